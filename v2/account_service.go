@@ -3,6 +3,8 @@ package binance
 import (
 	"context"
 	"net/http"
+
+	"github.com/vv1zard/go-binance/v2/common"
 )
 
 // GetAccountService get account info
@@ -12,12 +14,7 @@ type GetAccountService struct {
 
 // Do send request
 func (s *GetAccountService) Do(ctx context.Context, opts ...RequestOption) (res *Account, err error) {
-	r := &request{
-		method:   http.MethodGet,
-		endpoint: "/api/v3/account",
-		secType:  secTypeSigned,
-	}
-	data, err := s.c.callAPI(ctx, r, opts...)
+	data, err := s.c.callAPI(ctx, s.buildRequest(), opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -29,6 +26,14 @@ func (s *GetAccountService) Do(ctx context.Context, opts ...RequestOption) (res
 	return res, nil
 }
 
+func (s *GetAccountService) buildRequest() *request {
+	return &request{
+		method:   http.MethodGet,
+		endpoint: "/api/v3/account",
+		secType:  secTypeSigned,
+	}
+}
+
 // Account define account info
 type Account struct {
 	MakerCommission  int64     `json:"makerCommission"`
@@ -51,6 +56,58 @@ type Balance struct {
 	Locked string `json:"locked"`
 }
 
+// FreeDecimal parse Free as an exact decimal
+func (b *Balance) FreeDecimal() (common.Decimal, error) {
+	return common.ParseDecimal(b.Free)
+}
+
+// LockedDecimal parse Locked as an exact decimal
+func (b *Balance) LockedDecimal() (common.Decimal, error) {
+	return common.ParseDecimal(b.Locked)
+}
+
+// GetAccountCommissionService get the commission rates of the account on a
+// symbol
+type GetAccountCommissionService struct {
+	c      *Client
+	symbol string
+}
+
+// Symbol set symbol
+func (s *GetAccountCommissionService) Symbol(symbol string) *GetAccountCommissionService {
+	s.symbol = symbol
+	return s
+}
+
+// Do send request
+func (s *GetAccountCommissionService) Do(ctx context.Context, opts ...RequestOption) (res *AccountCommission, err error) {
+	r := &request{
+		method:   http.MethodGet,
+		endpoint: "/api/v3/account/commission",
+		secType:  secTypeSigned,
+	}
+	r.setParam("symbol", s.symbol)
+	data, err := s.c.callAPI(ctx, r, opts...)
+	if err != nil {
+		return nil, err
+	}
+	res = new(AccountCommission)
+	err = json.Unmarshal(data, res)
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// AccountCommission define the commission rates of the account on a symbol
+type AccountCommission struct {
+	Symbol             string              `json:"symbol"`
+	StandardCommission *CommissionRates    `json:"standardCommission"`
+	SpecialCommission  *CommissionRates    `json:"specialCommission"`
+	TaxCommission      *CommissionRates    `json:"taxCommission"`
+	Discount           *CommissionDiscount `json:"discount"`
+}
+
 // GetAccountSnapshotService all account orders; active, canceled, or filled
 type GetAccountSnapshotService struct {
 	c           *Client
@@ -94,6 +94,40 @@ func (s *accountServiceTestSuite) assertAccountEqual(e, a *Account) {
 	}
 }
 
+func (s *accountServiceTestSuite) TestGetAccountCommission() {
+	data := []byte(`{
+		"symbol": "BTCUSDT",
+		"standardCommission": {"maker": "0.00000010", "taker": "0.00000020", "buyer": "0.00000030", "seller": "0.00000040"},
+		"taxCommission": {"maker": "0.00000112", "taker": "0.00000114", "buyer": "0.00000118", "seller": "0.00000116"},
+		"discount": {
+			"enabledForAccount": true,
+			"enabledForSymbol": true,
+			"discountAsset": "BNB",
+			"discount": "0.75000000"
+		}
+	}`)
+	s.mockDo(data, nil)
+	defer s.assertDo()
+	s.assertReq(func(r *request) {
+		e := newSignedRequest().setParam("symbol", "BTCUSDT")
+		s.assertRequestEqual(e, r)
+	})
+
+	res, err := s.client.NewGetAccountCommissionService().Symbol("BTCUSDT").Do(newContext())
+	r := s.r()
+	r.NoError(err)
+	r.Equal("BTCUSDT", res.Symbol)
+	r.Equal(&CommissionRates{
+		Maker:  "0.00000010",
+		Taker:  "0.00000020",
+		Buyer:  "0.00000030",
+		Seller: "0.00000040",
+	}, res.StandardCommission)
+	r.Equal("0.00000118", res.TaxCommission.Buyer)
+	r.Nil(res.SpecialCommission)
+	r.Equal("0.75000000", res.Discount.Discount)
+}
+
 func (s *accountServiceTestSuite) TestGetAccountSnapshot() {
 	data := []byte(`{
 		"code":200,
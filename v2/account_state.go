@@ -0,0 +1,346 @@
+package binance
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/vv1zard/go-binance/v2/common"
+)
+
+// execution types of WsOrderUpdate
+const (
+	executionTypeNew   = "NEW"
+	executionTypeTrade = "TRADE"
+)
+
+// AccountState keeps the balances and open orders of the spot account in
+// sync with the user data stream. Events are buffered while the account and
+// the open orders are fetched, events older than the snapshot are dropped,
+// and a new snapshot is fetched whenever an event shows that previous ones
+// were missed, such as a trade filling more than the last known quantity.
+type AccountState struct {
+	// OnUpdate is called after every snapshot or event applied to the state
+	OnUpdate func(state *AccountState)
+
+	c          *Client
+	ctx        context.Context
+	errHandler ErrHandler
+	mu         sync.RWMutex
+	synced     bool
+	syncing    bool
+	// outdated is set when a snapshot being fetched may miss events
+	outdated bool
+	buffer   []*WsUserDataEvent
+	balances map[string]*stateBalance
+	orders   map[int64]*Order
+}
+
+// stateBalance is a balance and the time of its last update
+type stateBalance struct {
+	Balance
+	updateTime int64
+}
+
+// NewAccountState init an account state, call Serve to start syncing it
+func (c *Client) NewAccountState() *AccountState {
+	return &AccountState{
+		c:        c,
+		ctx:      context.Background(),
+		balances: make(map[string]*stateBalance),
+		orders:   make(map[int64]*Order),
+	}
+}
+
+// Serve start a user data session feeding the state until ctx is done. A
+// snapshot is fetched after every connection of the session since events
+// may be lost in between.
+func (s *AccountState) Serve(ctx context.Context, errHandler ErrHandler) (*common.UserDataSession, error) {
+	s.ctx = ctx
+	s.errHandler = errHandler
+	session := s.c.NewUserDataSession(s.HandleEvent, errHandler)
+	session.OnResync = s.Resync
+	return session, session.Start(ctx)
+}
+
+// Synced tell whether the state is currently in sync with the stream
+func (s *AccountState) Synced() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.synced
+}
+
+// Balance return the balance of asset
+func (s *AccountState) Balance(asset string) (Balance, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	b, ok := s.balances[asset]
+	if !ok {
+		return Balance{}, false
+	}
+	return b.Balance, true
+}
+
+// Balances return the balances of all assets, sorted by asset
+func (s *AccountState) Balances() []Balance {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	res := make([]Balance, 0, len(s.balances))
+	for _, b := range s.balances {
+		res = append(res, b.Balance)
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].Asset < res[j].Asset })
+	return res
+}
+
+// OpenOrders return the open orders of symbol, or of all symbols when symbol
+// is empty, sorted by order id
+func (s *AccountState) OpenOrders(symbol string) []Order {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	res := make([]Order, 0, len(s.orders))
+	for _, o := range s.orders {
+		if symbol == "" || o.Symbol == symbol {
+			res = append(res, *o)
+		}
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].OrderID < res[j].OrderID })
+	return res
+}
+
+// Resync fetch a new snapshot in the background, events are buffered and
+// replayed on top of it. Call it when events may have been lost, such as
+// after a reconnection.
+func (s *AccountState) Resync() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.startResync()
+}
+
+// startResync, s.mu must be held
+func (s *AccountState) startResync() {
+	s.synced = false
+	s.outdated = true
+	if !s.syncing {
+		s.syncing = true
+		go s.resync()
+	}
+}
+
+// HandleEvent apply a user data event, it is the handler of the session
+// started by Serve
+func (s *AccountState) HandleEvent(event *WsUserDataEvent) {
+	s.mu.Lock()
+	if !s.synced {
+		s.buffer = append(s.buffer, event)
+		if !s.syncing {
+			s.startResync()
+		}
+		s.mu.Unlock()
+		return
+	}
+	applied, err := s.applyEvent(event, false)
+	if err != nil {
+		s.buffer = []*WsUserDataEvent{event}
+		s.startResync()
+	}
+	s.mu.Unlock()
+	if err != nil {
+		s.error(err)
+		return
+	}
+	if applied {
+		s.notify()
+	}
+}
+
+// applyEvent apply an event on top of the state, s.mu must be held. While
+// replaying the buffer, updates of orders missing from the snapshot are
+// skipped since they ended before it.
+func (s *AccountState) applyEvent(event *WsUserDataEvent, replay bool) (applied bool, err error) {
+	switch event.Event {
+	case UserDataEventTypeOutboundAccountPosition:
+		for _, u := range event.AccountUpdate {
+			b, ok := s.balances[u.Asset]
+			if ok && event.AccountUpdateTime < b.updateTime {
+				continue
+			}
+			s.balances[u.Asset] = &stateBalance{
+				Balance:    Balance{Asset: u.Asset, Free: u.Free, Locked: u.Locked},
+				updateTime: event.AccountUpdateTime,
+			}
+			applied = true
+		}
+	case UserDataEventTypeBalanceUpdate:
+		u := event.BalanceUpdate
+		b, ok := s.balances[u.Asset]
+		if !ok {
+			b = &stateBalance{Balance: Balance{Asset: u.Asset, Free: "0", Locked: "0"}}
+			s.balances[u.Asset] = b
+		} else if event.TransactionTime <= b.updateTime {
+			return false, nil
+		}
+		free, err := common.ParseDecimal(b.Free)
+		if err != nil {
+			return false, err
+		}
+		change, err := common.ParseDecimal(u.Change)
+		if err != nil {
+			return false, err
+		}
+		b.Free = free.Add(change).String()
+		b.updateTime = event.TransactionTime
+		applied = true
+	case UserDataEventTypeExecutionReport:
+		return s.applyOrderUpdate(&event.OrderUpdate, replay)
+	}
+	return applied, nil
+}
+
+func (s *AccountState) applyOrderUpdate(u *WsOrderUpdate, replay bool) (applied bool, err error) {
+	known, ok := s.orders[u.Id]
+	if !ok {
+		if u.ExecutionType != executionTypeNew {
+			// The order was created before the stream, only a missed
+			// update of an order still open matters
+			if replay || !isOpenOrderStatus(OrderStatusType(u.Status)) {
+				return false, nil
+			}
+			return false, common.ErrAccountStateGap
+		}
+		known = &Order{Time: u.CreateTime}
+	} else {
+		if u.ExecutionType == executionTypeNew {
+			return false, nil
+		}
+		last := ""
+		if u.ExecutionType == executionTypeTrade {
+			last = u.LatestVolume
+		}
+		stale, err := common.CheckFill(known.ExecutedQuantity, u.FilledVolume, last)
+		if err != nil || stale {
+			return false, err
+		}
+	}
+	order := &Order{
+		Symbol:                   u.Symbol,
+		OrderID:                  u.Id,
+		OrderListId:              u.OrderListId,
+		ClientOrderID:            u.ClientOrderId,
+		Price:                    u.Price,
+		OrigQuantity:             u.Volume,
+		ExecutedQuantity:         u.FilledVolume,
+		CummulativeQuoteQuantity: u.FilledQuoteVolume,
+		Status:                   OrderStatusType(u.Status),
+		TimeInForce:              u.TimeInForce,
+		Type:                     OrderType(u.Type),
+		Side:                     SideType(u.Side),
+		StopPrice:                u.StopPrice,
+		IcebergQuantity:          u.IceBergVolume,
+		Time:                     known.Time,
+		UpdateTime:               u.TransactionTime,
+		IsWorking:                u.IsInOrderBook,
+		IsIsolated:               known.IsIsolated,
+		OrigQuoteOrderQuantity:   u.QuoteVolume,
+	}
+	if isOpenOrderStatus(order.Status) {
+		s.orders[order.OrderID] = order
+	} else {
+		delete(s.orders, order.OrderID)
+	}
+	return true, nil
+}
+
+// isOpenOrderStatus tell whether orders with status may still be filled
+func isOpenOrderStatus(status OrderStatusType) bool {
+	switch status {
+	case OrderStatusTypeFilled, OrderStatusTypeCanceled, OrderStatusTypeRejected, OrderStatusTypeExpired:
+		return false
+	}
+	return true
+}
+
+// resync fetch snapshots until the buffered events apply on top of one
+func (s *AccountState) resync() {
+	ctx := s.ctx
+	attempts := 0
+	for {
+		if attempts > 0 {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(common.Backoff(attempts, 100*time.Millisecond, 10*time.Second)):
+			}
+		}
+		attempts++
+		s.mu.Lock()
+		s.outdated = false
+		s.mu.Unlock()
+		account, err := s.c.NewGetAccountService().Do(ctx)
+		if err == nil {
+			var orders []*Order
+			orders, err = s.c.NewListOpenOrdersService().Do(ctx)
+			if err == nil {
+				var done bool
+				done, err = s.reset(account, orders)
+				if err == nil && !done {
+					// Resync was called again while fetching, events
+					// may be missing from this snapshot
+					attempts = 0
+					continue
+				}
+			}
+		}
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			s.error(err)
+			continue
+		}
+		s.notify()
+		return
+	}
+}
+
+// reset replace the state with a snapshot and replay the buffered events,
+// the snapshot is dropped when outdated
+func (s *AccountState) reset(account *Account, orders []*Order) (done bool, err error) {
+	s.mu.Lock()
+	if s.outdated {
+		s.mu.Unlock()
+		return false, nil
+	}
+	s.balances = make(map[string]*stateBalance, len(account.Balances))
+	for _, b := range account.Balances {
+		s.balances[b.Asset] = &stateBalance{Balance: b, updateTime: int64(account.UpdateTime)}
+	}
+	s.orders = make(map[int64]*Order, len(orders))
+	for _, o := range orders {
+		s.orders[o.OrderID] = o
+	}
+	for _, event := range s.buffer {
+		if _, err := s.applyEvent(event, true); err != nil {
+			s.mu.Unlock()
+			return false, err
+		}
+	}
+	s.buffer = nil
+	s.synced = true
+	s.syncing = false
+	s.mu.Unlock()
+	return true, nil
+}
+
+func (s *AccountState) notify() {
+	if s.OnUpdate != nil {
+		s.OnUpdate(s)
+	}
+}
+
+func (s *AccountState) error(err error) {
+	if s.errHandler != nil {
+		s.errHandler(err)
+	}
+}
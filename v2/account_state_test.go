@@ -0,0 +1,180 @@
+package binance
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/vv1zard/go-binance/v2/common"
+)
+
+type accountStateTestSuite struct {
+	baseTestSuite
+	origWsServe func(*WsConfig, WsHandler, ErrHandler) (chan struct{}, chan struct{}, error)
+	wsHandler   WsHandler
+	mu          sync.Mutex
+	account     string
+	openOrders  string
+	updates     chan struct{}
+	errs        chan error
+}
+
+func TestAccountState(t *testing.T) {
+	suite.Run(t, new(accountStateTestSuite))
+}
+
+func (s *accountStateTestSuite) SetupTest() {
+	s.baseTestSuite.SetupTest()
+	s.origWsServe = wsServe
+	wsServe = func(cfg *WsConfig, handler WsHandler, errHandler ErrHandler) (doneC, stopC chan struct{}, err error) {
+		s.mu.Lock()
+		s.wsHandler = handler
+		s.mu.Unlock()
+		doneC = make(chan struct{})
+		stopC = make(chan struct{})
+		go func() {
+			<-stopC
+			close(doneC)
+		}()
+		return doneC, stopC, nil
+	}
+	s.client.Client.do = func(req *http.Request) (*http.Response, error) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		switch req.URL.Path {
+		case "/api/v3/userDataStream":
+			return newHTTPResponse([]byte(`{"listenKey": "fakeListenKey"}`), http.StatusOK), nil
+		case "/api/v3/account":
+			return newHTTPResponse([]byte(s.account), http.StatusOK), nil
+		case "/api/v3/openOrders":
+			return newHTTPResponse([]byte(s.openOrders), http.StatusOK), nil
+		}
+		return newHTTPResponse([]byte(`{"code": -1000, "msg": "unexpected"}`), http.StatusBadRequest), nil
+	}
+	s.updates = make(chan struct{}, 10)
+	s.errs = make(chan error, 10)
+}
+
+func (s *accountStateTestSuite) TearDownTest() {
+	wsServe = s.origWsServe
+}
+
+func (s *accountStateTestSuite) setSnapshot(usdtFree, executed string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.account = fmt.Sprintf(`{
+		"updateTime": 100,
+		"balances": [
+			{"asset": "BTC", "free": "1", "locked": "0"},
+			{"asset": "USDT", "free": "%s", "locked": "100"}
+		]
+	}`, usdtFree)
+	s.openOrders = fmt.Sprintf(`[{
+		"symbol": "BTCUSDT", "orderId": 1, "orderListId": -1, "clientOrderId": "a",
+		"price": "100", "origQty": "1", "executedQty": "%s", "status": "NEW",
+		"timeInForce": "GTC", "type": "LIMIT", "side": "BUY", "time": 90, "updateTime": 90
+	}]`, executed)
+}
+
+func (s *accountStateTestSuite) send(message string) {
+	s.mu.Lock()
+	handler := s.wsHandler
+	s.mu.Unlock()
+	handler([]byte(message))
+}
+
+func (s *accountStateTestSuite) sendOrderUpdate(id int64, executionType, status, last, filled string, time int64) {
+	s.send(fmt.Sprintf(`{
+		"e": "executionReport", "E": %d, "s": "BTCUSDT", "c": "a", "S": "BUY", "o": "LIMIT",
+		"f": "GTC", "q": "1", "p": "100", "P": "0", "F": "0", "g": -1, "C": "",
+		"x": "%s", "X": "%s", "r": "NONE", "i": %d, "l": "%s", "z": "%s", "L": "100",
+		"n": "0", "N": null, "T": %d, "t": 1, "w": true, "m": false, "O": 90,
+		"Z": "0", "Y": "0", "Q": "0"
+	}`, time, executionType, status, id, last, filled, time))
+}
+
+func (s *accountStateTestSuite) waitUpdate() {
+	select {
+	case <-s.updates:
+	case <-time.After(time.Second):
+		s.FailNow("timed out waiting for state update")
+	}
+}
+
+func (s *accountStateTestSuite) TestSync() {
+	s.setSnapshot("1000", "0")
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	state := s.client.NewAccountState()
+	state.OnUpdate = func(state *AccountState) {
+		s.updates <- struct{}{}
+	}
+	session, err := state.Serve(ctx, func(err error) {
+		s.errs <- err
+	})
+	s.r().NoError(err)
+	s.waitUpdate()
+	s.r().True(state.Synced())
+	s.r().Equal([]Balance{
+		{Asset: "BTC", Free: "1", Locked: "0"},
+		{Asset: "USDT", Free: "1000", Locked: "100"},
+	}, state.Balances())
+	s.r().Len(state.OpenOrders("BTCUSDT"), 1)
+
+	// Stale balances are skipped
+	s.send(`{"e": "outboundAccountPosition", "E": 99, "u": 99, "B": [{"a": "BTC", "f": "0", "l": "0"}]}`)
+	s.send(`{"e": "outboundAccountPosition", "E": 110, "u": 110, "B": [{"a": "BTC", "f": "1.4", "l": "0"}]}`)
+	s.waitUpdate()
+	s.send(`{"e": "balanceUpdate", "E": 120, "a": "USDT", "d": "5.5", "T": 120}`)
+	s.waitUpdate()
+	// A position older than the balance update does not overwrite it
+	s.send(`{"e": "outboundAccountPosition", "E": 115, "u": 115, "B": [{"a": "USDT", "f": "1000", "l": "100"}]}`)
+	s.send(`{"e": "outboundAccountPosition", "E": 121, "u": 121, "B": [{"a": "BTC", "f": "1.4", "l": "0"}]}`)
+	s.waitUpdate()
+	btc, ok := state.Balance("BTC")
+	s.r().True(ok)
+	s.r().Equal("1.4", btc.Free)
+	usdt, _ := state.Balance("USDT")
+	s.r().Equal("1005.5", usdt.Free)
+
+	s.sendOrderUpdate(1, "TRADE", "PARTIALLY_FILLED", "0.4", "0.4", 130)
+	s.waitUpdate()
+	s.sendOrderUpdate(2, "NEW", "NEW", "0", "0", 131)
+	s.waitUpdate()
+	orders := state.OpenOrders("")
+	s.r().Len(orders, 2)
+	s.r().Equal("0.4", orders[0].ExecutedQuantity)
+	s.r().Equal(OrderStatusTypePartiallyFilled, orders[0].Status)
+	s.r().Equal(int64(90), orders[0].Time)
+	s.sendOrderUpdate(2, "CANCELED", "CANCELED", "0", "0", 132)
+	s.waitUpdate()
+	s.r().Len(state.OpenOrders(""), 1)
+
+	// A trade was missed, the state is fetched again
+	s.setSnapshot("2000", "0.9")
+	s.sendOrderUpdate(1, "TRADE", "PARTIALLY_FILLED", "0.2", "0.9", 140)
+	select {
+	case err := <-s.errs:
+		s.r().True(errors.Is(err, common.ErrAccountStateGap))
+	case <-time.After(time.Second):
+		s.FailNow("gap not reported")
+	}
+	s.waitUpdate()
+	orders = state.OpenOrders("BTCUSDT")
+	s.r().Len(orders, 1)
+	s.r().Equal("0.9", orders[0].ExecutedQuantity)
+	usdt, _ = state.Balance("USDT")
+	s.r().Equal("2000", usdt.Free)
+
+	cancel()
+	select {
+	case <-session.Done():
+	case <-time.After(time.Second):
+		s.FailNow("session not done")
+	}
+}
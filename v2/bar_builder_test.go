@@ -0,0 +1,57 @@
+package binance
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vv1zard/go-binance/v2/common"
+)
+
+func TestBackfillBars(t *testing.T) {
+	r := require.New(t)
+	c := NewClient("", "")
+	c.do = func(req *http.Request) (*http.Response, error) {
+		query := req.URL.Query()
+		r.Equal("/api/v3/klines", req.URL.Path)
+		r.Equal("1m", query.Get("interval"))
+		startTime, err := strconv.ParseInt(query.Get("startTime"), 10, 64)
+		r.NoError(err)
+		endTime, err := strconv.ParseInt(query.Get("endTime"), 10, 64)
+		r.NoError(err)
+		var klines []string
+		for open := startTime; open < endTime; open += time.Minute.Milliseconds() {
+			klines = append(klines, fmt.Sprintf(`[%d, "1", "2", "0.5", "1.5", "10", %d, "15", 3, "5", "7.5", "0"]`,
+				open, open+time.Minute.Milliseconds()-1))
+		}
+		return newHTTPResponse([]byte("["+strings.Join(klines, ",")+"]"), http.StatusOK), nil
+	}
+	var bars []common.Bar
+	b, err := common.NewTimeBarBuilder("1h", func(bar common.Bar) { bars = append(bars, bar) })
+	r.NoError(err)
+	start := time.Now().Truncate(time.Hour).Add(-2 * time.Hour)
+	r.NoError(c.BackfillBars(context.Background(), b, "BTCUSDT", start))
+	r.Len(bars, 2)
+	r.True(bars[1].IsFinal)
+	r.Equal("600", bars[1].Volume.String())
+	r.Equal(int64(180), bars[1].TradeNum)
+	// The current hour is in progress
+	if bar, ok := b.Current(); ok {
+		r.Equal(start.Add(2*time.Hour).UnixMilli(), bar.OpenTime)
+	}
+}
+
+func TestWsBarTrade(t *testing.T) {
+	r := require.New(t)
+	trade, err := (&WsAggTradeEvent{Price: "10.5", Quantity: "2", TradeTime: 1000, FirstBreakdownTradeID: 5, LastBreakdownTradeID: 7}).BarTrade()
+	r.NoError(err)
+	r.Equal(int64(3), trade.Count)
+	r.Equal("10.5", trade.Price.String())
+	_, err = (&WsTradeEvent{Price: "x", Quantity: "1"}).BarTrade()
+	r.Error(err)
+}
@@ -3,12 +3,11 @@ package binance
 import (
 	"bytes"
 	"context"
-	"crypto/hmac"
-	"crypto/sha256"
 	"crypto/tls"
 	"fmt"
 	"io/ioutil"
 	"log"
+	"log/slog"
 	"net/http"
 	"net/url"
 	"os"
@@ -97,6 +96,29 @@ type RateLimitType string
 // RateLimitInterval define the rate limitation intervals
 type RateLimitInterval string
 
+// CancelReplaceModeType define whether the new order of a cancel-replace is
+// placed when the cancel fails
+type CancelReplaceModeType string
+
+// CancelReplaceResultType define the result of each step of a cancel-replace
+type CancelReplaceResultType string
+
+// CancelRestrictionsType restrict the cancel to orders in a given status
+type CancelRestrictionsType string
+
+// SelfTradePreventionModeType define what is expired when an order would
+// match an order of the same account
+type SelfTradePreventionModeType string
+
+// ContingencyType define the type of an order list
+type ContingencyType string
+
+// ListStatusType define the status of an order list
+type ListStatusType string
+
+// ListOrderStatusType define the status of the orders of an order list
+type ListOrderStatusType string
+
 // Endpoints
 const (
 	baseAPIMainURL    = "https://api.binance.com"
@@ -159,7 +181,8 @@ const (
 	UserDataEventTypeOutboundAccountPosition UserDataEventType = "outboundAccountPosition"
 	UserDataEventTypeBalanceUpdate           UserDataEventType = "balanceUpdate"
 	UserDataEventTypeExecutionReport         UserDataEventType = "executionReport"
-	UserDataEventTypeListStatus              UserDataEventType = "ListStatus"
+	UserDataEventTypeListStatus              UserDataEventType = "listStatus"
+	UserDataEventTypeListenKeyExpired        UserDataEventType = "listenKeyExpired"
 
 	MarginTransferTypeToMargin MarginTransferType = 1
 	MarginTransferTypeToMain   MarginTransferType = 2
@@ -224,6 +247,34 @@ const (
 	RateLimitIntervalSecond RateLimitInterval = "SECOND"
 	RateLimitIntervalMinute RateLimitInterval = "MINUTE"
 	RateLimitIntervalDay    RateLimitInterval = "DAY"
+
+	CancelReplaceModeStopOnFailure CancelReplaceModeType = "STOP_ON_FAILURE"
+	CancelReplaceModeAllowFailure  CancelReplaceModeType = "ALLOW_FAILURE"
+
+	CancelReplaceResultSuccess      CancelReplaceResultType = "SUCCESS"
+	CancelReplaceResultFailure      CancelReplaceResultType = "FAILURE"
+	CancelReplaceResultNotAttempted CancelReplaceResultType = "NOT_ATTEMPTED"
+
+	CancelRestrictionsOnlyNew             CancelRestrictionsType = "ONLY_NEW"
+	CancelRestrictionsOnlyPartiallyFilled CancelRestrictionsType = "ONLY_PARTIALLY_FILLED"
+
+	SelfTradePreventionModeNone        SelfTradePreventionModeType = "NONE"
+	SelfTradePreventionModeExpireTaker SelfTradePreventionModeType = "EXPIRE_TAKER"
+	SelfTradePreventionModeExpireMaker SelfTradePreventionModeType = "EXPIRE_MAKER"
+	SelfTradePreventionModeExpireBoth  SelfTradePreventionModeType = "EXPIRE_BOTH"
+	SelfTradePreventionModeDecrement   SelfTradePreventionModeType = "DECREMENT"
+
+	ContingencyTypeOCO ContingencyType = "OCO"
+	ContingencyTypeOTO ContingencyType = "OTO"
+
+	ListStatusTypeResponse    ListStatusType = "RESPONSE"
+	ListStatusTypeExecStarted ListStatusType = "EXEC_STARTED"
+	ListStatusTypeUpdated     ListStatusType = "UPDATED"
+	ListStatusTypeAllDone     ListStatusType = "ALL_DONE"
+
+	ListOrderStatusTypeExecuting ListOrderStatusType = "EXECUTING"
+	ListOrderStatusTypeAllDone   ListOrderStatusType = "ALL_DONE"
+	ListOrderStatusTypeReject    ListOrderStatusType = "REJECT"
 )
 
 func currentTimestamp() int64 {
@@ -256,12 +307,13 @@ func getAPIEndpoint() string {
 // Services will be created by the form client.NewXXXService().
 func NewClient(apiKey, secretKey string) *Client {
 	return &Client{
-		APIKey:     apiKey,
-		SecretKey:  secretKey,
-		BaseURL:    getAPIEndpoint(),
-		UserAgent:  "Binance/golang",
-		HTTPClient: http.DefaultClient,
-		Logger:     log.New(os.Stderr, "Binance-golang ", log.LstdFlags),
+		APIKey:      apiKey,
+		SecretKey:   secretKey,
+		BaseURL:     getAPIEndpoint(),
+		UserAgent:   "Binance/golang",
+		HTTPClient:  http.DefaultClient,
+		Logger:      log.New(os.Stderr, "Binance-golang ", log.LstdFlags),
+		RateLimiter: common.NewRateLimiter(),
 	}
 }
 
@@ -283,7 +335,8 @@ func NewProxiedClient(apiKey, secretKey, proxyUrl string) *Client {
 		HTTPClient: &http.Client{
 			Transport: tr,
 		},
-		Logger: log.New(os.Stderr, "Binance-golang ", log.LstdFlags),
+		Logger:      log.New(os.Stderr, "Binance-golang ", log.LstdFlags),
+		RateLimiter: common.NewRateLimiter(),
 	}
 }
 
@@ -309,13 +362,51 @@ type Client struct {
 	Debug      bool
 	Logger     *log.Logger
 	TimeOffset int64
-	do         doFunc
-}
-
+	// Signer signs the requests, HMAC-SHA256 with SecretKey when nil
+	Signer common.Signer
+	// ClockSync, when set, replaces TimeOffset once synced and signed
+	// requests failing with -1021 are retried once after a resync
+	ClockSync *common.ClockSync
+	// RateLimiter throttles requests to the /api endpoints, it is seeded by
+	// ExchangeInfoService and can be set to nil to disable throttling
+	RateLimiter *common.RateLimiter
+	// Middlewares wrap the sending of every request, the first one is the
+	// outermost, see common.RetryMiddleware and common.LoggingMiddleware
+	Middlewares []common.Middleware
+	// LogHandler receives a record of every request, with secrets redacted
+	LogHandler slog.Handler
+	// Metrics receives the latency, status and used weight of every request
+	Metrics common.Metrics
+	do      doFunc
+}
+
+// debug log a line when Debug is set, with the signatures of the URLs and
+// bodies and the listen keys of the responses redacted
 func (c *Client) debug(format string, v ...interface{}) {
 	if c.Debug {
-		c.Logger.Printf(format, v...)
+		c.Logger.Print(common.RedactString(fmt.Sprintf(format, v...)))
+	}
+}
+
+// logger return the structured logger of the client, discarding records
+// when LogHandler is nil
+func (c *Client) logger() *slog.Logger {
+	return common.NewLogger(c.LogHandler)
+}
+
+func (c *Client) signer() common.Signer {
+	if c.Signer != nil {
+		return c.Signer
 	}
+	return common.NewHMACSigner(c.SecretKey)
+}
+
+// timeOffset return the offset measured by ClockSync once synced and TimeOffset otherwise
+func (c *Client) timeOffset() int64 {
+	if c.ClockSync != nil && c.ClockSync.Synced() {
+		return c.ClockSync.Offset()
+	}
+	return c.TimeOffset
 }
 
 func (c *Client) parseRequest(r *request, opts ...RequestOption) (err error) {
@@ -333,7 +424,7 @@ func (c *Client) parseRequest(r *request, opts ...RequestOption) (err error) {
 		r.setParam(recvWindowKey, r.recvWindow)
 	}
 	if r.secType == secTypeSigned {
-		r.setParam(timestampKey, currentTimestamp()-c.TimeOffset)
+		r.setParam(timestampKey, currentTimestamp()-c.timeOffset())
 	}
 	queryString := r.query.Encode()
 	body := &bytes.Buffer{}
@@ -352,13 +443,12 @@ func (c *Client) parseRequest(r *request, opts ...RequestOption) (err error) {
 
 	if r.secType == secTypeSigned {
 		raw := fmt.Sprintf("%s%s", queryString, bodyString)
-		mac := hmac.New(sha256.New, []byte(c.SecretKey))
-		_, err = mac.Write([]byte(raw))
+		signature, err := c.signer().Sign([]byte(raw))
 		if err != nil {
 			return err
 		}
 		v := url.Values{}
-		v.Set(signatureKey, fmt.Sprintf("%x", (mac.Sum(nil))))
+		v.Set(signatureKey, signature)
 		if queryString == "" {
 			queryString = v.Encode()
 		} else {
@@ -368,7 +458,7 @@ func (c *Client) parseRequest(r *request, opts ...RequestOption) (err error) {
 	if queryString != "" {
 		fullURL = fmt.Sprintf("%s?%s", fullURL, queryString)
 	}
-	c.debug("full url: %s, body: %s", fullURL, bodyString)
+	c.debug("full url: %s, body: %s", common.RedactString(fullURL), common.RedactString(bodyString))
 
 	r.fullURL = fullURL
 	r.header = header
@@ -377,25 +467,56 @@ func (c *Client) parseRequest(r *request, opts ...RequestOption) (err error) {
 }
 
 func (c *Client) callAPI(ctx context.Context, r *request, opts ...RequestOption) (data []byte, err error) {
+	data, err = c.send(ctx, r, opts...)
+	// Retry once with a fresh offset when the clock drifted out of the
+	// recvWindow, the options are already applied to r
+	if err != nil && r.secType == secTypeSigned && c.ClockSync != nil && common.IsInvalidTimestamp(err) {
+		if _, serr := c.ClockSync.Resync(ctx); serr == nil {
+			data, err = c.send(ctx, r)
+		}
+	}
+	return data, err
+}
+
+func (c *Client) send(ctx context.Context, r *request, opts ...RequestOption) (data []byte, err error) {
 	err = c.parseRequest(r, opts...)
 	if err != nil {
 		return []byte{}, err
 	}
+	weight, orders, limited := requestWeight(r)
+	limited = limited && c.RateLimiter != nil
+	if limited {
+		err = c.RateLimiter.Wait(ctx, weight, orders)
+		if err != nil {
+			return []byte{}, err
+		}
+	}
+	start, status := time.Now(), 0
+	var resHeader http.Header
+	defer func() {
+		latency := time.Since(start)
+		common.LogRequest(ctx, c.logger(), r.method, r.endpoint, weight, status, latency, err)
+		common.RecordRequest(c.Metrics, r.method, r.endpoint, status, resHeader, latency, err)
+	}()
 	req, err := http.NewRequest(r.method, r.fullURL, r.body)
 	if err != nil {
 		return []byte{}, err
 	}
 	req = req.WithContext(ctx)
 	req.Header = r.header
-	c.debug("request: %#v", req)
+	c.debug("request: %s %s", req.Method, common.RedactString(req.URL.String()))
 	f := c.do
 	if f == nil {
 		f = c.HTTPClient.Do
 	}
-	res, err := f(req)
+	res, err := common.Chain(common.Doer(f), c.Middlewares...)(req)
 	if err != nil {
 		return []byte{}, err
 	}
+	status, resHeader = res.StatusCode, res.Header
+	if limited {
+		c.RateLimiter.Update(res.StatusCode, res.Header)
+	}
 	data, err = ioutil.ReadAll(res.Body)
 	if err != nil {
 		return []byte{}, err
@@ -409,7 +530,7 @@ func (c *Client) callAPI(ctx context.Context, r *request, opts ...RequestOption)
 		}
 	}()
 	c.debug("response: %#v", res)
-	c.debug("response body: %s", string(data))
+	c.debug("response body: %s", common.RedactString(string(data)))
 	c.debug("response status code: %d", res.StatusCode)
 
 	if res.StatusCode >= http.StatusBadRequest {
@@ -418,6 +539,13 @@ func (c *Client) callAPI(ctx context.Context, r *request, opts ...RequestOption)
 		if e != nil {
 			c.debug("failed to unmarshal json: %s", e)
 		}
+		apiErr.StatusCode = res.StatusCode
+		apiErr.Method = r.method
+		apiErr.Endpoint = r.endpoint
+		apiErr.Weight = weight
+		if r.keepErrorBody {
+			return data, apiErr
+		}
 		return nil, apiErr
 	}
 	return data, nil
@@ -503,6 +631,56 @@ func (c *Client) NewCancelOrderService() *CancelOrderService {
 	return &CancelOrderService{c: c}
 }
 
+// NewCreateOrderListOCOService init creating order list OCO service
+func (c *Client) NewCreateOrderListOCOService() *CreateOrderListOCOService {
+	return &CreateOrderListOCOService{c: c}
+}
+
+// NewCreateOrderListOTOService init creating order list OTO service
+func (c *Client) NewCreateOrderListOTOService() *CreateOrderListOTOService {
+	return &CreateOrderListOTOService{c: c}
+}
+
+// NewCreateOrderListOTOCOService init creating order list OTOCO service
+func (c *Client) NewCreateOrderListOTOCOService() *CreateOrderListOTOCOService {
+	return &CreateOrderListOTOCOService{c: c}
+}
+
+// NewGetOrderListService init get order list service
+func (c *Client) NewGetOrderListService() *GetOrderListService {
+	return &GetOrderListService{c: c}
+}
+
+// NewListOrderListsService init list order lists service
+func (c *Client) NewListOrderListsService() *ListOrderListsService {
+	return &ListOrderListsService{c: c}
+}
+
+// NewListPreventedMatchesService init list prevented matches service
+func (c *Client) NewListPreventedMatchesService() *ListPreventedMatchesService {
+	return &ListPreventedMatchesService{c: c}
+}
+
+// NewGetAccountCommissionService init get account commission service
+func (c *Client) NewGetAccountCommissionService() *GetAccountCommissionService {
+	return &GetAccountCommissionService{c: c}
+}
+
+// NewCreateSOROrderService init creating SOR order service
+func (c *Client) NewCreateSOROrderService() *CreateSOROrderService {
+	return &CreateSOROrderService{c: c}
+}
+
+// NewListAllocationsService init list allocations service
+func (c *Client) NewListAllocationsService() *ListAllocationsService {
+	return &ListAllocationsService{c: c}
+}
+
+// NewCancelReplaceOrderService init cancel replace order service
+func (c *Client) NewCancelReplaceOrderService() *CancelReplaceOrderService {
+	return &CancelReplaceOrderService{c: c, order: CreateOrderService{c: c}}
+}
+
 // NewCancelOpenOrdersService init cancel open orders service
 func (c *Client) NewCancelOpenOrdersService() *CancelOpenOrdersService {
 	return &CancelOpenOrdersService{c: c}
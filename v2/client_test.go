@@ -3,9 +3,16 @@ package binance
 import (
 	"bytes"
 	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
 	"io/ioutil"
+	"log"
+	"log/slog"
 	"net/http"
 	"net/url"
+	"sync"
 	"testing"
 	"time"
 
@@ -13,6 +20,7 @@ import (
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
+	"github.com/vv1zard/go-binance/v2/common"
 )
 
 type baseTestSuite struct {
@@ -167,3 +175,180 @@ func TestFormatTimestamp(t *testing.T) {
 	tm, _ := time.Parse("2006-01-02 15:04:05", "2018-06-01 01:01:01")
 	assert.Equal(t, int64(1527814861000), FormatTimestamp(tm))
 }
+
+func TestParseRequestSigner(t *testing.T) {
+	r := require.New(t)
+	pub, key, err := ed25519.GenerateKey(rand.Reader)
+	r.NoError(err)
+	c := NewClient("dummyAPIKey", "")
+	c.Signer = &common.Ed25519Signer{PrivateKey: key}
+	req := &request{method: http.MethodPost, endpoint: "/api/v3/order", secType: secTypeSigned}
+	req.setFormParam("symbol", "BTCUSDT")
+	r.NoError(c.parseRequest(req))
+
+	u, err := url.Parse(req.fullURL)
+	r.NoError(err)
+	query := u.Query()
+	signature, err := base64.StdEncoding.DecodeString(query.Get(signatureKey))
+	r.NoError(err)
+	query.Del(signatureKey)
+	payload := query.Encode() + "symbol=BTCUSDT"
+	r.True(ed25519.Verify(pub, []byte(payload), signature))
+	r.Equal("dummyAPIKey", req.header.Get("X-MBX-APIKEY"))
+}
+
+func TestMiddlewares(t *testing.T) {
+	r := require.New(t)
+	c := NewClient("dummyAPIKey", "dummySecretKey")
+	attempts := 0
+	c.do = func(req *http.Request) (*http.Response, error) {
+		attempts++
+		r.Equal("trace-1", req.Header.Get("X-Trace-Id"))
+		r.NotEmpty(req.URL.Query().Get(signatureKey))
+		if attempts == 1 {
+			return newHTTPResponse([]byte(`{"code": -1001, "msg": "Internal error"}`), http.StatusServiceUnavailable), nil
+		}
+		return newHTTPResponse([]byte(`{"makerCommission": 15}`), http.StatusOK), nil
+	}
+	c.Middlewares = []common.Middleware{
+		common.RetryMiddleware(&common.RetryConfig{MaxRetries: 1}),
+		func(next common.Doer) common.Doer {
+			return func(req *http.Request) (*http.Response, error) {
+				req.Header.Set("X-Trace-Id", "trace-1")
+				return next(req)
+			}
+		},
+	}
+	account, err := c.NewGetAccountService().Do(context.Background())
+	r.NoError(err)
+	r.Equal(int64(15), account.MakerCommission)
+	r.Equal(2, attempts)
+}
+
+func TestAPIErrorBody(t *testing.T) {
+	r := require.New(t)
+	c := NewClient("dummyAPIKey", "dummySecretKey")
+	c.do = func(req *http.Request) (*http.Response, error) {
+		return newHTTPResponse([]byte(`{"code": -2013, "msg": "Order does not exist."}`), http.StatusBadRequest), nil
+	}
+	// The body of a failed request is only returned when the request asks for it
+	data, err := c.callAPI(context.Background(), &request{method: http.MethodGet, endpoint: "/api/v3/order"})
+	r.True(common.IsAPIError(err))
+	r.Nil(data)
+	data, err = c.callAPI(context.Background(), &request{method: http.MethodGet, endpoint: "/api/v3/order", keepErrorBody: true})
+	r.True(common.IsAPIError(err))
+	r.JSONEq(`{"code": -2013, "msg": "Order does not exist."}`, string(data))
+}
+
+func TestLogHandler(t *testing.T) {
+	r := require.New(t)
+	buf := new(bytes.Buffer)
+	c := NewClient("dummyAPIKey", "dummySecretKey")
+	c.LogHandler = slog.NewTextHandler(buf, &slog.HandlerOptions{Level: slog.LevelDebug})
+	c.do = func(req *http.Request) (*http.Response, error) {
+		return newHTTPResponse([]byte(`{"code": -2013, "msg": "Order does not exist."}`), http.StatusBadRequest), nil
+	}
+	_, err := c.NewGetOrderService().Symbol("BTCUSDT").OrderID(1).Do(context.Background())
+	r.Error(err)
+	out := buf.String()
+	r.Contains(out, `level=WARN msg="binance request" method=GET endpoint=/api/v3/order weight=4`)
+	r.Contains(out, `status=400 code=-2013 msg="Order does not exist."`)
+	r.NotContains(out, "dummyAPIKey")
+}
+
+func TestDebugRedacted(t *testing.T) {
+	r := require.New(t)
+	buf := new(bytes.Buffer)
+	listenKey := "pqia91ma19a5s61cv6a81va65sdf19v8a65a1a5s61cv6a81va65sdf19v8a65a1"
+	c := NewClient("dummyAPIKey", "dummySecretKey")
+	c.Debug = true
+	c.Logger = log.New(buf, "", 0)
+	c.do = func(req *http.Request) (*http.Response, error) {
+		if req.URL.Path == "/api/v3/userDataStream" {
+			return newHTTPResponse([]byte(`{"listenKey": "`+listenKey+`"}`), http.StatusOK), nil
+		}
+		return newHTTPResponse([]byte(`{}`), http.StatusOK), nil
+	}
+	_, err := c.NewGetAccountService().Do(context.Background())
+	r.NoError(err)
+	_, err = c.NewCreateOrderService().Symbol("BTCUSDT").Side(SideTypeBuy).
+		Type(OrderTypeMarket).Quantity("1").Do(context.Background())
+	r.NoError(err)
+	key, err := c.NewStartUserStreamService().Do(context.Background())
+	r.NoError(err)
+	r.Equal(listenKey, key)
+
+	out := buf.String()
+	r.Contains(out, "full url: ")
+	r.Contains(out, "signature=REDACTED")
+	r.NotRegexp(`signature=[0-9a-f]`, out)
+	r.NotContains(out, listenKey)
+}
+
+// countMetrics count the measurements it receives by kind
+type countMetrics struct {
+	mu         sync.Mutex
+	requests   []string
+	weights    map[string]int64
+	messages   map[string]int
+	lags       int
+	reconnects int
+}
+
+func (m *countMetrics) ObserveRequest(method, endpoint string, status int, code int64, latency time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.requests = append(m.requests, fmt.Sprintf("%s %s %d %d", method, endpoint, status, code))
+}
+
+func (m *countMetrics) SetUsedWeight(interval string, weight int64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.weights == nil {
+		m.weights = make(map[string]int64)
+	}
+	m.weights[interval] = weight
+}
+
+func (m *countMetrics) ObserveMessage(stream string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.messages == nil {
+		m.messages = make(map[string]int)
+	}
+	m.messages[stream]++
+}
+
+func (m *countMetrics) ObserveLag(stream string, lag time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.lags++
+}
+
+func (m *countMetrics) ObserveReconnect(stream string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.reconnects++
+}
+
+func TestMetrics(t *testing.T) {
+	r := require.New(t)
+	m := new(countMetrics)
+	c := NewClient("dummyAPIKey", "dummySecretKey")
+	c.Metrics = m
+	c.do = func(req *http.Request) (*http.Response, error) {
+		if req.URL.Path == "/api/v3/order" {
+			return newHTTPResponse([]byte(`{"code": -2013, "msg": "Order does not exist."}`), http.StatusBadRequest), nil
+		}
+		res := newHTTPResponse([]byte(`{"makerCommission": 15}`), http.StatusOK)
+		res.Header = http.Header{}
+		res.Header.Set("X-Mbx-Used-Weight-1m", "20")
+		return res, nil
+	}
+	_, err := c.NewGetAccountService().Do(context.Background())
+	r.NoError(err)
+	_, err = c.NewGetOrderService().Symbol("BTCUSDT").OrderID(1).Do(context.Background())
+	r.Error(err)
+	r.Equal([]string{"GET /api/v3/account 200 0", "GET /api/v3/order 400 -2013"}, m.requests)
+	r.Equal(map[string]int64{"1m": 20}, m.weights)
+}
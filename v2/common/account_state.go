@@ -0,0 +1,47 @@
+package common
+
+import (
+	"errors"
+)
+
+// ErrAccountStateGap is reported when a user data event shows that previous
+// events were missed, the account state has to be fetched again
+var ErrAccountStateGap = errors.New("account state missed user data events")
+
+// CheckFill compare the executed quantity known for an order with an order
+// update reporting filled in total, last of which were filled by the update
+// itself, last is empty for updates which are not trades. The update is
+// stale when the known quantity already includes it, and ErrAccountStateGap
+// is returned when trades between the two were missed. Empty quantities are
+// read as 0.
+func CheckFill(executed, filled, last string) (stale bool, err error) {
+	parse := func(s string) (Decimal, error) {
+		if s == "" {
+			return Decimal{}, nil
+		}
+		return ParseDecimal(s)
+	}
+	known, err := parse(executed)
+	if err != nil {
+		return false, err
+	}
+	total, err := parse(filled)
+	if err != nil {
+		return false, err
+	}
+	trade, err := parse(last)
+	if err != nil {
+		return false, err
+	}
+	switch c := total.Cmp(known); {
+	case c < 0:
+		return true, nil
+	case c == 0:
+		// A trade already counted, or an update which does not fill
+		return trade.Sign() > 0, nil
+	}
+	if !total.Sub(trade).Equal(known) {
+		return false, ErrAccountStateGap
+	}
+	return false, nil
+}
@@ -0,0 +1,31 @@
+package common
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckFill(t *testing.T) {
+	assert := assert.New(t)
+	for _, c := range []struct {
+		executed, filled, last string
+		stale                  bool
+		err                    error
+	}{
+		{"", "0", "", false, nil},
+		{"0", "1.5", "1.5", false, nil},
+		{"1.5", "2.00", "0.5", false, nil},
+		{"2", "2", "0.5", true, nil},
+		{"2", "1.5", "1.5", true, nil},
+		{"2", "2", "", false, nil},
+		{"1", "3", "1", false, ErrAccountStateGap},
+		{"0", "1", "", false, ErrAccountStateGap},
+	} {
+		stale, err := CheckFill(c.executed, c.filled, c.last)
+		assert.Equal(c.err, err, "%+v", c)
+		assert.Equal(c.stale, stale, "%+v", c)
+	}
+	_, err := CheckFill("1", "x", "")
+	assert.Error(err)
+}
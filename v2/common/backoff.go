@@ -0,0 +1,28 @@
+package common
+
+import (
+	"math/rand"
+	"time"
+)
+
+// Backoff returns the delay to wait before the given retry attempt (starting
+// at 1). The delay doubles from min on every attempt, is capped at max and
+// randomized over its upper half so that many clients retrying at the same
+// time do not hit the server in lockstep.
+func Backoff(attempt int, min, max time.Duration) time.Duration {
+	if attempt < 1 {
+		attempt = 1
+	}
+	if min <= 0 {
+		return 0
+	}
+	d := min
+	for i := 1; i < attempt && d < max; i++ {
+		d *= 2
+	}
+	if max > 0 && d > max {
+		d = max
+	}
+	half := d / 2
+	return half + time.Duration(rand.Int63n(int64(d-half)+1))
+}
@@ -0,0 +1,35 @@
+package common
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBackoff(t *testing.T) {
+	assert := assert.New(t)
+	min := 100 * time.Millisecond
+	max := time.Second
+	tests := []struct {
+		name    string
+		attempt int
+		want    time.Duration
+	}{
+		{name: "first attempt", attempt: 1, want: min},
+		{name: "zero attempt", attempt: 0, want: min},
+		{name: "third attempt", attempt: 3, want: 4 * min},
+		{name: "capped at max", attempt: 10, want: max},
+		{name: "large attempt", attempt: 1000, want: max},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for i := 0; i < 100; i++ {
+				d := Backoff(tt.attempt, min, max)
+				assert.GreaterOrEqual(int64(d), int64(tt.want/2))
+				assert.LessOrEqual(int64(d), int64(tt.want))
+			}
+		})
+	}
+	assert.Equal(time.Duration(0), Backoff(1, 0, max))
+}
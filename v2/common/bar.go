@@ -0,0 +1,291 @@
+package common
+
+import (
+	"errors"
+)
+
+// BarKind define when a bar closes
+type BarKind int
+
+// Global enums
+const (
+	// TimeBar close at the end of a fixed interval aligned on UTC
+	TimeBar BarKind = iota
+	// VolumeBar close once its volume reaches the threshold
+	VolumeBar
+	// TickBar close once its number of trades reaches the threshold
+	TickBar
+	// DollarBar close once its quote volume reaches the threshold
+	DollarBar
+)
+
+// ErrBarThreshold is returned for a volume, tick or dollar bar without a
+// positive threshold
+var ErrBarThreshold = errors.New("bar threshold must be positive")
+
+// Bar is a candle built by a BarBuilder. The open and close times of a time
+// bar are the bounds of its interval, like the klines of Binance, those of
+// the other bars are the times of their first and last trades.
+type Bar struct {
+	OpenTime    int64   `json:"openTime"`
+	CloseTime   int64   `json:"closeTime"`
+	Open        Decimal `json:"open"`
+	High        Decimal `json:"high"`
+	Low         Decimal `json:"low"`
+	Close       Decimal `json:"close"`
+	Volume      Decimal `json:"volume"`
+	QuoteVolume Decimal `json:"quoteVolume"`
+	TradeNum    int64   `json:"tradeNum"`
+	IsFinal     bool    `json:"isFinal"`
+}
+
+// BarTrade is a trade fed to a BarBuilder, Count is the number of trades it
+// aggregates, 1 when zero
+type BarTrade struct {
+	Time     int64
+	Price    Decimal
+	Quantity Decimal
+	Count    int64
+}
+
+// BarHandler receive the bars of a BarBuilder
+type BarHandler func(bar Bar)
+
+// BarBuilder build bars from trades or from the closes of 1m klines, the
+// inputs must come in time order. A time bar is emitted once the kline
+// ending its interval closes, or once an input or Advance passes its end;
+// an interval without trades emits no bar. A volume, tick or dollar bar
+// takes whole inputs, so the input crossing the threshold closes it and its
+// size may exceed the threshold.
+//
+// Inputs older than the last one are dropped, so that the live stream can
+// start before a backfill ends. A BarBuilder is not safe for concurrent use.
+type BarBuilder struct {
+	// OnBar receive the closed bars, and the bar in progress after every
+	// input when Partial is set
+	OnBar   BarHandler
+	Partial bool
+
+	kind      BarKind
+	interval  KlineInterval
+	threshold Decimal
+	bar       *Bar
+	size      Decimal
+	// last is the time of the last input, klineEnd the close time of the
+	// last final kline
+	last     int64
+	klineEnd int64
+}
+
+// barInput is a trade or a kline merged into a bar
+type barInput struct {
+	openTime, closeTime    int64
+	open, high, low, close Decimal
+	volume, quoteVolume    Decimal
+	tradeNum               int64
+	kline                  bool
+}
+
+// NewTimeBarBuilder create a builder of bars lasting interval, such as 2m,
+// 45m or 3h. When fed with klines, interval must be a multiple of theirs.
+func NewTimeBarBuilder(interval string, handler BarHandler) (*BarBuilder, error) {
+	iv, err := ParseKlineInterval(interval)
+	if err != nil {
+		return nil, err
+	}
+	return &BarBuilder{OnBar: handler, kind: TimeBar, interval: iv, last: -1, klineEnd: -1}, nil
+}
+
+// NewThresholdBarBuilder create a volume, tick or dollar builder closing its
+// bars once they reach threshold
+func NewThresholdBarBuilder(kind BarKind, threshold Decimal, handler BarHandler) (*BarBuilder, error) {
+	if kind == TimeBar || threshold.Sign() <= 0 {
+		return nil, ErrBarThreshold
+	}
+	return &BarBuilder{OnBar: handler, kind: kind, threshold: threshold, last: -1, klineEnd: -1}, nil
+}
+
+// Kind return the kind of the bars built
+func (b *BarBuilder) Kind() BarKind {
+	return b.kind
+}
+
+// Current return the bar in progress, false when there is none
+func (b *BarBuilder) Current() (Bar, bool) {
+	if b.bar == nil {
+		return Bar{}, false
+	}
+	return *b.bar, true
+}
+
+// AddTrade merge a trade into the bar in progress
+func (b *BarBuilder) AddTrade(t BarTrade) {
+	if t.Time < b.last || t.Time <= b.klineEnd {
+		return
+	}
+	count := t.Count
+	if count <= 0 {
+		count = 1
+	}
+	b.add(barInput{
+		openTime:    t.Time,
+		closeTime:   t.Time,
+		open:        t.Price,
+		high:        t.Price,
+		low:         t.Price,
+		close:       t.Price,
+		volume:      t.Quantity,
+		quoteVolume: t.Price.Mul(t.Quantity),
+		tradeNum:    count,
+	})
+}
+
+// AddKline merge a kline into the bar in progress. Only final klines are
+// merged, an open kline only updates the partial bar when Partial is set.
+func (b *BarBuilder) AddKline(k *Kline, final bool) error {
+	if k.OpenTime <= b.klineEnd || k.OpenTime < b.last {
+		return nil
+	}
+	in := barInput{openTime: k.OpenTime, closeTime: k.CloseTime, tradeNum: k.TradeNum, kline: true}
+	for _, f := range []struct {
+		dst *Decimal
+		s   string
+	}{
+		{&in.open, k.Open}, {&in.high, k.High}, {&in.low, k.Low}, {&in.close, k.Close},
+		{&in.volume, k.Volume}, {&in.quoteVolume, k.QuoteAssetVolume},
+	} {
+		d, err := ParseDecimal(f.s)
+		if err != nil {
+			return err
+		}
+		*f.dst = d
+	}
+	if !final {
+		if b.Partial {
+			b.emitPartial(in)
+		}
+		return nil
+	}
+	b.klineEnd = k.CloseTime
+	b.add(in)
+	return nil
+}
+
+// Backfill merge final klines, such as those of a KlineSeries, in order
+func (b *BarBuilder) Backfill(klines []*Kline) error {
+	for _, k := range klines {
+		if err := b.AddKline(k, true); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Advance close the time bar in progress once now, in ms, is past its end,
+// for intervals ending without trades
+func (b *BarBuilder) Advance(now int64) {
+	if b.kind == TimeBar && b.bar != nil && now > b.bar.CloseTime {
+		b.close()
+	}
+}
+
+func (b *BarBuilder) add(in barInput) {
+	b.last = in.openTime
+	if b.kind == TimeBar {
+		start := b.interval.Truncate(in.openTime)
+		if b.bar != nil && start > b.bar.OpenTime {
+			b.close()
+		}
+		first := b.bar == nil
+		if first {
+			b.bar = &Bar{OpenTime: start, CloseTime: b.interval.Next(start) - 1}
+		}
+		mergeBar(b.bar, in, first)
+		// The kline ending the interval closes the bar
+		if in.kline && in.closeTime >= b.bar.CloseTime {
+			b.close()
+		} else if b.Partial {
+			b.emitPartial(barInput{})
+		}
+		return
+	}
+	first := b.bar == nil
+	if first {
+		b.bar = &Bar{OpenTime: in.openTime}
+	}
+	mergeBar(b.bar, in, first)
+	b.bar.CloseTime = in.closeTime
+	switch b.kind {
+	case VolumeBar:
+		b.size = b.size.Add(in.volume)
+	case DollarBar:
+		b.size = b.size.Add(in.quoteVolume)
+	case TickBar:
+		b.size = NewDecimal(b.bar.TradeNum, 0)
+	}
+	if b.size.Cmp(b.threshold) >= 0 {
+		b.close()
+	} else if b.Partial {
+		b.emitPartial(barInput{})
+	}
+}
+
+// mergeBar add in to bar, first being the first input of the bar
+func mergeBar(bar *Bar, in barInput, first bool) {
+	if first {
+		bar.Open, bar.High, bar.Low = in.open, in.high, in.low
+	} else {
+		if in.high.Cmp(bar.High) > 0 {
+			bar.High = in.high
+		}
+		if in.low.Cmp(bar.Low) < 0 {
+			bar.Low = in.low
+		}
+	}
+	bar.Close = in.close
+	bar.Volume = bar.Volume.Add(in.volume)
+	bar.QuoteVolume = bar.QuoteVolume.Add(in.quoteVolume)
+	bar.TradeNum += in.tradeNum
+}
+
+// emitPartial send the bar in progress merged with an open kline, which is
+// not kept
+func (b *BarBuilder) emitPartial(open barInput) {
+	if b.OnBar == nil {
+		return
+	}
+	if !open.kline {
+		if b.bar != nil {
+			b.OnBar(*b.bar)
+		}
+		return
+	}
+	var bar Bar
+	first := b.bar == nil
+	if !first {
+		bar = *b.bar
+	}
+	if b.kind == TimeBar {
+		// The open kline may start the next interval
+		if start := b.interval.Truncate(open.openTime); first || start > bar.OpenTime {
+			bar, first = Bar{OpenTime: start, CloseTime: b.interval.Next(start) - 1}, true
+		}
+	} else if first {
+		bar.OpenTime = open.openTime
+	}
+	mergeBar(&bar, open, first)
+	if b.kind != TimeBar {
+		bar.CloseTime = open.closeTime
+	}
+	b.OnBar(bar)
+}
+
+func (b *BarBuilder) close() {
+	bar := *b.bar
+	bar.IsFinal = true
+	b.bar = nil
+	b.size = Decimal{}
+	if b.OnBar != nil {
+		b.OnBar(bar)
+	}
+}
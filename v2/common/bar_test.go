@@ -0,0 +1,160 @@
+package common
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func barTrade(t int64, price, quantity string) BarTrade {
+	return BarTrade{Time: t, Price: MustParseDecimal(price), Quantity: MustParseDecimal(quantity)}
+}
+
+func barKline(open int64, o, h, l, c, volume string, final bool) (*Kline, bool) {
+	return &Kline{
+		OpenTime:         open,
+		Open:             o,
+		High:             h,
+		Low:              l,
+		Close:            c,
+		Volume:           volume,
+		CloseTime:        open + time.Minute.Milliseconds() - 1,
+		QuoteAssetVolume: volume,
+		TradeNum:         1,
+	}, final
+}
+
+func TestTimeBarTrades(t *testing.T) {
+	assert := assert.New(t)
+	r := require.New(t)
+	var bars []Bar
+	b, err := NewTimeBarBuilder("45m", func(bar Bar) { bars = append(bars, bar) })
+	r.NoError(err)
+
+	b.AddTrade(barTrade(ms("2024-03-05T11:20:00Z"), "10", "1"))
+	b.AddTrade(barTrade(ms("2024-03-05T11:30:00Z"), "12", "2"))
+	b.AddTrade(barTrade(ms("2024-03-05T11:40:00Z"), "9", "1"))
+	// Older trades are dropped
+	b.AddTrade(barTrade(ms("2024-03-05T11:10:00Z"), "1", "1"))
+	r.Empty(bars)
+
+	b.AddTrade(barTrade(ms("2024-03-05T12:00:00Z"), "11", "1"))
+	r.Len(bars, 1)
+	bar := bars[0]
+	assert.True(bar.IsFinal)
+	assert.Equal(ms("2024-03-05T11:15:00Z"), bar.OpenTime)
+	assert.Equal(ms("2024-03-05T12:00:00Z")-1, bar.CloseTime)
+	assert.Equal("10", bar.Open.String())
+	assert.Equal("12", bar.High.String())
+	assert.Equal("9", bar.Low.String())
+	assert.Equal("9", bar.Close.String())
+	assert.Equal("4", bar.Volume.String())
+	assert.Equal("43", bar.QuoteVolume.String())
+	assert.Equal(int64(3), bar.TradeNum)
+
+	// Advance closes the bar without waiting for the next trade
+	b.Advance(ms("2024-03-05T12:44:59.999Z"))
+	r.Len(bars, 1)
+	b.Advance(ms("2024-03-05T12:45:00Z"))
+	r.Len(bars, 2)
+	assert.Equal(ms("2024-03-05T12:00:00Z"), bars[1].OpenTime)
+	_, ok := b.Current()
+	assert.False(ok)
+}
+
+func TestTimeBarKlines(t *testing.T) {
+	assert := assert.New(t)
+	r := require.New(t)
+	var bars []Bar
+	b, err := NewTimeBarBuilder("3m", func(bar Bar) { bars = append(bars, bar) })
+	r.NoError(err)
+	b.Partial = true
+	start := ms("2024-03-05T11:00:00Z")
+	minute := time.Minute.Milliseconds()
+
+	r.NoError(b.Backfill([]*Kline{
+		{OpenTime: start, Open: "1", High: "3", Low: "1", Close: "2", Volume: "1", QuoteAssetVolume: "2", CloseTime: start + minute - 1, TradeNum: 2},
+		{OpenTime: start + minute, Open: "2", High: "4", Low: "2", Close: "3", Volume: "1", QuoteAssetVolume: "3", CloseTime: start + 2*minute - 1, TradeNum: 1},
+	}))
+	r.Len(bars, 2)
+	assert.False(bars[1].IsFinal)
+	assert.Equal("4", bars[1].High.String())
+
+	// An open kline updates the partial bar only
+	r.NoError(b.AddKline(barKline(start+2*minute, "3", "5", "3", "5", "1", false)))
+	r.Len(bars, 3)
+	assert.False(bars[2].IsFinal)
+	assert.Equal("5", bars[2].Close.String())
+	cur, ok := b.Current()
+	r.True(ok)
+	assert.Equal("3", cur.Close.String())
+
+	// The kline ending the interval closes the bar
+	r.NoError(b.AddKline(barKline(start+2*minute, "3", "6", "0.5", "4", "1", true)))
+	r.Len(bars, 4)
+	bar := bars[3]
+	assert.True(bar.IsFinal)
+	assert.Equal(start, bar.OpenTime)
+	assert.Equal(start+3*minute-1, bar.CloseTime)
+	assert.Equal("1", bar.Open.String())
+	assert.Equal("6", bar.High.String())
+	assert.Equal("0.5", bar.Low.String())
+	assert.Equal("4", bar.Close.String())
+	assert.Equal("3", bar.Volume.String())
+	assert.Equal(int64(4), bar.TradeNum)
+
+	// The same kline from the live stream is dropped
+	r.NoError(b.AddKline(barKline(start+2*minute, "3", "6", "0.5", "4", "1", true)))
+	r.Len(bars, 4)
+	// So are the trades it covers
+	b.AddTrade(barTrade(start+3*minute-1, "4", "1"))
+	_, ok = b.Current()
+	assert.False(ok)
+
+	// An open kline of the next interval starts a new partial bar
+	r.NoError(b.AddKline(barKline(start+3*minute, "4", "4", "4", "4", "1", false)))
+	r.Len(bars, 5)
+	assert.Equal(start+3*minute, bars[4].OpenTime)
+	assert.Equal("4", bars[4].Open.String())
+}
+
+func TestThresholdBars(t *testing.T) {
+	assert := assert.New(t)
+	r := require.New(t)
+
+	_, err := NewThresholdBarBuilder(VolumeBar, Decimal{}, nil)
+	assert.Equal(ErrBarThreshold, err)
+	_, err = NewThresholdBarBuilder(TimeBar, MustParseDecimal("1"), nil)
+	assert.Equal(ErrBarThreshold, err)
+
+	for _, test := range []struct {
+		kind      BarKind
+		threshold string
+		// closes are the indexes of the trades closing a bar
+		closes []int
+	}{
+		{VolumeBar, "3", []int{1, 3}},
+		{TickBar, "2", []int{1, 3}},
+		{DollarBar, "40", []int{3}},
+	} {
+		var bars []Bar
+		b, err := NewThresholdBarBuilder(test.kind, MustParseDecimal(test.threshold), func(bar Bar) { bars = append(bars, bar) })
+		r.NoError(err)
+		var closes []int
+		for i, quantity := range []string{"1", "2", "1", "3", "0.5"} {
+			b.AddTrade(barTrade(int64(1000+i), strconv.Itoa(10-i), quantity))
+			if n := len(bars); n > 0 && bars[n-1].CloseTime == int64(1000+i) {
+				closes = append(closes, i)
+			}
+		}
+		assert.Equal(test.closes, closes, test.kind)
+		assert.Equal(int64(1000), bars[0].OpenTime)
+		assert.Equal("10", bars[0].Open.String())
+		assert.True(bars[0].IsFinal)
+		_, ok := b.Current()
+		assert.True(ok)
+	}
+}
@@ -0,0 +1,125 @@
+package common
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// ServerTimeFunc return the server time in milliseconds
+type ServerTimeFunc func(ctx context.Context) (int64, error)
+
+// ClockSync measure the offset between the local clock and the server clock,
+// compensating for half of the round-trip latency, and smooth it with an
+// exponential moving average. Offsets follow Client.TimeOffset: local time
+// minus server time in milliseconds.
+type ClockSync struct {
+	// Interval between two measurements when started
+	Interval time.Duration
+	// Smoothing is the weight of a new measurement in the average, from 0 to 1
+	Smoothing float64
+	// OnError is called when a background measurement fails
+	OnError func(err error)
+
+	serverTime ServerTimeFunc
+	now        func() time.Time
+	syncMu     sync.Mutex
+	mu         sync.RWMutex
+	synced     bool
+	offset     float64
+	roundTrip  time.Duration
+}
+
+// NewClockSync init a clock sync measuring serverTime every minute
+func NewClockSync(serverTime ServerTimeFunc) *ClockSync {
+	return &ClockSync{
+		Interval:   time.Minute,
+		Smoothing:  0.2,
+		serverTime: serverTime,
+		now:        time.Now,
+	}
+}
+
+// Synced tell whether at least one measurement succeeded
+func (s *ClockSync) Synced() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.synced
+}
+
+// Offset return the smoothed offset in milliseconds
+func (s *ClockSync) Offset() int64 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return int64(math.Round(s.offset))
+}
+
+// RoundTrip return the smoothed round-trip latency of the measurements
+func (s *ClockSync) RoundTrip() time.Duration {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.roundTrip
+}
+
+// Sync take a measurement and fold it into the average
+func (s *ClockSync) Sync(ctx context.Context) (offset int64, err error) {
+	return s.sync(ctx, false)
+}
+
+// Resync take a measurement and replace the average with it, used when the
+// clock jumped and the average can not be trusted anymore
+func (s *ClockSync) Resync(ctx context.Context) (offset int64, err error) {
+	return s.sync(ctx, true)
+}
+
+func (s *ClockSync) sync(ctx context.Context, reset bool) (int64, error) {
+	s.syncMu.Lock()
+	defer s.syncMu.Unlock()
+	sent := s.now()
+	serverTime, err := s.serverTime(ctx)
+	if err != nil {
+		return 0, err
+	}
+	received := s.now()
+	roundTrip := received.Sub(sent)
+	local := sent.Add(roundTrip / 2).UnixNano()
+	offset := float64(local)/float64(time.Millisecond) - float64(serverTime)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if reset || !s.synced {
+		s.offset = offset
+		s.roundTrip = roundTrip
+		s.synced = true
+	} else {
+		s.offset += s.Smoothing * (offset - s.offset)
+		s.roundTrip += time.Duration(s.Smoothing * float64(roundTrip-s.roundTrip))
+	}
+	return int64(math.Round(s.offset)), nil
+}
+
+// Start take a first measurement then keep measuring every Interval until
+// ctx is done
+func (s *ClockSync) Start(ctx context.Context) error {
+	_, err := s.Resync(ctx)
+	if err != nil {
+		return err
+	}
+	go func() {
+		ticker := time.NewTicker(s.Interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			_, err := s.Sync(ctx)
+			if err != nil && ctx.Err() == nil && s.OnError != nil {
+				s.OnError(err)
+			}
+		}
+	}()
+	return nil
+}
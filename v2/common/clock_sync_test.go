@@ -0,0 +1,58 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestClockSync(t *testing.T) {
+	r := require.New(t)
+	local := time.Unix(1000, 0)
+	roundTrip := 100 * time.Millisecond
+	serverTime := int64(0)
+	s := NewClockSync(func(ctx context.Context) (int64, error) {
+		local = local.Add(roundTrip)
+		return serverTime, nil
+	})
+	s.now = func() time.Time {
+		return local
+	}
+	s.Smoothing = 0.5
+	r.False(s.Synced())
+
+	// Local clock 2s ahead, the server answered at the middle of the round trip
+	serverTime = 998050
+	offset, err := s.Sync(context.Background())
+	r.NoError(err)
+	r.True(s.Synced())
+	r.Equal(int64(2000), offset)
+	r.Equal(roundTrip, s.RoundTrip())
+
+	// A 1s offset is averaged with the previous measurement
+	roundTrip = 300 * time.Millisecond
+	local = time.Unix(1000, 0)
+	serverTime = 999150
+	offset, err = s.Sync(context.Background())
+	r.NoError(err)
+	r.Equal(int64(1500), offset)
+	r.Equal(200*time.Millisecond, s.RoundTrip())
+
+	// Resync drops the average
+	local = time.Unix(1000, 0)
+	serverTime = 1000150
+	offset, err = s.Resync(context.Background())
+	r.NoError(err)
+	r.Equal(int64(0), offset)
+	r.Equal(int64(0), s.Offset())
+}
+
+func TestIsInvalidTimestamp(t *testing.T) {
+	r := require.New(t)
+	r.True(IsInvalidTimestamp(&APIError{Code: CodeInvalidTimestamp}))
+	r.False(IsInvalidTimestamp(&APIError{Code: -1121}))
+	r.False(IsInvalidTimestamp(errors.New("dummy error")))
+}
@@ -0,0 +1,230 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrHeartbeatStalled is reported when Beat was not called for StallTimeout,
+// the heartbeat then stops re-arming and the countdown cancels the orders
+var ErrHeartbeatStalled = errors.New("heartbeat stalled")
+
+// ErrCountdownElapsed is reported when a countdown was re-armed after it
+// ended, the orders of the symbol may have been cancelled
+var ErrCountdownElapsed = errors.New("countdown elapsed before being re-armed")
+
+// ErrCountdownInterval is returned by Start when Interval is not positive
+// and shorter than Countdown
+var ErrCountdownInterval = errors.New("countdown interval must be positive and shorter than the countdown")
+
+// ErrHeartbeatStarted is returned by Start when the heartbeat was already
+// started
+var ErrHeartbeatStarted = errors.New("heartbeat already started")
+
+// CountdownFunc arm the countdown cancelling all the open orders of symbol,
+// a zero countdown disarms it
+type CountdownFunc func(ctx context.Context, symbol string, countdown time.Duration) error
+
+// CountdownAction is what the heartbeat did to the countdown of a symbol
+type CountdownAction int
+
+// Global enums
+const (
+	// CountdownArm armed or re-armed the countdown
+	CountdownArm CountdownAction = iota
+	// CountdownDisarm cancelled the countdown, the orders are kept
+	CountdownDisarm
+	// CountdownLapse let the countdown run out, or found it had
+	CountdownLapse
+)
+
+// CountdownEvent report an action on the countdown of a symbol, Err is the
+// failure of the request, or why the countdown lapsed
+type CountdownEvent struct {
+	Symbol    string
+	Action    CountdownAction
+	Countdown time.Duration
+	Time      time.Time
+	Err       error
+}
+
+// CountdownHeartbeat is a dead man's switch for the open orders of futures
+// symbols: it re-arms the countdown of every symbol each Interval, so that
+// the exchange cancels their orders once the process stops re-arming them.
+//
+// Stall detection is opt-in: a heartbeat with a zero StallTimeout keeps
+// re-arming as long as its goroutine runs, even if the rest of the process
+// hangs. Set StallTimeout and call Beat from the main loop of the process,
+// the heartbeat then stops re-arming once Beat was not called for
+// StallTimeout and resumes on the next Beat.
+type CountdownHeartbeat struct {
+	// Countdown after which the orders are cancelled
+	Countdown time.Duration
+	// Interval between two arms, well below Countdown
+	Interval time.Duration
+	// StallTimeout without Beat after which re-arming stops, zero, the
+	// default, disables stall detection
+	StallTimeout time.Duration
+	// DisarmOnStop disarm the countdowns once ctx is done, keeping the orders
+	// on a clean shutdown
+	DisarmOnStop bool
+	// OnEvent is called for every arm, disarm and lapse
+	OnEvent func(event CountdownEvent)
+
+	countdown CountdownFunc
+	symbols   []string
+	now       func() time.Time
+
+	mu       sync.Mutex
+	lastBeat time.Time
+	armed    map[string]time.Time
+	stalled  bool
+	started  bool
+	doneC    chan struct{}
+}
+
+// NewCountdownHeartbeat init a heartbeat arming a 30s countdown on symbols
+// every 10s, see Start
+func NewCountdownHeartbeat(countdown CountdownFunc, symbols ...string) *CountdownHeartbeat {
+	return &CountdownHeartbeat{
+		Countdown: 30 * time.Second,
+		Interval:  10 * time.Second,
+		countdown: countdown,
+		symbols:   symbols,
+		now:       time.Now,
+		armed:     make(map[string]time.Time),
+		doneC:     make(chan struct{}),
+	}
+}
+
+// Beat tell the heartbeat the process is healthy
+func (h *CountdownHeartbeat) Beat() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.lastBeat = h.now()
+}
+
+// Stalled tell whether the heartbeat stopped re-arming for lack of Beat
+func (h *CountdownHeartbeat) Stalled() bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.stalled
+}
+
+// Done is closed once the heartbeat stopped after ctx was done, and the
+// countdowns were disarmed when DisarmOnStop is set, or once Start failed
+func (h *CountdownHeartbeat) Done() <-chan struct{} {
+	return h.doneC
+}
+
+// Start arm every symbol then keep re-arming them in the background until ctx
+// is done. The first failed arm is returned, the symbols armed before it
+// stay armed. A heartbeat can only be started once.
+func (h *CountdownHeartbeat) Start(ctx context.Context) error {
+	if h.Interval <= 0 || h.Interval >= h.Countdown {
+		return ErrCountdownInterval
+	}
+	h.mu.Lock()
+	started := h.started
+	h.started = true
+	h.mu.Unlock()
+	if started {
+		return ErrHeartbeatStarted
+	}
+	h.Beat()
+	for _, symbol := range h.symbols {
+		if err := h.arm(ctx, symbol); err != nil {
+			close(h.doneC)
+			return err
+		}
+	}
+	go h.run(ctx)
+	return nil
+}
+
+func (h *CountdownHeartbeat) run(ctx context.Context) {
+	defer close(h.doneC)
+	ticker := time.NewTicker(h.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			if h.DisarmOnStop {
+				h.disarm()
+			}
+			return
+		case <-ticker.C:
+		}
+		if h.checkStall() {
+			continue
+		}
+		for _, symbol := range h.symbols {
+			h.arm(ctx, symbol)
+			if ctx.Err() != nil {
+				break
+			}
+		}
+	}
+}
+
+// checkStall tell whether re-arming must be skipped, reporting the lapse of
+// every symbol when the stall starts
+func (h *CountdownHeartbeat) checkStall() bool {
+	h.mu.Lock()
+	now := h.now()
+	stalled := h.StallTimeout > 0 && now.Sub(h.lastBeat) > h.StallTimeout
+	report := stalled && !h.stalled
+	h.stalled = stalled
+	h.mu.Unlock()
+	if report {
+		for _, symbol := range h.symbols {
+			h.event(CountdownEvent{Symbol: symbol, Action: CountdownLapse, Time: now, Err: ErrHeartbeatStalled})
+		}
+	}
+	return stalled
+}
+
+func (h *CountdownHeartbeat) arm(ctx context.Context, symbol string) error {
+	now := h.now()
+	h.mu.Lock()
+	last, ok := h.armed[symbol]
+	h.mu.Unlock()
+	// A late arm, after a pause of the process, finds the countdown over
+	if ok && now.Sub(last) >= h.Countdown {
+		h.event(CountdownEvent{Symbol: symbol, Action: CountdownLapse, Time: now, Err: ErrCountdownElapsed})
+	}
+	err := h.countdown(ctx, symbol, h.Countdown)
+	if err != nil && ctx.Err() != nil {
+		return err
+	}
+	if err == nil {
+		h.mu.Lock()
+		h.armed[symbol] = now
+		h.mu.Unlock()
+	}
+	h.event(CountdownEvent{Symbol: symbol, Action: CountdownArm, Countdown: h.Countdown, Time: now, Err: err})
+	return err
+}
+
+// disarm cancel the countdowns with a fresh context since ctx is done
+func (h *CountdownHeartbeat) disarm() {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	for _, symbol := range h.symbols {
+		err := h.countdown(ctx, symbol, 0)
+		if err == nil {
+			h.mu.Lock()
+			delete(h.armed, symbol)
+			h.mu.Unlock()
+		}
+		h.event(CountdownEvent{Symbol: symbol, Action: CountdownDisarm, Time: h.now(), Err: err})
+	}
+}
+
+func (h *CountdownHeartbeat) event(event CountdownEvent) {
+	if h.OnEvent != nil {
+		h.OnEvent(event)
+	}
+}
@@ -0,0 +1,168 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// countdownRecorder record the calls and the events of a heartbeat
+type countdownRecorder struct {
+	mu     sync.Mutex
+	calls  map[string][]time.Duration
+	events []CountdownEvent
+	err    error
+}
+
+func newCountdownHeartbeatTest(symbols ...string) (*CountdownHeartbeat, *countdownRecorder) {
+	rec := &countdownRecorder{calls: make(map[string][]time.Duration)}
+	h := NewCountdownHeartbeat(func(ctx context.Context, symbol string, countdown time.Duration) error {
+		rec.mu.Lock()
+		defer rec.mu.Unlock()
+		rec.calls[symbol] = append(rec.calls[symbol], countdown)
+		return rec.err
+	}, symbols...)
+	h.Countdown = time.Second
+	h.Interval = 5 * time.Millisecond
+	h.OnEvent = func(event CountdownEvent) {
+		rec.mu.Lock()
+		defer rec.mu.Unlock()
+		rec.events = append(rec.events, event)
+	}
+	return h, rec
+}
+
+func (rec *countdownRecorder) count(symbol string) int {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	return len(rec.calls[symbol])
+}
+
+func (rec *countdownRecorder) lapses(err error) int {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	n := 0
+	for _, event := range rec.events {
+		if event.Action == CountdownLapse && errors.Is(event.Err, err) {
+			n++
+		}
+	}
+	return n
+}
+
+func TestCountdownHeartbeat(t *testing.T) {
+	r := require.New(t)
+	h, rec := newCountdownHeartbeatTest("BTCUSDT", "ETHUSDT")
+	h.DisarmOnStop = true
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	r.NoError(h.Start(ctx))
+	r.Equal(1, rec.count("BTCUSDT"))
+	r.Eventually(func() bool {
+		return rec.count("BTCUSDT") > 2 && rec.count("ETHUSDT") > 2
+	}, time.Second, time.Millisecond)
+
+	cancel()
+	select {
+	case <-h.Done():
+	case <-time.After(time.Second):
+		r.FailNow("heartbeat not done")
+	}
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	for _, symbol := range []string{"BTCUSDT", "ETHUSDT"} {
+		calls := rec.calls[symbol]
+		r.Equal(time.Second, calls[0])
+		r.Equal(time.Duration(0), calls[len(calls)-1], symbol)
+	}
+	last := rec.events[len(rec.events)-2:]
+	r.Equal(CountdownEvent{Symbol: "BTCUSDT", Action: CountdownDisarm, Time: last[0].Time}, last[0])
+	r.Equal(CountdownEvent{Symbol: "ETHUSDT", Action: CountdownDisarm, Time: last[1].Time}, last[1])
+	for _, event := range rec.events[:len(rec.events)-2] {
+		r.Equal(CountdownArm, event.Action)
+		r.Equal(time.Second, event.Countdown)
+		r.NoError(event.Err)
+	}
+}
+
+func TestCountdownHeartbeatStall(t *testing.T) {
+	r := require.New(t)
+	h, rec := newCountdownHeartbeatTest("BTCUSDT")
+	h.StallTimeout = 20 * time.Millisecond
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	r.NoError(h.Start(ctx))
+
+	// Without Beat the heartbeat stops re-arming, reporting the lapse once
+	r.Eventually(h.Stalled, time.Second, time.Millisecond)
+	armed := rec.count("BTCUSDT")
+	time.Sleep(20 * time.Millisecond)
+	r.Equal(armed, rec.count("BTCUSDT"))
+	r.Equal(1, rec.lapses(ErrHeartbeatStalled))
+
+	// A beat resumes it
+	h.Beat()
+	r.Eventually(func() bool { return rec.count("BTCUSDT") > armed }, time.Second, time.Millisecond)
+	r.False(h.Stalled())
+	r.Zero(rec.lapses(ErrCountdownElapsed))
+}
+
+func TestCountdownHeartbeatElapsed(t *testing.T) {
+	r := require.New(t)
+	h, rec := newCountdownHeartbeatTest("BTCUSDT")
+	var mu sync.Mutex
+	now := time.Unix(1000, 0)
+	h.now = func() time.Time {
+		mu.Lock()
+		defer mu.Unlock()
+		return now
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	r.NoError(h.Start(ctx))
+
+	// A pause of the process longer than the countdown ends it between two arms
+	mu.Lock()
+	now = now.Add(2 * time.Second)
+	mu.Unlock()
+	r.Eventually(func() bool { return rec.lapses(ErrCountdownElapsed) > 0 }, time.Second, time.Millisecond)
+	r.Equal(1, rec.lapses(ErrCountdownElapsed))
+}
+
+func TestCountdownHeartbeatStartError(t *testing.T) {
+	r := require.New(t)
+	h, rec := newCountdownHeartbeatTest("BTCUSDT")
+	rec.err = &APIError{Code: -1121, Message: "Invalid symbol."}
+	err := h.Start(context.Background())
+	r.True(errors.Is(err, rec.err))
+	r.Len(rec.events, 1)
+	r.Equal(CountdownArm, rec.events[0].Action)
+	r.Equal(rec.err, rec.events[0].Err)
+	select {
+	case <-h.Done():
+	default:
+		r.Fail("heartbeat not done after a failed start")
+	}
+	r.Equal(ErrHeartbeatStarted, h.Start(context.Background()))
+}
+
+func TestCountdownHeartbeatInvalid(t *testing.T) {
+	r := require.New(t)
+	h, rec := newCountdownHeartbeatTest("BTCUSDT")
+	h.Interval = 0
+	r.Equal(ErrCountdownInterval, h.Start(context.Background()))
+	h.Interval = h.Countdown
+	r.Equal(ErrCountdownInterval, h.Start(context.Background()))
+	r.Zero(rec.count("BTCUSDT"))
+
+	h.Interval = 5 * time.Millisecond
+	ctx, cancel := context.WithCancel(context.Background())
+	r.NoError(h.Start(ctx))
+	r.Equal(ErrHeartbeatStarted, h.Start(ctx))
+	cancel()
+	<-h.Done()
+}
@@ -0,0 +1,334 @@
+package common
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+var bigTen = big.NewInt(10)
+
+// RoundingMode define how a value is rounded to a step
+type RoundingMode int
+
+// Global enums
+const (
+	// RoundDown round toward zero
+	RoundDown RoundingMode = iota
+	// RoundUp round away from zero
+	RoundUp
+	// RoundHalfUp round to the nearest step, ties away from zero
+	RoundHalfUp
+)
+
+// Decimal is an exact decimal number, the value is unscaled * 10^-scale.
+// The zero value is 0.
+type Decimal struct {
+	unscaled *big.Int
+	scale    int32
+}
+
+// maxDecimalExponent bound the exponent accepted by ParseDecimal, a larger
+// one would allocate a huge integer
+const maxDecimalExponent = 1000
+
+// ParseDecimal parse a decimal string such as "0.00100000", "-12" or "1e-8",
+// the exponent must be within ±1000
+func ParseDecimal(s string) (Decimal, error) {
+	mantissa, exponent := s, int64(0)
+	if i := strings.IndexAny(s, "eE"); i >= 0 {
+		var err error
+		exponent, err = strconv.ParseInt(s[i+1:], 10, 32)
+		if err != nil {
+			return Decimal{}, fmt.Errorf("invalid decimal %q", s)
+		}
+		if exponent > maxDecimalExponent || exponent < -maxDecimalExponent {
+			return Decimal{}, fmt.Errorf("decimal exponent out of range %q", s)
+		}
+		mantissa = s[:i]
+	}
+	digits := mantissa
+	scale := int64(0)
+	if i := strings.IndexByte(mantissa, '.'); i >= 0 {
+		digits = mantissa[:i] + mantissa[i+1:]
+		scale = int64(len(mantissa) - i - 1)
+	}
+	digits = strings.TrimPrefix(digits, "+")
+	if digits == "" || digits == "-" || strings.ContainsAny(digits[1:], "+-") {
+		return Decimal{}, fmt.Errorf("invalid decimal %q", s)
+	}
+	unscaled, ok := new(big.Int).SetString(digits, 10)
+	if !ok {
+		return Decimal{}, fmt.Errorf("invalid decimal %q", s)
+	}
+	scale -= exponent
+	if scale < 0 {
+		unscaled.Mul(unscaled, pow10(-scale))
+		scale = 0
+	}
+	return Decimal{unscaled: unscaled, scale: int32(scale)}, nil
+}
+
+// NewDecimal return unscaled * 10^-scale, NewDecimal(123, 2) is 1.23
+func NewDecimal(unscaled int64, scale int32) Decimal {
+	d := Decimal{unscaled: big.NewInt(unscaled), scale: scale}
+	if scale < 0 {
+		d.unscaled.Mul(d.unscaled, pow10(int64(-scale)))
+		d.scale = 0
+	}
+	return d
+}
+
+// NewDecimalFromFloat return the shortest decimal representing f, f must be
+// finite
+func NewDecimalFromFloat(f float64) Decimal {
+	return MustParseDecimal(strconv.FormatFloat(f, 'f', -1, 64))
+}
+
+// MustParseDecimal is like ParseDecimal but panic on invalid input, for constants
+func MustParseDecimal(s string) Decimal {
+	d, err := ParseDecimal(s)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+func pow10(n int64) *big.Int {
+	return new(big.Int).Exp(bigTen, big.NewInt(n), nil)
+}
+
+func (d Decimal) int() *big.Int {
+	if d.unscaled == nil {
+		return new(big.Int)
+	}
+	return d.unscaled
+}
+
+// rescale return the unscaled value of d at a scale greater or equal to d.scale
+func (d Decimal) rescale(scale int32) *big.Int {
+	if scale == d.scale {
+		return d.int()
+	}
+	return new(big.Int).Mul(d.int(), pow10(int64(scale-d.scale)))
+}
+
+// align return the unscaled values of d and o at their common scale
+func (d Decimal) align(o Decimal) (x, y *big.Int, scale int32) {
+	scale = d.scale
+	if o.scale > scale {
+		scale = o.scale
+	}
+	return d.rescale(scale), o.rescale(scale), scale
+}
+
+// String return the shortest representation of d, without exponent nor
+// trailing zeros
+func (d Decimal) String() string {
+	s := d.int().String()
+	if d.scale == 0 {
+		return s
+	}
+	neg := strings.HasPrefix(s, "-")
+	s = strings.TrimPrefix(s, "-")
+	if pad := int(d.scale) - len(s) + 1; pad > 0 {
+		s = strings.Repeat("0", pad) + s
+	}
+	i := len(s) - int(d.scale)
+	s = strings.TrimRight(s[:i]+"."+s[i:], "0")
+	s = strings.TrimSuffix(s, ".")
+	if neg && s != "0" {
+		s = "-" + s
+	}
+	return s
+}
+
+// StringFixed return d rounded half up to places decimals, padded with
+// trailing zeros
+func (d Decimal) StringFixed(places int32) string {
+	r := d.Round(places, RoundHalfUp)
+	if places < 0 {
+		return r.String()
+	}
+	unscaled := r.int()
+	if places >= r.scale {
+		unscaled = r.rescale(places)
+	} else {
+		// r is a multiple of 10^-places, the division is exact
+		unscaled = new(big.Int).Quo(unscaled, pow10(int64(r.scale-places)))
+	}
+	s := new(big.Int).Abs(unscaled).String()
+	if places > 0 {
+		if pad := int(places) - len(s) + 1; pad > 0 {
+			s = strings.Repeat("0", pad) + s
+		}
+		i := len(s) - int(places)
+		s = s[:i] + "." + s[i:]
+	}
+	if r.Sign() < 0 {
+		s = "-" + s
+	}
+	return s
+}
+
+// Float64 return the nearest float64 of d
+func (d Decimal) Float64() float64 {
+	f, _ := strconv.ParseFloat(d.String(), 64)
+	return f
+}
+
+// Sign return -1, 0 or 1 depending on the sign of d
+func (d Decimal) Sign() int {
+	return d.int().Sign()
+}
+
+// IsZero tell whether d is 0
+func (d Decimal) IsZero() bool {
+	return d.Sign() == 0
+}
+
+// Cmp compare d and o and return -1, 0 or 1
+func (d Decimal) Cmp(o Decimal) int {
+	x, y, _ := d.align(o)
+	return x.Cmp(y)
+}
+
+// Equal tell whether d and o are the same number, whatever their scale
+func (d Decimal) Equal(o Decimal) bool {
+	return d.Cmp(o) == 0
+}
+
+// Neg return -d
+func (d Decimal) Neg() Decimal {
+	return Decimal{unscaled: new(big.Int).Neg(d.int()), scale: d.scale}
+}
+
+// Abs return |d|
+func (d Decimal) Abs() Decimal {
+	return Decimal{unscaled: new(big.Int).Abs(d.int()), scale: d.scale}
+}
+
+// Add return d + o
+func (d Decimal) Add(o Decimal) Decimal {
+	x, y, scale := d.align(o)
+	return Decimal{unscaled: new(big.Int).Add(x, y), scale: scale}
+}
+
+// Sub return d - o
+func (d Decimal) Sub(o Decimal) Decimal {
+	x, y, scale := d.align(o)
+	return Decimal{unscaled: new(big.Int).Sub(x, y), scale: scale}
+}
+
+// Mul return d * o
+func (d Decimal) Mul(o Decimal) Decimal {
+	return Decimal{unscaled: new(big.Int).Mul(d.int(), o.int()), scale: d.scale + o.scale}
+}
+
+// Div return d / o rounded to places decimals with mode, o must not be 0
+func (d Decimal) Div(o Decimal, places int32, mode RoundingMode) Decimal {
+	x, y, _ := d.align(o)
+	if places > 0 {
+		x = new(big.Int).Mul(x, pow10(int64(places)))
+	} else if places < 0 {
+		y = new(big.Int).Mul(y, pow10(int64(-places)))
+	}
+	q := quoRound(x, y, mode)
+	if places < 0 {
+		return Decimal{unscaled: q.Mul(q, pow10(int64(-places)))}
+	}
+	return Decimal{unscaled: q, scale: places}
+}
+
+// Round return d rounded to places decimals with mode
+func (d Decimal) Round(places int32, mode RoundingMode) Decimal {
+	if places >= d.scale {
+		return d
+	}
+	return d.RoundToStep(NewDecimal(1, places), mode)
+}
+
+// QuoInt return d / o rounded to an integer with mode, o must not be 0
+func (d Decimal) QuoInt(o Decimal, mode RoundingMode) *big.Int {
+	x, y, _ := d.align(o)
+	return quoRound(x, y, mode)
+}
+
+// RoundToStep round d to a multiple of step with mode, d is returned
+// unchanged when step is not positive
+func (d Decimal) RoundToStep(step Decimal, mode RoundingMode) Decimal {
+	if step.Sign() <= 0 {
+		return d
+	}
+	x, y, scale := d.align(step)
+	q := quoRound(x, y, mode)
+	return Decimal{unscaled: q.Mul(q, y), scale: scale}
+}
+
+func quoRound(x, y *big.Int, mode RoundingMode) *big.Int {
+	q, r := new(big.Int).QuoRem(x, y, new(big.Int))
+	if r.Sign() == 0 {
+		return q
+	}
+	away := false
+	switch mode {
+	case RoundUp:
+		away = true
+	case RoundHalfUp:
+		twice := new(big.Int).Abs(r)
+		away = twice.Lsh(twice, 1).Cmp(new(big.Int).Abs(y)) >= 0
+	}
+	if away {
+		if x.Sign() == y.Sign() {
+			q.Add(q, big.NewInt(1))
+		} else {
+			q.Sub(q, big.NewInt(1))
+		}
+	}
+	return q
+}
+
+// MarshalJSON encode d as a JSON string, like the API does
+func (d Decimal) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.Quote(d.String())), nil
+}
+
+// UnmarshalJSON decode a JSON string or number, null is decoded as 0
+func (d *Decimal) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if bytes.Equal(data, []byte("null")) {
+		*d = Decimal{}
+		return nil
+	}
+	s := string(data)
+	if len(data) > 0 && data[0] == '"' {
+		var err error
+		s, err = strconv.Unquote(s)
+		if err != nil {
+			return fmt.Errorf("invalid decimal %s", data)
+		}
+	}
+	v, err := ParseDecimal(s)
+	if err != nil {
+		return err
+	}
+	*d = v
+	return nil
+}
+
+// MarshalText encode d as text, for map keys and text based encodings
+func (d Decimal) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalText decode a decimal from text
+func (d *Decimal) UnmarshalText(data []byte) error {
+	v, err := ParseDecimal(string(data))
+	if err != nil {
+		return err
+	}
+	*d = v
+	return nil
+}
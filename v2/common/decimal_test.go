@@ -0,0 +1,120 @@
+package common
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseDecimal(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{input: "0.00100000", want: "0.001"},
+		{input: "-12", want: "-12"},
+		{input: "+1.50", want: "1.5"},
+		{input: ".5", want: "0.5"},
+		{input: "1e-8", want: "0.00000001"},
+		{input: "1.5E3", want: "1500"},
+		{input: "-0.000", want: "0"},
+		{input: "123456789012345678901234567890.123456789", want: "123456789012345678901234567890.123456789"},
+	}
+	for _, tt := range tests {
+		d, err := ParseDecimal(tt.input)
+		assert.NoError(t, err, tt.input)
+		assert.Equal(t, tt.want, d.String(), tt.input)
+	}
+	for _, input := range []string{"", "-", ".", "1.2.3", "1-2", "abc", "1e", "--1", "1e2000000000", "1e-1001"} {
+		_, err := ParseDecimal(input)
+		assert.Error(t, err, input)
+	}
+	assert.Equal(t, "0", Decimal{}.String())
+}
+
+func TestDecimalArithmetic(t *testing.T) {
+	assert := assert.New(t)
+	a := MustParseDecimal("0.1")
+	b := MustParseDecimal("0.2")
+	assert.Equal("0.3", a.Add(b).String())
+	assert.Equal("-0.1", a.Sub(b).String())
+	assert.Equal("0.02", a.Mul(b).String())
+	assert.Equal(0, a.Add(b).Cmp(MustParseDecimal("0.30000")))
+	assert.Equal(-1, a.Cmp(b))
+	assert.True(a.Sub(a).IsZero())
+	assert.Equal(int64(4), MustParseDecimal("1").QuoInt(MustParseDecimal("0.3"), RoundUp).Int64())
+	assert.Equal(int64(3), MustParseDecimal("1").QuoInt(MustParseDecimal("0.3"), RoundDown).Int64())
+}
+
+func TestDecimalRoundToStep(t *testing.T) {
+	tests := []struct {
+		value string
+		step  string
+		mode  RoundingMode
+		want  string
+	}{
+		{value: "1.23456", step: "0.001", mode: RoundDown, want: "1.234"},
+		{value: "1.23456", step: "0.001", mode: RoundUp, want: "1.235"},
+		{value: "1.2345", step: "0.001", mode: RoundHalfUp, want: "1.235"},
+		{value: "1.2344", step: "0.001", mode: RoundHalfUp, want: "1.234"},
+		{value: "-1.23456", step: "0.001", mode: RoundDown, want: "-1.234"},
+		{value: "-1.23456", step: "0.001", mode: RoundUp, want: "-1.235"},
+		{value: "17", step: "5", mode: RoundDown, want: "15"},
+		{value: "1.5", step: "0", mode: RoundDown, want: "1.5"},
+		{value: "99999999999999999999.99999999", step: "0.00000001", mode: RoundDown, want: "99999999999999999999.99999999"},
+	}
+	for _, tt := range tests {
+		got := MustParseDecimal(tt.value).RoundToStep(MustParseDecimal(tt.step), tt.mode)
+		assert.Equal(t, tt.want, got.String(), "%s step %s", tt.value, tt.step)
+	}
+}
+
+func TestDecimalDivRound(t *testing.T) {
+	assert := assert.New(t)
+	one := NewDecimal(1, 0)
+	three := NewDecimal(3, 0)
+	assert.Equal("0.33333333", one.Div(three, 8, RoundHalfUp).String())
+	assert.Equal("0.66666667", one.Add(one).Div(three, 8, RoundHalfUp).String())
+	assert.Equal("-0.66666666", one.Add(one).Neg().Div(three, 8, RoundDown).String())
+	assert.Equal("300", NewDecimal(28765, 2).Div(one, -2, RoundUp).String())
+	assert.Equal("1.24", MustParseDecimal("1.235").Round(2, RoundHalfUp).String())
+	assert.Equal("1.235", MustParseDecimal("1.235").Round(5, RoundHalfUp).String())
+	assert.Equal("1.23", NewDecimal(123, 2).String())
+	assert.Equal("1200", NewDecimal(12, -2).String())
+	assert.Equal("1.5", MustParseDecimal("-1.5").Abs().String())
+	assert.True(MustParseDecimal("1.50").Equal(MustParseDecimal("1.5")))
+}
+
+func TestDecimalConversions(t *testing.T) {
+	assert := assert.New(t)
+	assert.Equal("0.1", NewDecimalFromFloat(0.1).String())
+	assert.Equal(0.3, MustParseDecimal("0.3").Float64())
+	assert.Equal("1.50000000", MustParseDecimal("1.5").StringFixed(8))
+	assert.Equal("-0.01", MustParseDecimal("-0.005").StringFixed(2))
+	assert.Equal("2", MustParseDecimal("1.5").StringFixed(0))
+	assert.Equal("0.000", Decimal{}.StringFixed(3))
+}
+
+func TestDecimalJSON(t *testing.T) {
+	assert := assert.New(t)
+	var v struct {
+		Price    Decimal  `json:"price"`
+		Quantity Decimal  `json:"quantity"`
+		Fee      Decimal  `json:"fee"`
+		Stop     *Decimal `json:"stop"`
+	}
+	err := json.Unmarshal([]byte(`{"price":"43187.00000000","quantity":0.001,"fee":null,"stop":"1e-8"}`), &v)
+	assert.NoError(err)
+	assert.Equal("43187", v.Price.String())
+	assert.Equal("0.001", v.Quantity.String())
+	assert.True(v.Fee.IsZero())
+	assert.Equal("0.00000001", v.Stop.String())
+
+	data, err := json.Marshal(v)
+	assert.NoError(err)
+	assert.JSONEq(`{"price":"43187","quantity":"0.001","fee":"0","stop":"0.00000001"}`, string(data))
+
+	assert.Error(json.Unmarshal([]byte(`{"price":"abc"}`), &v))
+	assert.Error(json.Unmarshal([]byte(`{"price":true}`), &v))
+}
@@ -1,13 +1,27 @@
 package common
 
 import (
+	"errors"
 	"fmt"
+	"net/http"
+	"strings"
 )
 
 // APIError define API error when response status is 4xx or 5xx
 type APIError struct {
-	Code    int64  `json:"code"`
-	Message string `json:"msg"`
+	Code    ErrorCode `json:"code"`
+	Message string    `json:"msg"`
+
+	// StatusCode is the HTTP status of the response, or the status field of a
+	// websocket API response
+	StatusCode int `json:"-"`
+	// Method and Endpoint identify the request, Endpoint is the websocket API
+	// method for websocket API requests
+	Method   string `json:"-"`
+	Endpoint string `json:"-"`
+	// Weight is the request weight counted by the client rate limiter, 0 when
+	// the endpoint is not tracked
+	Weight int64 `json:"-"`
 }
 
 // Error return error code and message
@@ -15,8 +29,242 @@ func (e APIError) Error() string {
 	return fmt.Sprintf("<APIError> code=%d, msg=%s", e.Code, e.Message)
 }
 
+// Is report whether target is an API error with the same code, so API errors
+// can be matched against the sentinel errors with errors.Is
+func (e APIError) Is(target error) bool {
+	switch t := target.(type) {
+	case *APIError:
+		return t != nil && t.Code == e.Code
+	case APIError:
+		return t.Code == e.Code
+	}
+	return false
+}
+
 // IsAPIError check if e is an API error
 func IsAPIError(e error) bool {
-	_, ok := e.(*APIError)
+	_, ok := AsAPIError(e)
 	return ok
 }
+
+// AsAPIError return the first API error in the chain of err, whether it was
+// returned as a pointer or as a value
+func AsAPIError(err error) (*APIError, bool) {
+	var ptr *APIError
+	if errors.As(err, &ptr) && ptr != nil {
+		return ptr, true
+	}
+	var value APIError
+	if errors.As(err, &value) {
+		return &value, true
+	}
+	return nil, false
+}
+
+// ErrorCode is the code of an API error
+type ErrorCode int64
+
+// Error codes shared by the spot, futures, delivery and portfolio APIs, see
+// https://binance-docs.github.io/apidocs/spot/en/#error-codes
+const (
+	// 10xx - General server or network issues
+	CodeUnknown             ErrorCode = -1000
+	CodeDisconnected        ErrorCode = -1001
+	CodeUnauthorized        ErrorCode = -1002
+	CodeTooManyRequests     ErrorCode = -1003
+	CodeUnexpectedResponse  ErrorCode = -1006
+	CodeTimeout             ErrorCode = -1007
+	CodeServerBusy          ErrorCode = -1008
+	CodeFilterFailure       ErrorCode = -1013
+	CodeUnknownOrderComp    ErrorCode = -1014
+	CodeTooManyOrders       ErrorCode = -1015
+	CodeServiceShuttingDown ErrorCode = -1016
+	CodeUnsupportedOp       ErrorCode = -1020
+	CodeInvalidTimestamp    ErrorCode = -1021
+	CodeInvalidSignature    ErrorCode = -1022
+	CodeNotAuthorized       ErrorCode = -1099
+
+	// 11xx - Request issues
+	CodeIllegalChars           ErrorCode = -1100
+	CodeTooManyParameters      ErrorCode = -1101
+	CodeMandatoryParamEmpty    ErrorCode = -1102
+	CodeUnknownParam           ErrorCode = -1103
+	CodeUnreadParameters       ErrorCode = -1104
+	CodeParamEmpty             ErrorCode = -1105
+	CodeParamNotRequired       ErrorCode = -1106
+	CodeBadPrecision           ErrorCode = -1111
+	CodeNoDepth                ErrorCode = -1112
+	CodeTIFNotRequired         ErrorCode = -1114
+	CodeInvalidTIF             ErrorCode = -1115
+	CodeInvalidOrderType       ErrorCode = -1116
+	CodeInvalidSide            ErrorCode = -1117
+	CodeEmptyNewClOrdID        ErrorCode = -1118
+	CodeEmptyOrgClOrdID        ErrorCode = -1119
+	CodeBadInterval            ErrorCode = -1120
+	CodeBadSymbol              ErrorCode = -1121
+	CodeInvalidListenKey       ErrorCode = -1125
+	CodeMoreThanXXHours        ErrorCode = -1127
+	CodeOptionalParamsBadCombo ErrorCode = -1128
+	CodeInvalidParameter       ErrorCode = -1130
+
+	// 20xx - Processing issues
+	CodeInvalidAPIKeyID        ErrorCode = -2008
+	CodeNewOrderRejected       ErrorCode = -2010
+	CodeCancelRejected         ErrorCode = -2011
+	CodeNoSuchOrder            ErrorCode = -2013
+	CodeBadAPIKeyFormat        ErrorCode = -2014
+	CodeRejectedAPIKey         ErrorCode = -2015
+	CodeNoTradingWindow        ErrorCode = -2016
+	CodeBalanceNotSufficient   ErrorCode = -2018
+	CodeMarginNotSufficient    ErrorCode = -2019
+	CodeUnableToFill           ErrorCode = -2020
+	CodeOrderWouldTrigger      ErrorCode = -2021
+	CodeReduceOnlyReject       ErrorCode = -2022
+	CodeUserInLiquidation      ErrorCode = -2023
+	CodePositionNotSufficient  ErrorCode = -2024
+	CodeMaxOpenOrderExceeded   ErrorCode = -2025
+	CodeMaxLeverageRatio       ErrorCode = -2027
+	CodeMinLeverageRatio       ErrorCode = -2028
+	CodeMarginBalanceNotEnough ErrorCode = -3041
+
+	// 4xxx - Futures filter failures
+	CodePriceLessThanZero       ErrorCode = -4001
+	CodePriceGreaterThanMax     ErrorCode = -4002
+	CodeQtyLessThanZero         ErrorCode = -4003
+	CodeQtyLessThanMin          ErrorCode = -4004
+	CodeQtyGreaterThanMax       ErrorCode = -4005
+	CodePriceLessThanMin        ErrorCode = -4013
+	CodePriceNotIncreasedByTick ErrorCode = -4014
+	CodePriceHigherThanMultUp   ErrorCode = -4016
+	CodeQtyNotIncreasedByStep   ErrorCode = -4023
+	CodePriceLowerThanMultDown  ErrorCode = -4024
+	CodeMinNotional             ErrorCode = -4164
+)
+
+// Sentinel errors to match API errors with errors.Is, only the code is compared
+var (
+	ErrUnknown              = &APIError{Code: CodeUnknown, Message: "unknown error"}
+	ErrDisconnected         = &APIError{Code: CodeDisconnected, Message: "internal error, unable to process the request"}
+	ErrUnauthorized         = &APIError{Code: CodeUnauthorized, Message: "unauthorized"}
+	ErrTooManyRequests      = &APIError{Code: CodeTooManyRequests, Message: "too many requests"}
+	ErrServerBusy           = &APIError{Code: CodeServerBusy, Message: "server is overloaded"}
+	ErrFilterFailure        = &APIError{Code: CodeFilterFailure, Message: "filter failure"}
+	ErrTooManyOrders        = &APIError{Code: CodeTooManyOrders, Message: "too many new orders"}
+	ErrInvalidTimestamp     = &APIError{Code: CodeInvalidTimestamp, Message: "timestamp outside of the recvWindow"}
+	ErrInvalidSignature     = &APIError{Code: CodeInvalidSignature, Message: "invalid signature"}
+	ErrBadSymbol            = &APIError{Code: CodeBadSymbol, Message: "invalid symbol"}
+	ErrInvalidListenKey     = &APIError{Code: CodeInvalidListenKey, Message: "listen key does not exist"}
+	ErrNewOrderRejected     = &APIError{Code: CodeNewOrderRejected, Message: "new order rejected"}
+	ErrCancelRejected       = &APIError{Code: CodeCancelRejected, Message: "cancel rejected"}
+	ErrNoSuchOrder          = &APIError{Code: CodeNoSuchOrder, Message: "order does not exist"}
+	ErrRejectedAPIKey       = &APIError{Code: CodeRejectedAPIKey, Message: "invalid API key, IP or permissions"}
+	ErrBalanceNotSufficient = &APIError{Code: CodeBalanceNotSufficient, Message: "balance is insufficient"}
+	ErrMarginNotSufficient  = &APIError{Code: CodeMarginNotSufficient, Message: "margin is insufficient"}
+	ErrReduceOnlyReject     = &APIError{Code: CodeReduceOnlyReject, Message: "reduce only order rejected"}
+)
+
+var (
+	retryableCodes = codeSet(CodeDisconnected, CodeTooManyRequests, CodeServerBusy,
+		CodeTooManyOrders, CodeServiceShuttingDown, CodeInvalidTimestamp)
+	authCodes = codeSet(CodeUnauthorized, CodeInvalidSignature, CodeNotAuthorized,
+		CodeInvalidAPIKeyID, CodeBadAPIKeyFormat, CodeRejectedAPIKey)
+	insufficientBalanceCodes = codeSet(CodeBalanceNotSufficient, CodeMarginNotSufficient,
+		CodeMarginBalanceNotEnough)
+	filterFailureCodes = codeSet(CodeFilterFailure, CodePriceLessThanZero, CodePriceGreaterThanMax,
+		CodeQtyLessThanZero, CodeQtyLessThanMin, CodeQtyGreaterThanMax, CodePriceLessThanMin,
+		CodePriceNotIncreasedByTick, CodePriceHigherThanMultUp, CodeQtyNotIncreasedByStep,
+		CodePriceLowerThanMultDown, CodeMinNotional)
+)
+
+func codeSet(codes ...ErrorCode) map[ErrorCode]bool {
+	m := make(map[ErrorCode]bool, len(codes))
+	for _, code := range codes {
+		m[code] = true
+	}
+	return m
+}
+
+// IsInvalidTimestamp tell whether err is a -1021 API error
+func IsInvalidTimestamp(err error) bool {
+	apiErr, ok := AsAPIError(err)
+	return ok && apiErr.Code == CodeInvalidTimestamp
+}
+
+// IsRateLimit tell whether err was caused by a request or order rate limit,
+// reported by the server or by the client RateLimiter
+func IsRateLimit(err error) bool {
+	if errors.Is(err, ErrRateLimited) {
+		return true
+	}
+	apiErr, ok := AsAPIError(err)
+	if !ok {
+		return false
+	}
+	return apiErr.Code == CodeTooManyRequests || apiErr.Code == CodeTooManyOrders ||
+		apiErr.StatusCode == http.StatusTooManyRequests || apiErr.StatusCode == http.StatusTeapot
+}
+
+// IsRetryable tell whether the request was rejected for a transient reason
+// before being executed, so the same request can be sent again once rate
+// limits reset or the clock is synced. Errors with an unknown execution
+// status, such as -1006, -1007 or a 5xx without a code, are not retryable
+// since an order may have been placed.
+func IsRetryable(err error) bool {
+	if IsRateLimit(err) {
+		return true
+	}
+	apiErr, ok := AsAPIError(err)
+	return ok && retryableCodes[apiErr.Code]
+}
+
+// IsAuthError tell whether err was caused by the API key, its permissions or
+// the signature
+func IsAuthError(err error) bool {
+	apiErr, ok := AsAPIError(err)
+	if !ok {
+		return false
+	}
+	return authCodes[apiErr.Code] || apiErr.StatusCode == http.StatusUnauthorized
+}
+
+// IsInsufficientBalance tell whether an order was rejected because of the
+// account balance or margin
+func IsInsufficientBalance(err error) bool {
+	apiErr, ok := AsAPIError(err)
+	if !ok {
+		return false
+	}
+	if insufficientBalanceCodes[apiErr.Code] {
+		return true
+	}
+	// spot reports it as a rejected order
+	return apiErr.Code == CodeNewOrderRejected &&
+		strings.Contains(strings.ToLower(apiErr.Message), "insufficient balance")
+}
+
+// IsFilterFailure tell whether an order was rejected by a symbol filter such
+// as PRICE_FILTER, LOT_SIZE or MIN_NOTIONAL, or by a pre-flight check
+func IsFilterFailure(err error) bool {
+	var filterErr *FilterError
+	if errors.As(err, &filterErr) {
+		return true
+	}
+	apiErr, ok := AsAPIError(err)
+	if !ok {
+		return false
+	}
+	return filterFailureCodes[apiErr.Code] || strings.HasPrefix(apiErr.Message, "Filter failure")
+}
+
+// IsUnknownOrder tell whether a query or cancel targeted an order which does
+// not exist or is not open anymore
+func IsUnknownOrder(err error) bool {
+	apiErr, ok := AsAPIError(err)
+	if !ok {
+		return false
+	}
+	if apiErr.Code == CodeNoSuchOrder {
+		return true
+	}
+	return apiErr.Code == CodeCancelRejected && strings.Contains(apiErr.Message, "Unknown order")
+}
@@ -0,0 +1,72 @@
+package common
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsAPIError(t *testing.T) {
+	assert := assert.New(t)
+	assert.True(IsAPIError(&APIError{Code: CodeUnknown}))
+	assert.True(IsAPIError(APIError{Code: CodeUnknown}))
+	assert.True(IsAPIError(fmt.Errorf("wrapped: %w", &APIError{Code: CodeUnknown})))
+	assert.False(IsAPIError(errors.New("dummy error")))
+	assert.False(IsAPIError(nil))
+
+	apiErr, ok := AsAPIError(fmt.Errorf("wrapped: %w", APIError{Code: CodeBadSymbol, Endpoint: "/api/v3/order"}))
+	assert.True(ok)
+	assert.Equal(CodeBadSymbol, apiErr.Code)
+	assert.Equal("/api/v3/order", apiErr.Endpoint)
+}
+
+func TestAPIErrorIs(t *testing.T) {
+	assert := assert.New(t)
+	err := fmt.Errorf("wrapped: %w", &APIError{Code: CodeNoSuchOrder, Message: "Order does not exist."})
+	assert.True(errors.Is(err, ErrNoSuchOrder))
+	assert.False(errors.Is(err, ErrCancelRejected))
+	assert.True(errors.Is(APIError{Code: CodeInvalidTimestamp}, ErrInvalidTimestamp))
+}
+
+func TestErrorClassification(t *testing.T) {
+	tests := []struct {
+		name                string
+		err                 error
+		retryable           bool
+		rateLimit           bool
+		auth                bool
+		insufficientBalance bool
+		filterFailure       bool
+		unknownOrder        bool
+	}{
+		{name: "too many requests", err: &APIError{Code: CodeTooManyRequests}, retryable: true, rateLimit: true},
+		{name: "banned", err: &APIError{StatusCode: http.StatusTeapot}, retryable: true, rateLimit: true},
+		{name: "local rate limit", err: &RateLimitError{Type: RateLimitRequestWeight}, retryable: true, rateLimit: true},
+		{name: "server busy", err: &APIError{Code: CodeServerBusy, StatusCode: http.StatusServiceUnavailable}, retryable: true},
+		{name: "invalid timestamp", err: &APIError{Code: CodeInvalidTimestamp}, retryable: true},
+		{name: "unknown execution", err: &APIError{Code: CodeTimeout, StatusCode: http.StatusServiceUnavailable}},
+		{name: "invalid signature", err: &APIError{Code: CodeInvalidSignature}, auth: true},
+		{name: "unauthorized status", err: &APIError{StatusCode: http.StatusUnauthorized}, auth: true},
+		{name: "spot balance", err: &APIError{Code: CodeNewOrderRejected, Message: "Account has insufficient balance for requested action."}, insufficientBalance: true},
+		{name: "futures margin", err: &APIError{Code: CodeMarginNotSufficient}, insufficientBalance: true},
+		{name: "spot filter", err: &APIError{Code: CodeNewOrderRejected, Message: "Filter failure: LOT_SIZE"}, filterFailure: true},
+		{name: "futures tick size", err: &APIError{Code: CodePriceNotIncreasedByTick}, filterFailure: true},
+		{name: "no such order", err: &APIError{Code: CodeNoSuchOrder}, unknownOrder: true},
+		{name: "unknown order cancel", err: &APIError{Code: CodeCancelRejected, Message: "Unknown order sent."}, unknownOrder: true},
+		{name: "other error", err: errors.New("dummy error")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert := assert.New(t)
+			assert.Equal(tt.retryable, IsRetryable(tt.err), "retryable")
+			assert.Equal(tt.rateLimit, IsRateLimit(tt.err), "rate limit")
+			assert.Equal(tt.auth, IsAuthError(tt.err), "auth")
+			assert.Equal(tt.insufficientBalance, IsInsufficientBalance(tt.err), "insufficient balance")
+			assert.Equal(tt.filterFailure, IsFilterFailure(tt.err), "filter failure")
+			assert.Equal(tt.unknownOrder, IsUnknownOrder(tt.err), "unknown order")
+		})
+	}
+}
@@ -3,7 +3,8 @@ package common
 import "math"
 import "bytes"
 
-// AmountToLotSize converts an amount to a lot sized amount
+// AmountToLotSize converts an amount to a lot sized amount. It works on
+// float64 and loses precision, Decimal.RoundToStep rounds exactly.
 func AmountToLotSize(lot float64, precision int, amount float64) float64 {
 	return math.Trunc(math.Floor(amount/lot)*lot*math.Pow10(precision)) / math.Pow10(precision)
 }
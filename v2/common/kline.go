@@ -0,0 +1,132 @@
+package common
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// Kline is a kline of any market, the klines of the spot, futures and
+// delivery packages convert to it
+type Kline struct {
+	OpenTime                 int64  `json:"openTime"`
+	Open                     string `json:"open"`
+	High                     string `json:"high"`
+	Low                      string `json:"low"`
+	Close                    string `json:"close"`
+	Volume                   string `json:"volume"`
+	CloseTime                int64  `json:"closeTime"`
+	QuoteAssetVolume         string `json:"quoteAssetVolume"`
+	TradeNum                 int64  `json:"tradeNum"`
+	TakerBuyBaseAssetVolume  string `json:"takerBuyBaseAssetVolume"`
+	TakerBuyQuoteAssetVolume string `json:"takerBuyQuoteAssetVolume"`
+}
+
+// KlineRange is a range of klines, from the open time of the first to the
+// open time of the last one
+type KlineRange struct {
+	Start int64 `json:"start"`
+	End   int64 `json:"end"`
+}
+
+// KlineInterval is a kline interval such as 1m, 4h, 1w or 1M. Intervals up to
+// days are aligned on the Unix epoch, weeks start on Monday and months on
+// their first day, all in UTC.
+type KlineInterval struct {
+	n    int64
+	unit byte
+}
+
+// weekOffset is the open time of the first weekly kline, Monday 1970-01-05
+const weekOffset = 4 * 24 * int64(time.Hour/time.Millisecond)
+
+// ParseKlineInterval parse an interval made of a positive count and a unit
+// among s, m, h, d, w and M, such as 1m or 45m
+func ParseKlineInterval(s string) (KlineInterval, error) {
+	if len(s) < 2 {
+		return KlineInterval{}, fmt.Errorf("invalid kline interval %q", s)
+	}
+	n, err := strconv.ParseInt(s[:len(s)-1], 10, 64)
+	if err != nil || n <= 0 {
+		return KlineInterval{}, fmt.Errorf("invalid kline interval %q", s)
+	}
+	switch unit := s[len(s)-1]; unit {
+	case 's', 'm', 'h', 'd', 'w', 'M':
+		return KlineInterval{n: n, unit: unit}, nil
+	}
+	return KlineInterval{}, fmt.Errorf("invalid kline interval %q", s)
+}
+
+// String return the interval as accepted by the API
+func (i KlineInterval) String() string {
+	return strconv.FormatInt(i.n, 10) + string(i.unit)
+}
+
+// Duration return the fixed duration of the interval, 0 for months
+func (i KlineInterval) Duration() time.Duration {
+	var unit time.Duration
+	switch i.unit {
+	case 's':
+		unit = time.Second
+	case 'm':
+		unit = time.Minute
+	case 'h':
+		unit = time.Hour
+	case 'd':
+		unit = 24 * time.Hour
+	case 'w':
+		unit = 7 * 24 * time.Hour
+	}
+	return time.Duration(i.n) * unit
+}
+
+// Truncate return the open time of the kline containing t, in ms
+func (i KlineInterval) Truncate(t int64) int64 {
+	if i.unit == 'M' {
+		date := time.UnixMilli(t).UTC()
+		months := int64(date.Year())*12 + int64(date.Month()) - 1
+		months -= floorMod(months, i.n)
+		return time.Date(int(months/12), time.Month(months%12+1), 1, 0, 0, 0, 0, time.UTC).UnixMilli()
+	}
+	d := i.Duration().Milliseconds()
+	var offset int64
+	if i.unit == 'w' {
+		offset = weekOffset
+	}
+	return t - floorMod(t-offset, d)
+}
+
+// Add return the open time of the kline n intervals after the one opening
+// at open
+func (i KlineInterval) Add(open int64, n int64) int64 {
+	if i.unit == 'M' {
+		return time.UnixMilli(open).UTC().AddDate(0, int(n*i.n), 0).UnixMilli()
+	}
+	return open + n*i.Duration().Milliseconds()
+}
+
+// Next return the open time of the kline following the one opening at open
+func (i KlineInterval) Next(open int64) int64 {
+	return i.Add(open, 1)
+}
+
+// Count return the number of klines opening in r
+func (i KlineInterval) Count(r KlineRange) int64 {
+	if r.End < r.Start {
+		return 0
+	}
+	if i.unit == 'M' {
+		start, end := time.UnixMilli(r.Start).UTC(), time.UnixMilli(r.End).UTC()
+		months := int64(end.Year()-start.Year())*12 + int64(end.Month()-start.Month())
+		return months/i.n + 1
+	}
+	return (r.End-r.Start)/i.Duration().Milliseconds() + 1
+}
+
+func floorMod(a, b int64) int64 {
+	m := a % b
+	if m < 0 {
+		m += b
+	}
+	return m
+}
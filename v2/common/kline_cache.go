@@ -0,0 +1,214 @@
+package common
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// klineCSVHeader is the header of the kline files of a KlineCache
+var klineCSVHeader = []string{
+	"open_time", "open", "high", "low", "close", "volume", "close_time",
+	"quote_asset_volume", "trade_num", "taker_buy_base_asset_volume", "taker_buy_quote_asset_volume",
+}
+
+// KlineCache store klines on disk, one CSV file per market, symbol and
+// interval under Dir, along with a JSON file recording the ranges already
+// downloaded and the gaps found in them
+//
+//	<Dir>/<market>/<symbol>/<interval>.csv
+//	<Dir>/<market>/<symbol>/<interval>.json
+type KlineCache struct {
+	Dir string
+
+	mu    sync.Mutex
+	locks map[string]*sync.Mutex
+}
+
+// NewKlineCache create a cache storing its files under dir
+func NewKlineCache(dir string) *KlineCache {
+	return &KlineCache{Dir: dir}
+}
+
+// klineCacheEntry is the content cached for a market, symbol and interval
+type klineCacheEntry struct {
+	klines map[int64]*Kline
+	// Covered is the ranges already downloaded, Gaps the ranges in them
+	// without klines
+	Covered []KlineRange `json:"covered"`
+	Gaps    []KlineRange `json:"gaps"`
+}
+
+func newKlineCacheEntry() *klineCacheEntry {
+	return &klineCacheEntry{klines: make(map[int64]*Kline)}
+}
+
+// path return the path of the files of an entry without extension, months
+// are spelled mo so that 1M does not clash with 1m on case-insensitive
+// file systems
+func (c *KlineCache) path(market, symbol, interval string) string {
+	if strings.HasSuffix(interval, "M") {
+		interval = strings.TrimSuffix(interval, "M") + "mo"
+	}
+	return filepath.Join(c.Dir, market, symbol, interval)
+}
+
+// lock lock the entry of a market, symbol and interval and return the
+// function unlocking it. A download holds it from load to save, so that
+// concurrent downloads of the entry do not overwrite each other's klines.
+func (c *KlineCache) lock(market, symbol, interval string) (unlock func()) {
+	path := c.path(market, symbol, interval)
+	c.mu.Lock()
+	if c.locks == nil {
+		c.locks = make(map[string]*sync.Mutex)
+	}
+	l, ok := c.locks[path]
+	if !ok {
+		l = new(sync.Mutex)
+		c.locks[path] = l
+	}
+	c.mu.Unlock()
+	l.Lock()
+	return l.Unlock
+}
+
+// load read an entry, which is empty when nothing was cached yet, the entry
+// must be locked
+func (c *KlineCache) load(market, symbol, interval string) (*klineCacheEntry, error) {
+	path := c.path(market, symbol, interval)
+	entry := newKlineCacheEntry()
+	meta, err := os.ReadFile(path + ".json")
+	if errors.Is(err, fs.ErrNotExist) {
+		return entry, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err = json.Unmarshal(meta, entry); err != nil {
+		return nil, fmt.Errorf("kline cache %s: %w", path, err)
+	}
+	f, err := os.Open(path + ".csv")
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	records, err := csv.NewReader(f).ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("kline cache %s: %w", path, err)
+	}
+	for i, record := range records {
+		if i == 0 {
+			continue
+		}
+		kline, err := parseKlineRecord(record)
+		if err != nil {
+			return nil, fmt.Errorf("kline cache %s line %d: %w", path, i+1, err)
+		}
+		entry.klines[kline.OpenTime] = kline
+	}
+	return entry, nil
+}
+
+// save write an entry, replacing the previous files atomically, the entry
+// must be locked
+func (c *KlineCache) save(market, symbol, interval string, entry *klineCacheEntry) error {
+	path := c.path(market, symbol, interval)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	klines := entry.sorted()
+	records := make([][]string, 0, len(klines)+1)
+	records = append(records, klineCSVHeader)
+	for _, kline := range klines {
+		records = append(records, formatKlineRecord(kline))
+	}
+	err := writeFileAtomic(path+".csv", func(f *os.File) error {
+		w := csv.NewWriter(f)
+		if err := w.WriteAll(records); err != nil {
+			return err
+		}
+		return w.Error()
+	})
+	if err != nil {
+		return err
+	}
+	// The ranges are written last so that they never cover missing klines
+	return writeFileAtomic(path+".json", func(f *os.File) error {
+		return json.NewEncoder(f).Encode(entry)
+	})
+}
+
+// sorted return the klines of the entry by open time
+func (e *klineCacheEntry) sorted() []*Kline {
+	klines := make([]*Kline, 0, len(e.klines))
+	for _, kline := range e.klines {
+		klines = append(klines, kline)
+	}
+	sort.Slice(klines, func(i, j int) bool {
+		return klines[i].OpenTime < klines[j].OpenTime
+	})
+	return klines
+}
+
+func writeFileAtomic(path string, write func(f *os.File) error) error {
+	f, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	if err = write(f); err != nil {
+		f.Close()
+		return err
+	}
+	if err = f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
+
+func formatKlineRecord(k *Kline) []string {
+	return []string{
+		strconv.FormatInt(k.OpenTime, 10), k.Open, k.High, k.Low, k.Close, k.Volume,
+		strconv.FormatInt(k.CloseTime, 10), k.QuoteAssetVolume, strconv.FormatInt(k.TradeNum, 10),
+		k.TakerBuyBaseAssetVolume, k.TakerBuyQuoteAssetVolume,
+	}
+}
+
+func parseKlineRecord(record []string) (*Kline, error) {
+	if len(record) != len(klineCSVHeader) {
+		return nil, fmt.Errorf("expected %d fields, got %d", len(klineCSVHeader), len(record))
+	}
+	openTime, err := strconv.ParseInt(record[0], 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	closeTime, err := strconv.ParseInt(record[6], 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	tradeNum, err := strconv.ParseInt(record[8], 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	return &Kline{
+		OpenTime:                 openTime,
+		Open:                     record[1],
+		High:                     record[2],
+		Low:                      record[3],
+		Close:                    record[4],
+		Volume:                   record[5],
+		CloseTime:                closeTime,
+		QuoteAssetVolume:         record[7],
+		TradeNum:                 tradeNum,
+		TakerBuyBaseAssetVolume:  record[9],
+		TakerBuyQuoteAssetVolume: record[10],
+	}, nil
+}
@@ -0,0 +1,240 @@
+package common
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// KlineFetchFunc request the klines of symbol opening between startTime and
+// endTime, in ms, limit is the number of klines expected
+type KlineFetchFunc func(ctx context.Context, symbol, interval string, startTime, endTime int64, limit int) ([]*Kline, error)
+
+// KlineDownloader download the klines of any range, splitting it into
+// requests of Limit klines run by Concurrency workers. The requests go
+// through the client, so set its RateLimiter to bound them by weight.
+//
+// With a Cache, only the ranges not downloaded yet are requested and the
+// gaps found in the ranges already downloaded are reported again.
+type KlineDownloader struct {
+	// Market names the market in the cache, such as spot or futures
+	Market string
+	Fetch  KlineFetchFunc
+	// Limit is the maximum number of klines returned by a request
+	Limit int
+	// Concurrency is the number of requests in flight, 4 by default
+	Concurrency int
+	// Cache stores the klines on disk, nil disables it
+	Cache *KlineCache
+
+	now func() time.Time
+}
+
+// KlineSeries is the result of a download
+type KlineSeries struct {
+	// Klines are the closed klines of the range sorted by open time
+	Klines []*Kline
+	// Gaps are the ranges without klines, such as exchange halts or the
+	// time before the listing of the symbol
+	Gaps []KlineRange
+}
+
+// klineChunk is one request of a download
+type klineChunk struct {
+	r      KlineRange
+	klines []*Kline
+	err    error
+}
+
+// Download return the closed klines of symbol opening between start and
+// end, along with the gaps of the range. On error, the klines downloaded so
+// far are still cached.
+func (d *KlineDownloader) Download(ctx context.Context, symbol, interval string, start, end time.Time) (*KlineSeries, error) {
+	iv, err := ParseKlineInterval(interval)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now
+	if d.now != nil {
+		now = d.now
+	}
+	// Only closed klines are downloaded, so that they can be cached
+	r := KlineRange{Start: iv.Truncate(start.UnixMilli()), End: iv.Truncate(end.UnixMilli())}
+	if r.Start < start.UnixMilli() {
+		r.Start = iv.Next(r.Start)
+	}
+	if current := iv.Truncate(now().UnixMilli()); r.End >= current {
+		r.End = iv.Add(current, -1)
+	}
+	series := new(KlineSeries)
+	if r.Start > r.End {
+		return series, nil
+	}
+
+	entry := newKlineCacheEntry()
+	if d.Cache != nil {
+		defer d.Cache.lock(d.Market, symbol, interval)()
+		entry, err = d.Cache.load(d.Market, symbol, interval)
+		if err != nil {
+			return nil, err
+		}
+	}
+	chunks := d.split(iv, missingKlineRanges(iv, r, entry.Covered))
+	d.fetch(ctx, symbol, interval, chunks)
+	for _, chunk := range chunks {
+		if chunk.err != nil {
+			if err == nil {
+				err = chunk.err
+			}
+			continue
+		}
+		received := make(map[int64]bool, len(chunk.klines))
+		for _, kline := range chunk.klines {
+			if kline.OpenTime >= chunk.r.Start && kline.OpenTime <= chunk.r.End {
+				entry.klines[kline.OpenTime] = kline
+				received[kline.OpenTime] = true
+			}
+		}
+		entry.Gaps = append(entry.Gaps, klineGaps(iv, chunk.r, received)...)
+		entry.Covered = append(entry.Covered, chunk.r)
+	}
+	entry.Covered = mergeKlineRanges(iv, entry.Covered)
+	entry.Gaps = mergeKlineRanges(iv, entry.Gaps)
+	if d.Cache != nil && len(chunks) > 0 {
+		if serr := d.Cache.save(d.Market, symbol, interval, entry); serr != nil && err == nil {
+			err = serr
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	for _, kline := range entry.sorted() {
+		if kline.OpenTime >= r.Start && kline.OpenTime <= r.End {
+			series.Klines = append(series.Klines, kline)
+		}
+	}
+	for _, gap := range entry.Gaps {
+		if gap.End >= r.Start && gap.Start <= r.End {
+			series.Gaps = append(series.Gaps, KlineRange{Start: max(gap.Start, r.Start), End: min(gap.End, r.End)})
+		}
+	}
+	return series, nil
+}
+
+// split cut the ranges into chunks of Limit klines
+func (d *KlineDownloader) split(iv KlineInterval, ranges []KlineRange) []*klineChunk {
+	limit := int64(d.Limit)
+	if limit <= 0 {
+		limit = 500
+	}
+	var chunks []*klineChunk
+	for _, r := range ranges {
+		for start := r.Start; start <= r.End; {
+			end := iv.Add(start, limit-1)
+			if end > r.End {
+				end = r.End
+			}
+			chunks = append(chunks, &klineChunk{r: KlineRange{Start: start, End: end}})
+			start = iv.Next(end)
+		}
+	}
+	return chunks
+}
+
+// fetch run the requests of the chunks, the first error cancels the others
+func (d *KlineDownloader) fetch(ctx context.Context, symbol, interval string, chunks []*klineChunk) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	concurrency := d.Concurrency
+	if concurrency <= 0 {
+		concurrency = 4
+	}
+	iv, _ := ParseKlineInterval(interval)
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for _, chunk := range chunks {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			chunk.err = ctx.Err()
+			continue
+		}
+		wg.Add(1)
+		go func(chunk *klineChunk) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			// endTime is the close time of the last kline, whether the
+			// endpoint filters on the open or the close time
+			limit := int(iv.Count(chunk.r))
+			chunk.klines, chunk.err = d.Fetch(ctx, symbol, interval, chunk.r.Start, iv.Next(chunk.r.End)-1, limit)
+			if chunk.err != nil {
+				cancel()
+			}
+		}(chunk)
+	}
+	wg.Wait()
+}
+
+// missingKlineRanges return the parts of r not covered yet, covered being
+// sorted and merged
+func missingKlineRanges(iv KlineInterval, r KlineRange, covered []KlineRange) []KlineRange {
+	var missing []KlineRange
+	next := r.Start
+	for _, c := range covered {
+		if c.End < next {
+			continue
+		}
+		if c.Start > r.End {
+			break
+		}
+		if c.Start > next {
+			missing = append(missing, KlineRange{Start: next, End: iv.Add(c.Start, -1)})
+		}
+		next = iv.Next(c.End)
+	}
+	if next <= r.End {
+		missing = append(missing, KlineRange{Start: next, End: r.End})
+	}
+	return missing
+}
+
+// klineGaps return the ranges of r without received klines
+func klineGaps(iv KlineInterval, r KlineRange, received map[int64]bool) []KlineRange {
+	var gaps []KlineRange
+	for open := r.Start; open <= r.End; open = iv.Next(open) {
+		if received[open] {
+			continue
+		}
+		if n := len(gaps); n > 0 && iv.Next(gaps[n-1].End) == open {
+			gaps[n-1].End = open
+		} else {
+			gaps = append(gaps, KlineRange{Start: open, End: open})
+		}
+	}
+	return gaps
+}
+
+// mergeKlineRanges sort the ranges and merge the overlapping or adjacent ones
+func mergeKlineRanges(iv KlineInterval, ranges []KlineRange) []KlineRange {
+	if len(ranges) == 0 {
+		return ranges
+	}
+	sort.Slice(ranges, func(i, j int) bool {
+		return ranges[i].Start < ranges[j].Start
+	})
+	merged := ranges[:1]
+	for _, r := range ranges[1:] {
+		last := &merged[len(merged)-1]
+		if r.Start <= iv.Next(last.End) {
+			if r.End > last.End {
+				last.End = r.End
+			}
+			continue
+		}
+		merged = append(merged, r)
+	}
+	return merged
+}
@@ -0,0 +1,148 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type klineDownloaderTestSuite struct {
+	suite.Suite
+	mu sync.Mutex
+	// halted is the range the exchange has no klines for
+	halted   KlineRange
+	requests []KlineRange
+	fail     bool
+	d        *KlineDownloader
+}
+
+func TestKlineDownloader(t *testing.T) {
+	suite.Run(t, new(klineDownloaderTestSuite))
+}
+
+func (s *klineDownloaderTestSuite) SetupTest() {
+	s.requests, s.fail = nil, false
+	s.halted = KlineRange{Start: ms("2024-01-01T10:00:00Z"), End: ms("2024-01-01T10:04:00Z")}
+	s.d = &KlineDownloader{
+		Market:      "spot",
+		Fetch:       s.fetch,
+		Limit:       100,
+		Concurrency: 2,
+		Cache:       NewKlineCache(s.T().TempDir()),
+		now:         func() time.Time { return time.UnixMilli(ms("2024-01-02T00:00:30Z")) },
+	}
+}
+
+func (s *klineDownloaderTestSuite) fetch(ctx context.Context, symbol, interval string, startTime, endTime int64, limit int) ([]*Kline, error) {
+	s.Equal("BTCUSDT", symbol)
+	s.Equal("1m", interval)
+	s.mu.Lock()
+	s.requests = append(s.requests, KlineRange{Start: startTime, End: endTime})
+	s.mu.Unlock()
+	if s.fail {
+		return nil, errors.New("fetch failed")
+	}
+	var klines []*Kline
+	for open := startTime; open <= endTime && len(klines) < limit; open += time.Minute.Milliseconds() {
+		if open >= s.halted.Start && open <= s.halted.End {
+			continue
+		}
+		klines = append(klines, &Kline{
+			OpenTime:  open,
+			Open:      "1.0",
+			Close:     time.UnixMilli(open).UTC().Format("1504"),
+			CloseTime: open + time.Minute.Milliseconds() - 1,
+			TradeNum:  1,
+		})
+	}
+	return klines, nil
+}
+
+func (s *klineDownloaderTestSuite) TestDownload() {
+	r := s.Require()
+	start, end := time.UnixMilli(ms("2024-01-01T08:00:00Z")), time.UnixMilli(ms("2024-01-01T11:59:59Z"))
+	series, err := s.d.Download(context.Background(), "BTCUSDT", "1m", start, end)
+	r.NoError(err)
+	r.Len(series.Klines, 240-5)
+	r.Equal(ms("2024-01-01T08:00:00Z"), series.Klines[0].OpenTime)
+	r.Equal(ms("2024-01-01T11:59:00Z"), series.Klines[len(series.Klines)-1].OpenTime)
+	r.Equal([]KlineRange{s.halted}, series.Gaps)
+	// 240 klines in requests of 100
+	r.Len(s.requests, 3)
+
+	// Only the missing range is requested, the gap comes from the cache
+	s.requests = nil
+	end = time.UnixMilli(ms("2024-01-01T12:29:59Z"))
+	series, err = s.d.Download(context.Background(), "BTCUSDT", "1m", start, end)
+	r.NoError(err)
+	r.Len(series.Klines, 270-5)
+	r.Equal([]KlineRange{s.halted}, series.Gaps)
+	r.Equal([]KlineRange{{Start: ms("2024-01-01T12:00:00Z"), End: ms("2024-01-01T12:29:59.999Z")}}, s.requests)
+
+	// A cached range is served without requests
+	s.requests = nil
+	series, err = s.d.Download(context.Background(), "BTCUSDT", "1m",
+		time.UnixMilli(ms("2024-01-01T09:58:00Z")), time.UnixMilli(ms("2024-01-01T10:06:00Z")))
+	r.NoError(err)
+	r.Len(series.Klines, 4)
+	r.Equal("0958", series.Klines[0].Close)
+	r.Equal([]KlineRange{s.halted}, series.Gaps)
+	r.Empty(s.requests)
+
+	_, err = os.Stat(filepath.Join(s.d.Cache.Dir, "spot", "BTCUSDT", "1m.csv"))
+	r.NoError(err)
+}
+
+func (s *klineDownloaderTestSuite) TestDownloadOpenKline() {
+	r := s.Require()
+	// The kline opening at 00:00 is not closed yet
+	series, err := s.d.Download(context.Background(), "BTCUSDT", "1m",
+		time.UnixMilli(ms("2024-01-01T23:58:00Z")), time.UnixMilli(ms("2024-01-02T00:10:00Z")))
+	r.NoError(err)
+	r.Len(series.Klines, 2)
+	r.Equal(ms("2024-01-01T23:59:00Z"), series.Klines[1].OpenTime)
+}
+
+func (s *klineDownloaderTestSuite) TestDownloadError() {
+	r := s.Require()
+	s.fail = true
+	start, end := time.UnixMilli(ms("2024-01-01T08:00:00Z")), time.UnixMilli(ms("2024-01-01T09:00:00Z"))
+	_, err := s.d.Download(context.Background(), "BTCUSDT", "1m", start, end)
+	r.EqualError(err, "fetch failed")
+
+	// Nothing was cached
+	s.fail, s.requests = false, nil
+	series, err := s.d.Download(context.Background(), "BTCUSDT", "1m", start, end)
+	r.NoError(err)
+	r.Len(series.Klines, 61)
+	r.Len(s.requests, 1)
+}
+
+func (s *klineDownloaderTestSuite) TestConcurrentDownloads() {
+	r := s.Require()
+	var wg sync.WaitGroup
+	for _, hour := range []string{"08", "09"} {
+		wg.Add(1)
+		go func(hour string) {
+			defer wg.Done()
+			start, end := time.UnixMilli(ms("2024-01-01T"+hour+":00:00Z")), time.UnixMilli(ms("2024-01-01T"+hour+":59:59Z"))
+			_, err := s.d.Download(context.Background(), "BTCUSDT", "1m", start, end)
+			s.NoError(err)
+		}(hour)
+	}
+	wg.Wait()
+
+	// Both downloads were cached
+	s.requests = nil
+	series, err := s.d.Download(context.Background(), "BTCUSDT", "1m",
+		time.UnixMilli(ms("2024-01-01T08:00:00Z")), time.UnixMilli(ms("2024-01-01T09:59:59Z")))
+	r.NoError(err)
+	r.Len(series.Klines, 120)
+	r.Empty(s.requests)
+}
@@ -0,0 +1,54 @@
+package common
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func ms(s string) int64 {
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		panic(err)
+	}
+	return t.UnixMilli()
+}
+
+func TestParseKlineInterval(t *testing.T) {
+	assert := assert.New(t)
+	for _, s := range []string{"1s", "1m", "45m", "4h", "3d", "1w", "1M"} {
+		iv, err := ParseKlineInterval(s)
+		assert.NoError(err)
+		assert.Equal(s, iv.String())
+	}
+	for _, s := range []string{"", "m", "0m", "-1m", "1y", "1.5h"} {
+		_, err := ParseKlineInterval(s)
+		assert.Error(err, s)
+	}
+}
+
+func TestKlineInterval(t *testing.T) {
+	assert := assert.New(t)
+	r := require.New(t)
+	iv, err := ParseKlineInterval("45m")
+	r.NoError(err)
+	assert.Equal(45*time.Minute, iv.Duration())
+	assert.Equal(ms("2024-03-05T11:15:00Z"), iv.Truncate(ms("2024-03-05T11:59:59Z")))
+	assert.Equal(ms("2024-03-05T12:00:00Z"), iv.Next(ms("2024-03-05T11:15:00Z")))
+	assert.Equal(int64(3), iv.Count(KlineRange{Start: ms("2024-03-05T11:15:00Z"), End: ms("2024-03-05T12:45:00Z")}))
+
+	iv, err = ParseKlineInterval("1w")
+	r.NoError(err)
+	// Weeks start on Monday
+	assert.Equal(ms("2024-03-04T00:00:00Z"), iv.Truncate(ms("2024-03-10T23:00:00Z")))
+
+	iv, err = ParseKlineInterval("1M")
+	r.NoError(err)
+	assert.Zero(iv.Duration())
+	assert.Equal(ms("2024-02-01T00:00:00Z"), iv.Truncate(ms("2024-02-29T12:00:00Z")))
+	assert.Equal(ms("2024-03-01T00:00:00Z"), iv.Next(ms("2024-02-01T00:00:00Z")))
+	assert.Equal(ms("2023-12-01T00:00:00Z"), iv.Add(ms("2024-02-01T00:00:00Z"), -2))
+	assert.Equal(int64(14), iv.Count(KlineRange{Start: ms("2023-01-01T00:00:00Z"), End: ms("2024-02-01T00:00:00Z")}))
+}
@@ -0,0 +1,149 @@
+package common
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"regexp"
+	"time"
+)
+
+// Redacted replace secrets in logs
+const Redacted = "REDACTED"
+
+// redactedKeys are the attribute keys whose value is always redacted
+var redactedKeys = map[string]bool{
+	"apiKey":    true,
+	"secretKey": true,
+	"signature": true,
+	"listenKey": true,
+}
+
+var (
+	// redactedParamRe match the signature and listen key parameters of a URL
+	redactedParamRe = regexp.MustCompile(`(signature|listenKey)=[A-Za-z0-9%+/=_-]*`)
+	// listenKeyPathRe match a listen key in the path of a websocket endpoint,
+	// or in the streams of a combined endpoint
+	listenKeyPathRe = regexp.MustCompile(`(/|streams=)[A-Za-z0-9]{60,}`)
+	// listenKeyJSONRe match the listen key of a user stream response body
+	listenKeyJSONRe = regexp.MustCompile(`"listenKey"\s*:\s*"[^"]*"`)
+)
+
+// RedactString hide the signatures and listen keys of the URLs, websocket
+// endpoints and response bodies found in s
+func RedactString(s string) string {
+	s = redactedParamRe.ReplaceAllString(s, "${1}="+Redacted)
+	s = listenKeyJSONRe.ReplaceAllString(s, `"listenKey":"`+Redacted+`"`)
+	return listenKeyPathRe.ReplaceAllString(s, "${1}"+Redacted)
+}
+
+// NewRedactHandler wrap h so that the records it handles do not leak
+// secrets: attributes named apiKey, secretKey, signature or listenKey are
+// redacted, and so are signatures and listen keys in string values.
+// Loggers created by the clients always use it.
+func NewRedactHandler(h slog.Handler) slog.Handler {
+	if _, ok := h.(*redactHandler); ok {
+		return h
+	}
+	return &redactHandler{h: h}
+}
+
+type redactHandler struct {
+	h slog.Handler
+}
+
+func (h *redactHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.h.Enabled(ctx, level)
+}
+
+func (h *redactHandler) Handle(ctx context.Context, r slog.Record) error {
+	redacted := slog.NewRecord(r.Time, r.Level, RedactString(r.Message), r.PC)
+	r.Attrs(func(a slog.Attr) bool {
+		redacted.AddAttrs(redactAttr(a))
+		return true
+	})
+	return h.h.Handle(ctx, redacted)
+}
+
+func (h *redactHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	redacted := make([]slog.Attr, len(attrs))
+	for i, a := range attrs {
+		redacted[i] = redactAttr(a)
+	}
+	return &redactHandler{h: h.h.WithAttrs(redacted)}
+}
+
+func (h *redactHandler) WithGroup(name string) slog.Handler {
+	return &redactHandler{h: h.h.WithGroup(name)}
+}
+
+func redactAttr(a slog.Attr) slog.Attr {
+	if redactedKeys[a.Key] {
+		return slog.String(a.Key, Redacted)
+	}
+	switch a.Value.Kind() {
+	case slog.KindString:
+		return slog.String(a.Key, RedactString(a.Value.String()))
+	case slog.KindGroup:
+		attrs := a.Value.Group()
+		redacted := make([]slog.Attr, len(attrs))
+		for i, attr := range attrs {
+			redacted[i] = redactAttr(attr)
+		}
+		return slog.Attr{Key: a.Key, Value: slog.GroupValue(redacted...)}
+	case slog.KindLogValuer:
+		return redactAttr(slog.Attr{Key: a.Key, Value: a.Value.Resolve()})
+	case slog.KindAny:
+		if err, ok := a.Value.Any().(error); ok {
+			return slog.String(a.Key, RedactString(err.Error()))
+		}
+	}
+	return a
+}
+
+// discardHandler drop every record, it stands for a nil handler
+type discardHandler struct{}
+
+func (discardHandler) Enabled(context.Context, slog.Level) bool  { return false }
+func (discardHandler) Handle(context.Context, slog.Record) error { return nil }
+func (h discardHandler) WithAttrs([]slog.Attr) slog.Handler      { return h }
+func (h discardHandler) WithGroup(string) slog.Handler           { return h }
+
+// NewLogger return a logger writing to h with secrets redacted, records are
+// dropped when h is nil
+func NewLogger(h slog.Handler) *slog.Logger {
+	if h == nil {
+		return slog.New(discardHandler{})
+	}
+	return slog.New(NewRedactHandler(h))
+}
+
+// LogRequest log a REST request at debug level when it succeeded, at warn
+// level when it was rejected with a 4xx status and at error level otherwise
+func LogRequest(ctx context.Context, logger *slog.Logger, method, endpoint string, weight int64, status int, latency time.Duration, err error) {
+	level := slog.LevelDebug
+	switch {
+	case err != nil && status == 0, status >= http.StatusInternalServerError:
+		level = slog.LevelError
+	case status >= http.StatusBadRequest:
+		level = slog.LevelWarn
+	}
+	if !logger.Enabled(ctx, level) {
+		return
+	}
+	attrs := []slog.Attr{
+		slog.String("method", method),
+		slog.String("endpoint", endpoint),
+		slog.Int64("weight", weight),
+		slog.Duration("latency", latency),
+	}
+	if status != 0 {
+		attrs = append(attrs, slog.Int("status", status))
+	}
+	if apiErr, ok := AsAPIError(err); ok {
+		attrs = append(attrs, slog.Int64("code", int64(apiErr.Code)), slog.String("msg", apiErr.Message))
+	} else if err != nil {
+		attrs = append(attrs, slog.Any("error", err))
+	}
+	logger.LogAttrs(ctx, level, "binance request", attrs...)
+}
@@ -0,0 +1,76 @@
+package common
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testListenKey = "pqia91ma19a5s61cv6a81va65sdf19v8a65a1a5s61cv6a81va65sdf19v8a65a1"
+
+func TestRedactString(t *testing.T) {
+	assert := assert.New(t)
+	assert.Equal("https://api.binance.com/api/v3/order?symbol=BTCUSDT&timestamp=1&signature=REDACTED",
+		RedactString("https://api.binance.com/api/v3/order?symbol=BTCUSDT&timestamp=1&signature=0a1b2c"))
+	assert.Equal("PUT /api/v3/userDataStream?listenKey=REDACTED",
+		RedactString("PUT /api/v3/userDataStream?listenKey="+testListenKey))
+	assert.Equal("wss://stream.binance.com:9443/ws/REDACTED",
+		RedactString("wss://stream.binance.com:9443/ws/"+testListenKey))
+	assert.Equal("wss://stream.binance.com:9443/stream?streams=REDACTED",
+		RedactString("wss://stream.binance.com:9443/stream?streams="+testListenKey))
+	assert.Equal("wss://stream.binance.com:9443/stream?streams=btcusdt@trade/REDACTED",
+		RedactString("wss://stream.binance.com:9443/stream?streams=btcusdt@trade/"+testListenKey))
+	assert.Equal("wss://stream.binance.com:9443/ws/btcusdt@kline_1m",
+		RedactString("wss://stream.binance.com:9443/ws/btcusdt@kline_1m"))
+	assert.Equal(`{"listenKey":"REDACTED"}`,
+		RedactString(`{"listenKey": "`+testListenKey+`"}`))
+}
+
+func TestRedactHandler(t *testing.T) {
+	assert := assert.New(t)
+	buf := new(bytes.Buffer)
+	logger := NewLogger(slog.NewJSONHandler(buf, nil)).With("apiKey", "myAPIKey")
+	logger.Info("connected /ws/"+testListenKey,
+		"listenKey", testListenKey,
+		"stream", "wss://fstream.binance.com/ws/"+testListenKey,
+		slog.Group("request", "signature", "0a1b2c", "symbol", "BTCUSDT"),
+		"error", errors.New("GET /api/v3/account?signature=0a1b2c: EOF"),
+	)
+	out := buf.String()
+	assert.NotContains(out, "myAPIKey")
+	assert.NotContains(out, "0a1b2c")
+	assert.NotContains(out, testListenKey)
+	var record map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+	assert.Equal("REDACTED", record["apiKey"])
+	assert.Equal("wss://fstream.binance.com/ws/REDACTED", record["stream"])
+	assert.Equal(map[string]interface{}{"signature": "REDACTED", "symbol": "BTCUSDT"}, record["request"])
+	assert.Equal("GET /api/v3/account?signature=REDACTED: EOF", record["error"])
+
+	// A nil handler discards records
+	NewLogger(nil).Error("dropped")
+}
+
+func TestLogRequest(t *testing.T) {
+	assert := assert.New(t)
+	buf := new(bytes.Buffer)
+	logger := NewLogger(slog.NewTextHandler(buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	ctx := context.Background()
+	LogRequest(ctx, logger, "GET", "/api/v3/account", 20, 200, 15*time.Millisecond, nil)
+	LogRequest(ctx, logger, "POST", "/api/v3/order", 1, 400, time.Millisecond,
+		&APIError{Code: -2010, Message: "Account has insufficient balance"})
+	LogRequest(ctx, logger, "GET", "/api/v3/depth", 5, 0, time.Second, errors.New("connection reset"))
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 3)
+	assert.Contains(lines[0], `level=DEBUG msg="binance request" method=GET endpoint=/api/v3/account weight=20 latency=15ms status=200`)
+	assert.Contains(lines[1], `level=WARN msg="binance request" method=POST endpoint=/api/v3/order weight=1 latency=1ms status=400 code=-2010`)
+	assert.Contains(lines[2], `level=ERROR msg="binance request" method=GET endpoint=/api/v3/depth weight=5 latency=1s error="connection reset"`)
+}
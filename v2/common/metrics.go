@@ -0,0 +1,126 @@
+package common
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Metrics receive the measurements of the REST requests and the websocket
+// streams of the clients. Implementations must be safe for concurrent use,
+// see the metrics/prometheus and metrics/otel modules for adapters.
+type Metrics interface {
+	// ObserveRequest record a REST request, status is 0 when no response
+	// was received and code is the Binance error code of a rejected request
+	ObserveRequest(method, endpoint string, status int, code int64, latency time.Duration)
+	// SetUsedWeight record the request weight used in interval, as reported
+	// by the X-MBX-USED-WEIGHT-* response headers
+	SetUsedWeight(interval string, weight int64)
+	// ObserveMessage record a message received on stream
+	ObserveMessage(stream string)
+	// ObserveLag record the delay between the event time of a message and
+	// its reception
+	ObserveLag(stream string, lag time.Duration)
+	// ObserveReconnect record a reconnection of stream
+	ObserveReconnect(stream string)
+}
+
+// RecordRequest report a REST request and the used weight found in header
+// to m, it does nothing when m is nil
+func RecordRequest(m Metrics, method, endpoint string, status int, header http.Header, latency time.Duration, err error) {
+	if m == nil {
+		return
+	}
+	var code int64
+	if apiErr, ok := AsAPIError(err); ok {
+		code = int64(apiErr.Code)
+	}
+	m.ObserveRequest(method, endpoint, status, code, latency)
+	for key, values := range header {
+		name := strings.ToUpper(key)
+		if len(values) == 0 || !strings.HasPrefix(name, "X-MBX-USED-WEIGHT-") {
+			continue
+		}
+		weight, err := strconv.ParseInt(values[0], 10, 64)
+		if err != nil {
+			continue
+		}
+		m.SetUsedWeight(strings.ToLower(name[len("X-MBX-USED-WEIGHT-"):]), weight)
+	}
+}
+
+// RecordMessage report a message received on stream to m, along with its
+// lag when it carries an event time. The stream of a combined stream payload
+// takes precedence over stream. It does nothing when m is nil.
+func RecordMessage(m Metrics, stream string, message []byte) {
+	if m == nil {
+		return
+	}
+	header, ok := parseEventHeader(message)
+	if ok && header.Stream != "" {
+		stream = redactStream(header.Stream)
+	}
+	m.ObserveMessage(stream)
+	if ok && header.Time > 0 {
+		m.ObserveLag(stream, time.Since(time.UnixMilli(header.Time)))
+	}
+}
+
+// RecordReconnect report a reconnection of stream to m, it does nothing
+// when m is nil
+func RecordReconnect(m Metrics, stream string) {
+	if m != nil {
+		m.ObserveReconnect(stream)
+	}
+}
+
+// eventHeader is the part of a websocket message identifying its event,
+// the type is decoded too so that "e" does not match E case-insensitively
+type eventHeader struct {
+	Stream string       `json:"stream"`
+	Event  string       `json:"e"`
+	Time   int64        `json:"E"`
+	Data   *eventHeader `json:"data"`
+}
+
+// parseEventHeader decode the header of a websocket message, which may be
+// wrapped in a combined stream payload or be an array of events
+func parseEventHeader(message []byte) (header eventHeader, ok bool) {
+	message = bytes.TrimSpace(message)
+	if len(message) > 0 && message[0] == '[' {
+		var events []eventHeader
+		if json.Unmarshal(message, &events) != nil || len(events) == 0 {
+			return header, false
+		}
+		return events[0], true
+	}
+	if json.Unmarshal(message, &header) != nil {
+		return header, false
+	}
+	if header.Data != nil {
+		header.Time = header.Data.Time
+	}
+	return header, true
+}
+
+// StreamName return the stream label of a websocket endpoint: the streams
+// it subscribes to, with listen keys redacted
+func StreamName(endpoint string) string {
+	name := endpoint
+	if i := strings.Index(name, "streams="); i >= 0 {
+		name = name[i+len("streams="):]
+	} else if i := strings.LastIndex(name, "/ws/"); i >= 0 {
+		name = name[i+len("/ws/"):]
+	} else if i := strings.LastIndex(name, "/"); i >= 0 {
+		name = name[i+1:]
+	}
+	return redactStream(name)
+}
+
+// redactStream hide the listen keys of a stream name
+func redactStream(name string) string {
+	return RedactString("/" + name)[1:]
+}
@@ -0,0 +1,115 @@
+package common
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// recordMetrics keep the measurements it receives as strings
+type recordMetrics struct {
+	mu      sync.Mutex
+	records []string
+	lags    map[string]time.Duration
+}
+
+func (m *recordMetrics) record(format string, args ...interface{}) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.records = append(m.records, fmt.Sprintf(format, args...))
+}
+
+func (m *recordMetrics) ObserveRequest(method, endpoint string, status int, code int64, latency time.Duration) {
+	m.record("request %s %s %d %d", method, endpoint, status, code)
+}
+
+func (m *recordMetrics) SetUsedWeight(interval string, weight int64) {
+	m.record("weight %s %d", interval, weight)
+}
+
+func (m *recordMetrics) ObserveMessage(stream string) {
+	m.record("message %s", stream)
+}
+
+func (m *recordMetrics) ObserveLag(stream string, lag time.Duration) {
+	m.record("lag %s", stream)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.lags == nil {
+		m.lags = make(map[string]time.Duration)
+	}
+	m.lags[stream] = lag
+}
+
+func (m *recordMetrics) ObserveReconnect(stream string) {
+	m.record("reconnect %s", stream)
+}
+
+func TestRecordRequest(t *testing.T) {
+	assert := assert.New(t)
+	m := new(recordMetrics)
+	header := http.Header{}
+	header.Set("X-Mbx-Used-Weight", "21")
+	header.Set("X-Mbx-Used-Weight-1m", "21")
+	header.Set("X-Mbx-Order-Count-10s", "1")
+	RecordRequest(m, "GET", "/api/v3/account", 200, header, time.Millisecond, nil)
+	RecordRequest(m, "POST", "/api/v3/order", 400, nil, time.Millisecond,
+		&APIError{Code: -2010, Message: "Account has insufficient balance"})
+	RecordRequest(m, "GET", "/api/v3/depth", 0, nil, time.Second, errors.New("connection reset"))
+	assert.Equal([]string{
+		"request GET /api/v3/account 200 0",
+		"weight 1m 21",
+		"request POST /api/v3/order 400 -2010",
+		"request GET /api/v3/depth 0 0",
+	}, m.records)
+
+	// A nil Metrics is ignored
+	RecordRequest(nil, "GET", "/api/v3/account", 200, header, time.Millisecond, nil)
+}
+
+func TestRecordMessage(t *testing.T) {
+	assert := assert.New(t)
+	m := new(recordMetrics)
+	eventTime := time.Now().Add(-time.Second).UnixMilli()
+	RecordMessage(m, "btcusdt@kline_1m", []byte(fmt.Sprintf(`{"e":"kline","E":%d,"s":"BTCUSDT"}`, eventTime)))
+	RecordMessage(m, "btcusdt@trade/ethusdt@trade",
+		[]byte(fmt.Sprintf(`{"stream":"ethusdt@trade","data":{"e":"trade","E":%d}}`, eventTime)))
+	RecordMessage(m, "!ticker@arr", []byte(fmt.Sprintf(`[{"e":"24hrTicker","E":%d}]`, eventTime)))
+	RecordMessage(m, "btcusdt@depth5", []byte(`{"lastUpdateId":160,"bids":[],"asks":[]}`))
+	RecordMessage(m, "invalid", []byte(`not json`))
+	assert.Equal([]string{
+		"message btcusdt@kline_1m",
+		"lag btcusdt@kline_1m",
+		"message ethusdt@trade",
+		"lag ethusdt@trade",
+		"message !ticker@arr",
+		"lag !ticker@arr",
+		"message btcusdt@depth5",
+		"message invalid",
+	}, m.records)
+	assert.True(m.lags["btcusdt@kline_1m"] >= time.Second)
+
+	// A nil Metrics is ignored
+	RecordMessage(nil, "btcusdt@kline_1m", []byte(`{"E":1}`))
+}
+
+func TestRecordReconnect(t *testing.T) {
+	m := new(recordMetrics)
+	RecordReconnect(m, "btcusdt@depth")
+	RecordReconnect(nil, "btcusdt@depth")
+	assert.Equal(t, []string{"reconnect btcusdt@depth"}, m.records)
+}
+
+func TestStreamName(t *testing.T) {
+	assert := assert.New(t)
+	assert.Equal("btcusdt@kline_1m", StreamName("wss://stream.binance.com:9443/ws/btcusdt@kline_1m"))
+	assert.Equal("btcusdt@trade/ethusdt@trade",
+		StreamName("wss://stream.binance.com:9443/stream?streams=btcusdt@trade/ethusdt@trade"))
+	assert.Equal("REDACTED", StreamName("wss://fstream.binance.com/ws/"+testListenKey))
+	assert.Equal("btcusdt@depth", StreamName("ws://127.0.0.1:8080/btcusdt@depth"))
+}
@@ -0,0 +1,131 @@
+package common
+
+import (
+	"context"
+	"io"
+	"log"
+	"net/http"
+	"time"
+)
+
+// Doer send an HTTP request and return its response, like http.Client.Do
+type Doer func(req *http.Request) (*http.Response, error)
+
+// Middleware wrap the Doer of a client to act on every request it sends,
+// such as retrying, recording, tracing or injecting headers. Requests are
+// signed before going through the middlewares.
+type Middleware func(next Doer) Doer
+
+// Chain wrap do with middlewares, the first middleware is the outermost one
+func Chain(do Doer, middlewares ...Middleware) Doer {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		do = middlewares[i](do)
+	}
+	return do
+}
+
+// RetryConfig define how RetryMiddleware retries requests
+type RetryConfig struct {
+	// MaxRetries is the number of retries after the first attempt
+	MaxRetries int
+	// MinBackoff is the delay before the first retry
+	MinBackoff time.Duration
+	// MaxBackoff caps the exponentially growing delay between retries
+	MaxBackoff time.Duration
+	// RetryPost also retry POST requests. They are not retried by default
+	// since a request failing with a 5xx or a network error may still have
+	// been executed, an order would then be placed twice.
+	RetryPost bool
+}
+
+// NewRetryConfig create a retry configuration with sensible defaults
+func NewRetryConfig() *RetryConfig {
+	return &RetryConfig{
+		MaxRetries: 3,
+		MinBackoff: 100 * time.Millisecond,
+		MaxBackoff: 5 * time.Second,
+	}
+}
+
+// RetryMiddleware retry requests failing with a 5xx status or a network
+// error, backing off between attempts. Signed requests keep their timestamp,
+// so the backoff must stay well below the recvWindow.
+func RetryMiddleware(cfg *RetryConfig) Middleware {
+	return func(next Doer) Doer {
+		return func(req *http.Request) (*http.Response, error) {
+			if req.Method == http.MethodPost && !cfg.RetryPost {
+				return next(req)
+			}
+			for attempts := 0; ; attempts++ {
+				res, err := next(req)
+				retry := err != nil || res.StatusCode >= http.StatusInternalServerError
+				if !retry || attempts >= cfg.MaxRetries || req.Context().Err() != nil {
+					return res, err
+				}
+				if req.Body != nil && req.GetBody == nil {
+					// The body was consumed and can not be sent again
+					return res, err
+				}
+				if res != nil {
+					io.Copy(io.Discard, res.Body)
+					res.Body.Close()
+				}
+				if err := sleep(req.Context(), Backoff(attempts+1, cfg.MinBackoff, cfg.MaxBackoff)); err != nil {
+					return nil, err
+				}
+				req, err = rewind(req)
+				if err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// rewind return a copy of req with a fresh body
+func rewind(req *http.Request) (*http.Request, error) {
+	clone := req.Clone(req.Context())
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		clone.Body = body
+	}
+	return clone, nil
+}
+
+// LoggingMiddleware log every request with its status and latency, the
+// signature, listen key and API key are redacted
+func LoggingMiddleware(logger *log.Logger) Middleware {
+	return func(next Doer) Doer {
+		return func(req *http.Request) (*http.Response, error) {
+			start := time.Now()
+			res, err := next(req)
+			latency := time.Since(start)
+			apiKey := ""
+			if req.Header.Get("X-MBX-APIKEY") != "" {
+				apiKey = Redacted
+			}
+			if err != nil {
+				logger.Printf("method=%s url=%q apiKey=%s latency=%s err=%q",
+					req.Method, RedactString(req.URL.String()), apiKey, latency, err)
+				return res, err
+			}
+			logger.Printf("method=%s url=%q apiKey=%s latency=%s status=%d",
+				req.Method, RedactString(req.URL.String()), apiKey, latency, res.StatusCode)
+			return res, err
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package common
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeDoer answer with statuses in order and record the bodies it received
+type fakeDoer struct {
+	statuses []int
+	bodies   []string
+}
+
+func (f *fakeDoer) do(req *http.Request) (*http.Response, error) {
+	body := ""
+	if req.Body != nil {
+		data, _ := io.ReadAll(req.Body)
+		body = string(data)
+	}
+	f.bodies = append(f.bodies, body)
+	status := f.statuses[0]
+	f.statuses = f.statuses[1:]
+	if status == 0 {
+		return nil, errors.New("connection reset")
+	}
+	return &http.Response{StatusCode: status, Body: io.NopCloser(strings.NewReader("{}"))}, nil
+}
+
+func TestChain(t *testing.T) {
+	var calls []string
+	middleware := func(name string) Middleware {
+		return func(next Doer) Doer {
+			return func(req *http.Request) (*http.Response, error) {
+				calls = append(calls, name)
+				return next(req)
+			}
+		}
+	}
+	f := &fakeDoer{statuses: []int{200}}
+	req, _ := http.NewRequest(http.MethodGet, "https://api.binance.com/api/v3/ping", nil)
+	res, err := Chain(f.do, middleware("outer"), middleware("inner"))(req)
+	require.NoError(t, err)
+	assert.Equal(t, 200, res.StatusCode)
+	assert.Equal(t, []string{"outer", "inner"}, calls)
+}
+
+func TestRetryMiddleware(t *testing.T) {
+	assert := assert.New(t)
+	cfg := &RetryConfig{MaxRetries: 2, MinBackoff: time.Millisecond, MaxBackoff: time.Millisecond}
+	newRequest := func(method string) *http.Request {
+		req, _ := http.NewRequest(method, "https://api.binance.com/api/v3/order", bytes.NewBufferString("symbol=BTCUSDT"))
+		return req
+	}
+
+	f := &fakeDoer{statuses: []int{502, 0, 200}}
+	res, err := RetryMiddleware(cfg)(f.do)(newRequest(http.MethodDelete))
+	assert.NoError(err)
+	assert.Equal(200, res.StatusCode)
+	assert.Equal([]string{"symbol=BTCUSDT", "symbol=BTCUSDT", "symbol=BTCUSDT"}, f.bodies)
+
+	f = &fakeDoer{statuses: []int{503, 503, 503}}
+	res, err = RetryMiddleware(cfg)(f.do)(newRequest(http.MethodGet))
+	assert.NoError(err)
+	assert.Equal(503, res.StatusCode)
+	assert.Len(f.bodies, 3)
+
+	// Client errors and POST requests are not retried
+	f = &fakeDoer{statuses: []int{400}}
+	res, err = RetryMiddleware(cfg)(f.do)(newRequest(http.MethodGet))
+	assert.NoError(err)
+	assert.Equal(400, res.StatusCode)
+	f = &fakeDoer{statuses: []int{0}}
+	_, err = RetryMiddleware(cfg)(f.do)(newRequest(http.MethodPost))
+	assert.EqualError(err, "connection reset")
+
+	cfg.RetryPost = true
+	f = &fakeDoer{statuses: []int{0, 200}}
+	res, err = RetryMiddleware(cfg)(f.do)(newRequest(http.MethodPost))
+	assert.NoError(err)
+	assert.Equal(200, res.StatusCode)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	f = &fakeDoer{statuses: []int{500}}
+	res, err = RetryMiddleware(cfg)(f.do)(newRequest(http.MethodGet).WithContext(ctx))
+	assert.NoError(err)
+	assert.Equal(500, res.StatusCode)
+}
+
+func TestLoggingMiddleware(t *testing.T) {
+	assert := assert.New(t)
+	buf := new(bytes.Buffer)
+	logger := log.New(buf, "", 0)
+	req, _ := http.NewRequest(http.MethodPut,
+		"https://api.binance.com/api/v3/userDataStream?listenKey=secretKey&timestamp=1&signature=abcdef", nil)
+	req.Header.Set("X-MBX-APIKEY", "myAPIKey")
+	f := &fakeDoer{statuses: []int{200, 0}}
+	_, err := LoggingMiddleware(logger)(f.do)(req)
+	assert.NoError(err)
+	_, err = LoggingMiddleware(logger)(f.do)(req)
+	assert.Error(err)
+
+	out := buf.String()
+	assert.NotContains(out, "secretKey")
+	assert.NotContains(out, "abcdef")
+	assert.NotContains(out, "myAPIKey")
+	assert.Contains(out, `method=PUT url="https://api.binance.com/api/v3/userDataStream?listenKey=REDACTED&timestamp=1&signature=REDACTED" apiKey=REDACTED`)
+	assert.Contains(out, "status=200")
+	assert.Contains(out, `err="connection reset"`)
+}
@@ -0,0 +1,356 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ErrOrderBookGap is reported when a depth update does not follow the
+// previous one, the book has to be rebuilt from a new snapshot
+var ErrOrderBookGap = errors.New("order book update sequence gap")
+
+// ErrInsufficientDepth is returned when the book does not hold enough
+// quantity to fill the requested size
+var ErrInsufficientDepth = errors.New("insufficient order book depth")
+
+type bookLevel struct {
+	price    float64
+	quantity float64
+	level    PriceLevel
+}
+
+// Book is a thread-safe in-memory order book with bids sorted from the
+// highest price and asks sorted from the lowest price
+type Book struct {
+	mu           sync.RWMutex
+	lastUpdateID int64
+	bids         []bookLevel
+	asks         []bookLevel
+}
+
+// NewBook create an empty book
+func NewBook() *Book {
+	return &Book{}
+}
+
+// Reset replace the content of the book with a snapshot
+func (b *Book) Reset(lastUpdateID int64, bids, asks []PriceLevel) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.lastUpdateID = lastUpdateID
+	b.bids = b.bids[:0]
+	b.asks = b.asks[:0]
+	return b.update(bids, asks)
+}
+
+// Update apply levels from a diff depth event, a level with a zero quantity is removed
+func (b *Book) Update(lastUpdateID int64, bids, asks []PriceLevel) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	err := b.update(bids, asks)
+	if err != nil {
+		return err
+	}
+	b.lastUpdateID = lastUpdateID
+	return nil
+}
+
+func (b *Book) update(bids, asks []PriceLevel) (err error) {
+	for _, level := range bids {
+		b.bids, err = setLevel(b.bids, level, true)
+		if err != nil {
+			return err
+		}
+	}
+	for _, level := range asks {
+		b.asks, err = setLevel(b.asks, level, false)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func setLevel(levels []bookLevel, level PriceLevel, desc bool) ([]bookLevel, error) {
+	price, quantity, err := level.Parse()
+	if err != nil {
+		return levels, err
+	}
+	i := sort.Search(len(levels), func(i int) bool {
+		if desc {
+			return levels[i].price <= price
+		}
+		return levels[i].price >= price
+	})
+	found := i < len(levels) && levels[i].price == price
+	switch {
+	case quantity == 0 && found:
+		return append(levels[:i], levels[i+1:]...), nil
+	case quantity == 0:
+		return levels, nil
+	case found:
+		levels[i] = bookLevel{price: price, quantity: quantity, level: level}
+		return levels, nil
+	}
+	levels = append(levels, bookLevel{})
+	copy(levels[i+1:], levels[i:])
+	levels[i] = bookLevel{price: price, quantity: quantity, level: level}
+	return levels, nil
+}
+
+// LastUpdateID return the update id of the last snapshot or event applied
+func (b *Book) LastUpdateID() int64 {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.lastUpdateID
+}
+
+// BestBid return the highest bid, ok is false when there is no bid
+func (b *Book) BestBid() (level PriceLevel, ok bool) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	if len(b.bids) == 0 {
+		return level, false
+	}
+	return b.bids[0].level, true
+}
+
+// BestAsk return the lowest ask, ok is false when there is no ask
+func (b *Book) BestAsk() (level PriceLevel, ok bool) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	if len(b.asks) == 0 {
+		return level, false
+	}
+	return b.asks[0].level, true
+}
+
+// Depth return up to n levels on each side starting from the best prices, n <= 0 returns all levels
+func (b *Book) Depth(n int) (bids, asks []PriceLevel) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return topLevels(b.bids, n), topLevels(b.asks, n)
+}
+
+func topLevels(levels []bookLevel, n int) []PriceLevel {
+	if n <= 0 || n > len(levels) {
+		n = len(levels)
+	}
+	res := make([]PriceLevel, n)
+	for i := 0; i < n; i++ {
+		res[i] = levels[i].level
+	}
+	return res
+}
+
+// BuyVWAP return the volume weighted average price paid to buy size by sweeping the asks
+func (b *Book) BuyVWAP(size float64) (float64, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return vwap(b.asks, size)
+}
+
+// SellVWAP return the volume weighted average price received to sell size by sweeping the bids
+func (b *Book) SellVWAP(size float64) (float64, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return vwap(b.bids, size)
+}
+
+func vwap(levels []bookLevel, size float64) (float64, error) {
+	if size <= 0 {
+		return 0, errors.New("size must be positive")
+	}
+	remaining := size
+	notional := 0.0
+	for _, level := range levels {
+		quantity := level.quantity
+		if quantity > remaining {
+			quantity = remaining
+		}
+		notional += quantity * level.price
+		remaining -= quantity
+		if remaining <= 0 {
+			return notional / size, nil
+		}
+	}
+	return 0, ErrInsufficientDepth
+}
+
+// DepthUpdate is a diff depth event applied by an OrderBookSync
+type DepthUpdate struct {
+	FirstUpdateID int64
+	LastUpdateID  int64
+	// PrevLastUpdateID is the last update id of the previous event, only
+	// set by the chained streams
+	PrevLastUpdateID int64
+	Bids             []PriceLevel
+	Asks             []PriceLevel
+}
+
+// DepthSnapshot is a depth snapshot the diff depth events are applied on
+type DepthSnapshot struct {
+	LastUpdateID int64
+	Bids         []PriceLevel
+	Asks         []PriceLevel
+}
+
+// DepthSnapshotFunc fetch a depth snapshot of the synced symbol
+type DepthSnapshotFunc func(ctx context.Context) (*DepthSnapshot, error)
+
+// OrderBookSync keeps a Book in sync with a diff depth stream. Events are
+// buffered while a snapshot is fetched, stale events are dropped and a new
+// snapshot is fetched whenever a gap is detected in the update ids.
+type OrderBookSync struct {
+	*Book
+
+	snapshot   DepthSnapshotFunc
+	chained    bool
+	onUpdate   func()
+	errHandler func(err error)
+	mu         sync.Mutex
+	synced     bool
+	syncing    bool
+	bridged    bool
+	buffer     []*DepthUpdate
+}
+
+// NewOrderBookSync init the sync of a book from the snapshots of snapshot.
+// On chained streams, as the futures ones, every event carries the last
+// update id of the previous event, otherwise every event starts right after
+// the previous one.
+func NewOrderBookSync(snapshot DepthSnapshotFunc, chained bool) *OrderBookSync {
+	return &OrderBookSync{
+		Book:     NewBook(),
+		snapshot: snapshot,
+		chained:  chained,
+	}
+}
+
+// Synced tell whether the book is currently in sync with the stream
+func (b *OrderBookSync) Synced() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.synced
+}
+
+// Handler return the function to call with every event of the stream.
+// Snapshots are fetched with ctx, onUpdate is called after every snapshot or
+// event applied to the book and errHandler with every gap or failed snapshot.
+func (b *OrderBookSync) Handler(ctx context.Context, onUpdate func(), errHandler func(err error)) func(event *DepthUpdate) {
+	b.onUpdate = onUpdate
+	b.errHandler = errHandler
+	return func(event *DepthUpdate) {
+		b.handleEvent(ctx, event)
+	}
+}
+
+func (b *OrderBookSync) handleEvent(ctx context.Context, event *DepthUpdate) {
+	b.mu.Lock()
+	if !b.synced {
+		b.buffer = append(b.buffer, event)
+		if !b.syncing {
+			b.syncing = true
+			go b.resync(ctx)
+		}
+		b.mu.Unlock()
+		return
+	}
+	applied, err := b.applyEvent(event)
+	if err != nil {
+		b.synced = false
+		b.buffer = []*DepthUpdate{event}
+		b.syncing = true
+		go b.resync(ctx)
+	}
+	b.mu.Unlock()
+	if err != nil {
+		b.errHandler(err)
+		return
+	}
+	if applied {
+		b.onUpdate()
+	}
+}
+
+// applyEvent apply an event on top of the book, b.mu must be held. Events
+// older than the book are skipped and the first event applied must cover the
+// snapshot. Every following event must start right after the previous one,
+// or on chained streams continue from it.
+func (b *OrderBookSync) applyEvent(event *DepthUpdate) (applied bool, err error) {
+	last := b.LastUpdateID()
+	if !b.chained {
+		if event.LastUpdateID <= last {
+			return false, nil
+		}
+		if event.FirstUpdateID > last+1 {
+			return false, ErrOrderBookGap
+		}
+		return true, b.Update(event.LastUpdateID, event.Bids, event.Asks)
+	}
+	if event.LastUpdateID < last {
+		return false, nil
+	}
+	if b.bridged && event.PrevLastUpdateID != last {
+		return false, ErrOrderBookGap
+	}
+	if !b.bridged && event.FirstUpdateID > last {
+		return false, ErrOrderBookGap
+	}
+	b.bridged = true
+	return true, b.Update(event.LastUpdateID, event.Bids, event.Asks)
+}
+
+// resync fetch snapshots until one lines up with the buffered events
+func (b *OrderBookSync) resync(ctx context.Context) {
+	attempts := 0
+	for {
+		if attempts > 0 {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(Backoff(attempts, 100*time.Millisecond, 10*time.Second)):
+			}
+		}
+		attempts++
+		snapshot, err := b.snapshot(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			b.errHandler(err)
+			continue
+		}
+		b.mu.Lock()
+		// A snapshot older than the first buffered event can not be
+		// bridged, wait for the exchange to catch up and fetch another.
+		if snapshot.LastUpdateID < b.buffer[0].FirstUpdateID {
+			b.mu.Unlock()
+			continue
+		}
+		b.bridged = false
+		err = b.Reset(snapshot.LastUpdateID, snapshot.Bids, snapshot.Asks)
+		for i := 0; err == nil && i < len(b.buffer); i++ {
+			_, err = b.applyEvent(b.buffer[i])
+			if err != nil {
+				// Events were lost inside the buffer, start over
+				// from the first event after the gap.
+				b.buffer = b.buffer[i:]
+			}
+		}
+		if err != nil {
+			b.mu.Unlock()
+			b.errHandler(err)
+			continue
+		}
+		b.buffer = nil
+		b.synced = true
+		b.syncing = false
+		b.mu.Unlock()
+		b.onUpdate()
+		return
+	}
+}
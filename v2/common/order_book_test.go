@@ -0,0 +1,225 @@
+package common
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestBook(t *testing.T) {
+	r := require.New(t)
+	b := NewBook()
+	_, ok := b.BestBid()
+	r.False(ok)
+
+	err := b.Reset(10, []PriceLevel{
+		{Price: "99", Quantity: "1"},
+		{Price: "100", Quantity: "2"},
+		{Price: "98", Quantity: "3"},
+	}, []PriceLevel{
+		{Price: "102", Quantity: "1"},
+		{Price: "101", Quantity: "2"},
+	})
+	r.NoError(err)
+	r.Equal(int64(10), b.LastUpdateID())
+
+	bid, ok := b.BestBid()
+	r.True(ok)
+	r.Equal(PriceLevel{Price: "100", Quantity: "2"}, bid)
+	ask, ok := b.BestAsk()
+	r.True(ok)
+	r.Equal(PriceLevel{Price: "101", Quantity: "2"}, ask)
+
+	err = b.Update(11, []PriceLevel{
+		{Price: "100", Quantity: "0"},
+		{Price: "99.5", Quantity: "4"},
+		{Price: "97", Quantity: "0"},
+	}, []PriceLevel{
+		{Price: "101", Quantity: "0.5"},
+	})
+	r.NoError(err)
+	r.Equal(int64(11), b.LastUpdateID())
+
+	bids, asks := b.Depth(2)
+	r.Equal([]PriceLevel{{Price: "99.5", Quantity: "4"}, {Price: "99", Quantity: "1"}}, bids)
+	r.Equal([]PriceLevel{{Price: "101", Quantity: "0.5"}, {Price: "102", Quantity: "1"}}, asks)
+	bids, _ = b.Depth(0)
+	r.Len(bids, 3)
+
+	err = b.Update(12, []PriceLevel{{Price: "abc", Quantity: "1"}}, nil)
+	r.Error(err)
+	r.Equal(int64(11), b.LastUpdateID())
+}
+
+func TestBookVWAP(t *testing.T) {
+	assert := assert.New(t)
+	b := NewBook()
+	b.Reset(1, []PriceLevel{
+		{Price: "100", Quantity: "1"},
+		{Price: "99", Quantity: "1"},
+	}, []PriceLevel{
+		{Price: "101", Quantity: "1"},
+		{Price: "103", Quantity: "3"},
+	})
+
+	p, err := b.BuyVWAP(0.5)
+	assert.NoError(err)
+	assert.InDelta(101, p, 1e-9)
+	p, err = b.BuyVWAP(2)
+	assert.NoError(err)
+	assert.InDelta(102, p, 1e-9)
+	p, err = b.SellVWAP(2)
+	assert.NoError(err)
+	assert.InDelta(99.5, p, 1e-9)
+
+	_, err = b.SellVWAP(3)
+	assert.Equal(ErrInsufficientDepth, err)
+	_, err = b.BuyVWAP(0)
+	assert.Error(err)
+}
+
+type orderBookSyncTestSuite struct {
+	suite.Suite
+	snapshots chan *DepthSnapshot
+	updates   chan struct{}
+	errs      chan error
+	cancel    context.CancelFunc
+}
+
+func TestOrderBookSync(t *testing.T) {
+	suite.Run(t, new(orderBookSyncTestSuite))
+}
+
+func (s *orderBookSyncTestSuite) SetupTest() {
+	s.snapshots = make(chan *DepthSnapshot, 10)
+	s.updates = make(chan struct{}, 10)
+	s.errs = make(chan error, 10)
+}
+
+func (s *orderBookSyncTestSuite) TearDownTest() {
+	if s.cancel != nil {
+		s.cancel()
+	}
+}
+
+// serve start a sync fetching the snapshots sent on s.snapshots
+func (s *orderBookSyncTestSuite) serve(chained bool) (*OrderBookSync, func(event *DepthUpdate)) {
+	snapshots := s.snapshots
+	book := NewOrderBookSync(func(ctx context.Context) (*DepthSnapshot, error) {
+		select {
+		case snapshot := <-snapshots:
+			return snapshot, nil
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}, chained)
+	var ctx context.Context
+	ctx, s.cancel = context.WithCancel(context.Background())
+	updates, errs := s.updates, s.errs
+	handler := book.Handler(ctx, func() {
+		updates <- struct{}{}
+	}, func(err error) {
+		errs <- err
+	})
+	return book, handler
+}
+
+func (s *orderBookSyncTestSuite) waitUpdate() {
+	select {
+	case <-s.updates:
+	case <-time.After(time.Second):
+		s.FailNow("timed out waiting for book update")
+	}
+}
+
+func (s *orderBookSyncTestSuite) waitGap() {
+	select {
+	case err := <-s.errs:
+		s.Require().Equal(ErrOrderBookGap, err)
+	case <-time.After(time.Second):
+		s.FailNow("timed out waiting for gap")
+	}
+}
+
+func (s *orderBookSyncTestSuite) TestSync() {
+	book, handler := s.serve(false)
+	s.snapshots <- &DepthSnapshot{
+		LastUpdateID: 160,
+		Bids:         []PriceLevel{{Price: "0.0024", Quantity: "10"}, {Price: "0.0023", Quantity: "5"}},
+		Asks:         []PriceLevel{{Price: "0.0026", Quantity: "100"}},
+	}
+
+	// Stale event dropped, second one bridges the snapshot.
+	handler(&DepthUpdate{FirstUpdateID: 150, LastUpdateID: 155, Bids: []PriceLevel{{Price: "0.0024", Quantity: "1"}}})
+	handler(&DepthUpdate{FirstUpdateID: 157, LastUpdateID: 161, Bids: []PriceLevel{{Price: "0.0025", Quantity: "2"}}})
+	s.waitUpdate()
+	s.True(book.Synced())
+	s.Equal(int64(161), book.LastUpdateID())
+	bid, _ := book.BestBid()
+	s.Equal(PriceLevel{Price: "0.0025", Quantity: "2"}, bid)
+
+	handler(&DepthUpdate{
+		FirstUpdateID: 162,
+		LastUpdateID:  163,
+		Bids:          []PriceLevel{{Price: "0.0025", Quantity: "0"}},
+		Asks:          []PriceLevel{{Price: "0.0026", Quantity: "0"}, {Price: "0.0027", Quantity: "3"}},
+	})
+	s.waitUpdate()
+	bids, asks := book.Depth(5)
+	s.Equal([]PriceLevel{{Price: "0.0024", Quantity: "10"}, {Price: "0.0023", Quantity: "5"}}, bids)
+	s.Equal([]PriceLevel{{Price: "0.0027", Quantity: "3"}}, asks)
+}
+
+func (s *orderBookSyncTestSuite) TestResyncOnGap() {
+	book, handler := s.serve(false)
+	s.snapshots <- &DepthSnapshot{LastUpdateID: 160}
+	handler(&DepthUpdate{FirstUpdateID: 157, LastUpdateID: 161})
+	s.waitUpdate()
+	s.True(book.Synced())
+
+	handler(&DepthUpdate{FirstUpdateID: 170, LastUpdateID: 171})
+	s.waitGap()
+	s.False(book.Synced())
+
+	// The next snapshot covering the buffered event syncs the book again
+	s.snapshots <- &DepthSnapshot{LastUpdateID: 170}
+	s.waitUpdate()
+	s.True(book.Synced())
+	s.Equal(int64(171), book.LastUpdateID())
+}
+
+func (s *orderBookSyncTestSuite) TestChainedSync() {
+	book, handler := s.serve(true)
+	s.snapshots <- &DepthSnapshot{LastUpdateID: 160, Bids: []PriceLevel{{Price: "9638.0", Quantity: "10"}}}
+
+	// The first event covers the snapshot, the next ones follow pu
+	handler(&DepthUpdate{FirstUpdateID: 150, LastUpdateID: 155, PrevLastUpdateID: 149})
+	handler(&DepthUpdate{FirstUpdateID: 157, LastUpdateID: 161, PrevLastUpdateID: 155, Bids: []PriceLevel{{Price: "9638.1", Quantity: "2"}}})
+	s.waitUpdate()
+	s.True(book.Synced())
+	handler(&DepthUpdate{FirstUpdateID: 165, LastUpdateID: 167, PrevLastUpdateID: 161})
+	s.waitUpdate()
+	s.Equal(int64(167), book.LastUpdateID())
+	bid, _ := book.BestBid()
+	s.Equal(PriceLevel{Price: "9638.1", Quantity: "2"}, bid)
+
+	handler(&DepthUpdate{FirstUpdateID: 170, LastUpdateID: 172, PrevLastUpdateID: 169})
+	s.waitGap()
+	s.False(book.Synced())
+}
+
+func (s *orderBookSyncTestSuite) TestStaleSnapshot() {
+	book, handler := s.serve(true)
+	// The first snapshot predates the buffered event, another one is fetched
+	s.snapshots <- &DepthSnapshot{LastUpdateID: 150}
+	s.snapshots <- &DepthSnapshot{LastUpdateID: 160}
+	handler(&DepthUpdate{FirstUpdateID: 157, LastUpdateID: 161, PrevLastUpdateID: 155})
+	s.waitUpdate()
+	s.True(book.Synced())
+	s.Equal(int64(161), book.LastUpdateID())
+	s.Empty(s.snapshots)
+}
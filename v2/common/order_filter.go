@@ -0,0 +1,147 @@
+package common
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// ErrSymbolNotFound is returned when validating an order for a symbol missing
+// from the exchange info
+var ErrSymbolNotFound = errors.New("symbol not found in exchange info")
+
+// FilterError is returned when an order does not pass a symbol filter before
+// being sent
+type FilterError struct {
+	Symbol string
+	Filter string
+	Param  string
+	Value  string
+	Reason string
+}
+
+// Error return the filter, the parameter and the reason
+func (e *FilterError) Error() string {
+	return fmt.Sprintf("<FilterError> symbol=%s, filter=%s, %s=%s: %s", e.Symbol, e.Filter, e.Param, e.Value, e.Reason)
+}
+
+// Is match ErrFilterFailure, so rejected orders are classified like the
+// -1013 errors they prevent
+func (e *FilterError) Is(target error) bool {
+	return target == ErrFilterFailure
+}
+
+// StepFilter is the min, max and step of PRICE_FILTER, LOT_SIZE or
+// MARKET_LOT_SIZE, a zero bound or step is not checked
+type StepFilter struct {
+	Filter string
+	Min    string
+	Max    string
+	Step   string
+}
+
+// Apply check value against the filter and return it. When round is true a
+// value off the step is rounded with mode instead of rejected, and the
+// rounded value is returned. Steps are counted from Min.
+func (f StepFilter) Apply(symbol, param, value string, round bool, mode RoundingMode) (string, error) {
+	reject := func(reason string, args ...interface{}) (string, error) {
+		return "", &FilterError{Symbol: symbol, Filter: f.Filter, Param: param, Value: value, Reason: fmt.Sprintf(reason, args...)}
+	}
+	v, err := ParseDecimal(value)
+	if err != nil {
+		return reject("not a decimal")
+	}
+	min, max, step, err := f.parse()
+	if err != nil {
+		return "", err
+	}
+	// below the min the offset to round would be negative
+	if min.Sign() > 0 && v.Cmp(min) < 0 {
+		return reject("below min %s", f.Min)
+	}
+	if step.Sign() > 0 {
+		rounded := v.Sub(min).RoundToStep(step, mode).Add(min)
+		if rounded.Cmp(v) != 0 {
+			if !round {
+				return reject("not a multiple of step %s", f.Step)
+			}
+			v = rounded
+			value = rounded.String()
+		}
+	}
+	if max.Sign() > 0 && v.Cmp(max) > 0 {
+		return reject("above max %s", f.Max)
+	}
+	return value, nil
+}
+
+func (f StepFilter) parse() (min, max, step Decimal, err error) {
+	for _, p := range []struct {
+		dst   *Decimal
+		value string
+	}{{&min, f.Min}, {&max, f.Max}, {&step, f.Step}} {
+		if p.value == "" {
+			continue
+		}
+		*p.dst, err = ParseDecimal(p.value)
+		if err != nil {
+			return min, max, step, fmt.Errorf("invalid %s filter: %w", f.Filter, err)
+		}
+	}
+	return min, max, step, nil
+}
+
+// CheckMinNotional check that price * quantity is at least minNotional, a
+// zero minNotional is not checked
+func CheckMinNotional(symbol, filter, price, quantity, minNotional string) error {
+	if minNotional == "" {
+		return nil
+	}
+	min, err := ParseDecimal(minNotional)
+	if err != nil {
+		return fmt.Errorf("invalid %s filter: %w", filter, err)
+	}
+	p, err := ParseDecimal(price)
+	if err != nil {
+		return &FilterError{Symbol: symbol, Filter: filter, Param: "price", Value: price, Reason: "not a decimal"}
+	}
+	q, err := ParseDecimal(quantity)
+	if err != nil {
+		return &FilterError{Symbol: symbol, Filter: filter, Param: "quantity", Value: quantity, Reason: "not a decimal"}
+	}
+	notional := p.Mul(q)
+	if min.Sign() > 0 && notional.Cmp(min) < 0 {
+		return &FilterError{Symbol: symbol, Filter: filter, Param: "notional", Value: notional.String(),
+			Reason: fmt.Sprintf("below min notional %s", minNotional)}
+	}
+	return nil
+}
+
+// CheckIcebergParts check that an iceberg order is split in at most limit
+// parts, that is ceil(quantity / icebergQuantity) <= limit
+func CheckIcebergParts(symbol, filter, quantity, icebergQuantity string, limit int) error {
+	q, err := ParseDecimal(quantity)
+	if err != nil {
+		return &FilterError{Symbol: symbol, Filter: filter, Param: "quantity", Value: quantity, Reason: "not a decimal"}
+	}
+	iq, err := ParseDecimal(icebergQuantity)
+	if err != nil || iq.Sign() <= 0 {
+		return &FilterError{Symbol: symbol, Filter: filter, Param: "icebergQty", Value: icebergQuantity, Reason: "not a positive decimal"}
+	}
+	parts := q.QuoInt(iq, RoundUp)
+	if parts.Cmp(big.NewInt(int64(limit))) > 0 {
+		return &FilterError{Symbol: symbol, Filter: filter, Param: "icebergQty", Value: icebergQuantity,
+			Reason: fmt.Sprintf("%s parts above limit %d", parts, limit)}
+	}
+	return nil
+}
+
+// CheckMaxNumAlgoOrders check that one more algo order does not exceed the
+// max number of open algo orders
+func CheckMaxNumAlgoOrders(symbol, filter string, open, max int) error {
+	if max > 0 && open >= max {
+		return &FilterError{Symbol: symbol, Filter: filter, Param: "openAlgoOrders", Value: fmt.Sprint(open),
+			Reason: fmt.Sprintf("max %d algo orders reached", max)}
+	}
+	return nil
+}
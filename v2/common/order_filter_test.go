@@ -0,0 +1,76 @@
+package common
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStepFilterApply(t *testing.T) {
+	f := StepFilter{Filter: "PRICE_FILTER", Min: "0.01", Max: "1000.00", Step: "0.01"}
+	tests := []struct {
+		name   string
+		value  string
+		round  bool
+		mode   RoundingMode
+		want   string
+		reason string
+	}{
+		{name: "valid", value: "10.25", want: "10.25"},
+		{name: "keep formatting", value: "10.2500", want: "10.2500"},
+		{name: "off step", value: "10.255", reason: "not a multiple of step 0.01"},
+		{name: "round down", value: "10.255", round: true, mode: RoundDown, want: "10.25"},
+		{name: "round up", value: "10.255", round: true, mode: RoundUp, want: "10.26"},
+		{name: "below min", value: "0.001", round: true, reason: "below min 0.01"},
+		{name: "above max", value: "1000.01", reason: "above max 1000.00"},
+		{name: "not a decimal", value: "ten", reason: "not a decimal"},
+		{name: "fraction", value: "41/4", reason: "not a decimal"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := f.Apply("BTCUSDT", "price", tt.value, tt.round, tt.mode)
+			if tt.reason == "" {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+				return
+			}
+			var filterErr *FilterError
+			if assert.True(t, errors.As(err, &filterErr)) {
+				assert.Equal(t, tt.reason, filterErr.Reason)
+				assert.Equal(t, "price", filterErr.Param)
+			}
+			assert.True(t, IsFilterFailure(err))
+			assert.True(t, errors.Is(err, ErrFilterFailure))
+		})
+	}
+}
+
+func TestStepFilterFromMin(t *testing.T) {
+	// Steps are counted from the min
+	f := StepFilter{Filter: "LOT_SIZE", Min: "0.5", Step: "1"}
+	got, err := f.Apply("BTCUSDT", "quantity", "2.5", false, RoundDown)
+	assert.NoError(t, err)
+	assert.Equal(t, "2.5", got)
+	_, err = f.Apply("BTCUSDT", "quantity", "2", false, RoundDown)
+	assert.Error(t, err)
+}
+
+func TestCheckMinNotional(t *testing.T) {
+	assert.NoError(t, CheckMinNotional("BTCUSDT", "MIN_NOTIONAL", "0.1", "100", "10"))
+	err := CheckMinNotional("BTCUSDT", "MIN_NOTIONAL", "0.1", "99.9", "10")
+	assert.EqualError(t, err, "<FilterError> symbol=BTCUSDT, filter=MIN_NOTIONAL, notional=9.99: below min notional 10")
+	assert.NoError(t, CheckMinNotional("BTCUSDT", "MIN_NOTIONAL", "0.1", "1", ""))
+}
+
+func TestCheckIcebergParts(t *testing.T) {
+	assert.NoError(t, CheckIcebergParts("BTCUSDT", "ICEBERG_PARTS", "10", "1", 10))
+	assert.Error(t, CheckIcebergParts("BTCUSDT", "ICEBERG_PARTS", "10.1", "1", 10))
+	assert.Error(t, CheckIcebergParts("BTCUSDT", "ICEBERG_PARTS", "10", "0", 10))
+}
+
+func TestCheckMaxNumAlgoOrders(t *testing.T) {
+	assert.NoError(t, CheckMaxNumAlgoOrders("BTCUSDT", "MAX_NUM_ALGO_ORDERS", 4, 5))
+	assert.Error(t, CheckMaxNumAlgoOrders("BTCUSDT", "MAX_NUM_ALGO_ORDERS", 5, 5))
+	assert.NoError(t, CheckMaxNumAlgoOrders("BTCUSDT", "MAX_NUM_ALGO_ORDERS", 5, 0))
+}
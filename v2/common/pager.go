@@ -0,0 +1,283 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+)
+
+// ErrPageOverflow is reported when a full page of a time window holds items
+// of a single millisecond, so the window can not be narrowed any further
+var ErrPageOverflow = errors.New("page limit reached within a single millisecond")
+
+// PageQuery is the query of one page of a history endpoint. FromID is set
+// when paging by id, StartTime and EndTime (in ms) otherwise.
+type PageQuery struct {
+	FromID    *int64
+	StartTime int64
+	EndTime   int64
+	// Offset is the number of items of the window already fetched, for the
+	// endpoints paging a window by offset or page number
+	Offset int
+	Limit  int
+}
+
+// Pager describe how to walk a history endpoint across an arbitrarily long
+// range, one request per page. The requests go through the client, so set
+// its RateLimiter to throttle them by request weight.
+type Pager[T any] struct {
+	// Fetch request the items matching q
+	Fetch func(ctx context.Context, q PageQuery) ([]T, error)
+	// Limit is the maximum number of items returned by a request
+	Limit int
+	// MaxWindow is the maximum span between the start and end time of a
+	// request, 0 means unlimited
+	MaxWindow time.Duration
+	// Time return the time of an item in ms
+	Time func(T) int64
+	// ID return the id of an item when the endpoint pages by id, the walk
+	// then continues by id after the first page found by time
+	ID func(T) int64
+	// Key identify an item to drop the duplicates at page boundaries, it
+	// defaults to ID
+	Key func(T) interface{}
+	// Offset tells the endpoint pages a window by offset, the items of a
+	// window are then sorted by time since the endpoint may return them in
+	// descending order
+	Offset bool
+
+	// StartTime is the start of the range in ms, FromID takes precedence
+	// when the endpoint pages by id. When both are unset an id walk starts
+	// at id 0, and a time walk one window before EndTime.
+	StartTime int64
+	// EndTime is the end of the range in ms, 0 means the time of the first
+	// request
+	EndTime int64
+	FromID  *int64
+}
+
+// Iterator walk the items of a history endpoint page by page
+//
+//	it := pager.Iterator()
+//	for it.Next(ctx) {
+//		item := it.Item()
+//	}
+//	if err := it.Err(); err != nil {
+//	}
+type Iterator[T any] struct {
+	next func(ctx context.Context) ([]T, bool, error)
+	page []T
+	item T
+	done bool
+	err  error
+}
+
+// NewIterator create an iterator over the pages returned by next, which
+// reports done with the last page
+func NewIterator[T any](next func(ctx context.Context) (page []T, done bool, err error)) *Iterator[T] {
+	return &Iterator[T]{next: next}
+}
+
+// Next advance to the next item, fetching the next page when needed. It
+// returns false at the end of the range or on error.
+func (it *Iterator[T]) Next(ctx context.Context) bool {
+	for len(it.page) == 0 {
+		if it.done || it.err != nil {
+			return false
+		}
+		it.page, it.done, it.err = it.next(ctx)
+		if it.err != nil {
+			return false
+		}
+	}
+	it.item, it.page = it.page[0], it.page[1:]
+	return true
+}
+
+// Item return the current item
+func (it *Iterator[T]) Item() T {
+	return it.item
+}
+
+// Err return the error that stopped the iteration
+func (it *Iterator[T]) Err() error {
+	return it.err
+}
+
+// All collect the remaining items
+func (it *Iterator[T]) All(ctx context.Context) ([]T, error) {
+	var items []T
+	for it.Next(ctx) {
+		items = append(items, it.Item())
+	}
+	return items, it.Err()
+}
+
+// Iterator return an iterator walking the range of p
+func (p Pager[T]) Iterator() *Iterator[T] {
+	if p.Key == nil && p.ID != nil {
+		p.Key = func(item T) interface{} { return p.ID(item) }
+	}
+	w := &pagerWalk[T]{p: p, fromID: p.FromID, start: p.StartTime}
+	return NewIterator(w.next)
+}
+
+// pagerWalk is the state of a Pager iteration
+type pagerWalk[T any] struct {
+	p      Pager[T]
+	fromID *int64
+	start  int64
+	end    int64
+	// seen hold the keys of the items already returned at the boundary of
+	// the current window
+	seen map[interface{}]bool
+}
+
+func (w *pagerWalk[T]) next(ctx context.Context) ([]T, bool, error) {
+	p := w.p
+	if w.end == 0 {
+		w.end = p.EndTime
+		if w.end == 0 {
+			w.end = time.Now().UnixMilli()
+		}
+		if w.fromID == nil && w.start == 0 {
+			if p.ID != nil {
+				w.fromID = new(int64)
+			} else if p.MaxWindow > 0 {
+				w.start = w.end - p.MaxWindow.Milliseconds() + 1
+			}
+		}
+	}
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, false, err
+		}
+		if w.fromID != nil {
+			return w.nextByID(ctx)
+		}
+		if w.start > w.end {
+			return nil, true, nil
+		}
+		items, err := w.nextByTime(ctx)
+		if err != nil || len(items) > 0 {
+			return items, false, err
+		}
+	}
+}
+
+// nextByID fetch the items from fromID, the walk ends with a partial page
+// or an item past the end of the range
+func (w *pagerWalk[T]) nextByID(ctx context.Context) ([]T, bool, error) {
+	p := w.p
+	items, err := p.Fetch(ctx, PageQuery{FromID: w.fromID, Limit: p.Limit})
+	if err != nil {
+		return nil, false, err
+	}
+	done := len(items) < p.Limit
+	for i, item := range items {
+		if p.Time(item) > w.end {
+			items, done = items[:i], true
+			break
+		}
+	}
+	if len(items) > 0 {
+		fromID := p.ID(items[len(items)-1]) + 1
+		w.fromID = &fromID
+	}
+	return items, done, nil
+}
+
+// nextByTime fetch the items of the window starting at start, it returns an
+// empty page when the window is exhausted without new items
+func (w *pagerWalk[T]) nextByTime(ctx context.Context) ([]T, error) {
+	p := w.p
+	end := w.end
+	if p.MaxWindow > 0 && w.start+p.MaxWindow.Milliseconds()-1 < end {
+		end = w.start + p.MaxWindow.Milliseconds() - 1
+	}
+	if p.Offset {
+		items, err := w.fetchWindow(ctx, end)
+		if err != nil {
+			return nil, err
+		}
+		w.start = end + 1
+		return items, nil
+	}
+	items, err := p.Fetch(ctx, PageQuery{StartTime: w.start, EndTime: end, Limit: p.Limit})
+	if err != nil {
+		return nil, err
+	}
+	if p.ID != nil && len(items) > 0 {
+		// The first page found by time, carry on by id
+		fromID := p.ID(items[len(items)-1]) + 1
+		w.fromID = &fromID
+		return items, nil
+	}
+	if len(items) < p.Limit {
+		items = w.dedup(items)
+		w.start, w.seen = end+1, nil
+		return items, nil
+	}
+	// The window holds more items, continue from the time of the last one,
+	// which may be shared by items of the next page
+	last := p.Time(items[len(items)-1])
+	items = w.dedup(items)
+	if last == w.start && len(items) == 0 {
+		return nil, ErrPageOverflow
+	}
+	if last != w.start || w.seen == nil {
+		w.start, w.seen = last, make(map[interface{}]bool)
+	}
+	for _, item := range items {
+		if p.Key != nil && p.Time(item) == last {
+			w.seen[p.Key(item)] = true
+		}
+	}
+	return items, nil
+}
+
+// fetchWindow fetch every page of the window ending at end by offset and
+// sort the items by time
+func (w *pagerWalk[T]) fetchWindow(ctx context.Context, end int64) ([]T, error) {
+	p := w.p
+	w.seen = nil
+	var window []T
+	for offset := 0; ; offset += p.Limit {
+		items, err := p.Fetch(ctx, PageQuery{StartTime: w.start, EndTime: end, Offset: offset, Limit: p.Limit})
+		if err != nil {
+			return nil, err
+		}
+		// Items added while paging shift the offsets, drop the repeated ones
+		window = append(window, w.dedup(items)...)
+		if len(items) < p.Limit {
+			break
+		}
+	}
+	sort.SliceStable(window, func(i, j int) bool {
+		return p.Time(window[i]) < p.Time(window[j])
+	})
+	return window, nil
+}
+
+// dedup drop the items already seen and record the others
+func (w *pagerWalk[T]) dedup(items []T) []T {
+	if w.p.Key == nil {
+		return items
+	}
+	if w.seen == nil {
+		w.seen = make(map[interface{}]bool)
+	}
+	kept := items[:0]
+	for _, item := range items {
+		key := w.p.Key(item)
+		if w.seen[key] {
+			continue
+		}
+		if w.p.Offset {
+			w.seen[key] = true
+		}
+		kept = append(kept, item)
+	}
+	return kept
+}
@@ -0,0 +1,169 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type pagerItem struct {
+	id   int64
+	time int64
+}
+
+// pagerEndpoint serve items sorted by id, like a history endpoint
+type pagerEndpoint struct {
+	t       *testing.T
+	items   []pagerItem
+	window  int64
+	desc    bool
+	queries []PageQuery
+}
+
+func (e *pagerEndpoint) fetch(ctx context.Context, q PageQuery) ([]pagerItem, error) {
+	e.queries = append(e.queries, q)
+	var matched []pagerItem
+	for _, item := range e.items {
+		switch {
+		case q.FromID != nil:
+			if item.id >= *q.FromID {
+				matched = append(matched, item)
+			}
+		default:
+			require.True(e.t, e.window == 0 || q.EndTime-q.StartTime < e.window, "window too large")
+			if item.time >= q.StartTime && item.time <= q.EndTime {
+				matched = append(matched, item)
+			}
+		}
+	}
+	if e.desc {
+		sort.Slice(matched, func(i, j int) bool { return matched[i].id > matched[j].id })
+	}
+	if q.Offset > len(matched) {
+		return nil, nil
+	}
+	matched = matched[q.Offset:]
+	if len(matched) > q.Limit {
+		matched = matched[:q.Limit]
+	}
+	return matched, nil
+}
+
+func (e *pagerEndpoint) pager() Pager[pagerItem] {
+	return Pager[pagerItem]{
+		Fetch:     e.fetch,
+		Limit:     4,
+		MaxWindow: time.Duration(e.window) * time.Millisecond,
+		Time:      func(item pagerItem) int64 { return item.time },
+		Key:       func(item pagerItem) interface{} { return item.id },
+	}
+}
+
+func pagerIDs(items []pagerItem) []int64 {
+	ids := make([]int64, len(items))
+	for i, item := range items {
+		ids[i] = item.id
+	}
+	return ids
+}
+
+func TestPagerByID(t *testing.T) {
+	assert := assert.New(t)
+	e := &pagerEndpoint{t: t, window: 100}
+	for id := int64(0); id < 20; id++ {
+		e.items = append(e.items, pagerItem{id: id, time: 1000 + id*10})
+	}
+	p := e.pager()
+	p.ID = func(item pagerItem) int64 { return item.id }
+	p.StartTime, p.EndTime = 735, 1125
+	items, err := p.Iterator().All(context.Background())
+	assert.NoError(err)
+	assert.Equal([]int64{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12}, pagerIDs(items))
+	// Empty windows are skipped until the first page, then the walk goes on by id
+	assert.Equal([]PageQuery{
+		{StartTime: 735, EndTime: 834, Limit: 4},
+		{StartTime: 835, EndTime: 934, Limit: 4},
+		{StartTime: 935, EndTime: 1034, Limit: 4},
+	}, e.queries[:3])
+	assert.Equal(int64(4), *e.queries[3].FromID)
+	assert.Len(e.queries, 6)
+
+	e.queries = nil
+	p.FromID, p.EndTime = new(int64), 0
+	*p.FromID = 17
+	items, err = p.Iterator().All(context.Background())
+	assert.NoError(err)
+	assert.Equal([]int64{17, 18, 19}, pagerIDs(items))
+	assert.Len(e.queries, 1)
+}
+
+func TestPagerByTime(t *testing.T) {
+	assert := assert.New(t)
+	e := &pagerEndpoint{t: t, window: 50}
+	// Several items share a millisecond across page boundaries
+	times := []int64{0, 0, 1, 1, 1, 2, 3, 3, 3, 40, 41, 42, 43, 44, 45, 46, 120}
+	for i, ts := range times {
+		e.items = append(e.items, pagerItem{id: int64(i), time: 1000 + ts})
+	}
+	p := e.pager()
+	p.StartTime, p.EndTime = 1000, 1130
+	it := p.Iterator()
+	var ids []int64
+	for it.Next(context.Background()) {
+		ids = append(ids, it.Item().id)
+	}
+	assert.NoError(it.Err())
+	assert.Equal([]int64{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}, ids)
+}
+
+func TestPagerByOffset(t *testing.T) {
+	assert := assert.New(t)
+	e := &pagerEndpoint{t: t, window: 100, desc: true}
+	for id := int64(0); id < 11; id++ {
+		e.items = append(e.items, pagerItem{id: id, time: id * 20})
+	}
+	p := e.pager()
+	p.Offset = true
+	p.StartTime, p.EndTime = 10, 300
+	items, err := p.Iterator().All(context.Background())
+	assert.NoError(err)
+	assert.Equal([]int64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}, pagerIDs(items))
+	assert.Equal([]PageQuery{
+		{StartTime: 10, EndTime: 109, Offset: 0, Limit: 4},
+		{StartTime: 10, EndTime: 109, Offset: 4, Limit: 4},
+		{StartTime: 110, EndTime: 209, Offset: 0, Limit: 4},
+		{StartTime: 110, EndTime: 209, Offset: 4, Limit: 4},
+		{StartTime: 210, EndTime: 300, Offset: 0, Limit: 4},
+	}, e.queries)
+}
+
+func TestPagerOverflow(t *testing.T) {
+	e := &pagerEndpoint{t: t}
+	for id := int64(0); id < 6; id++ {
+		e.items = append(e.items, pagerItem{id: id, time: 5})
+	}
+	p := e.pager()
+	p.StartTime, p.EndTime = 1, 10
+	items, err := p.Iterator().All(context.Background())
+	assert.True(t, errors.Is(err, ErrPageOverflow))
+	assert.Equal(t, []int64{0, 1, 2, 3}, pagerIDs(items))
+}
+
+func TestPagerError(t *testing.T) {
+	errFetch := errors.New("fetch failed")
+	it := Pager[pagerItem]{
+		Fetch: func(ctx context.Context, q PageQuery) ([]pagerItem, error) {
+			return nil, errFetch
+		},
+		Limit: 4,
+		Time:  func(item pagerItem) int64 { return item.time },
+	}.Iterator()
+	assert.False(t, it.Next(context.Background()))
+	assert.Equal(t, errFetch, it.Err())
+	assert.False(t, it.Next(context.Background()))
+}
@@ -23,3 +23,17 @@ func (p *PriceLevel) Parse() (float64, float64, error) {
 	}
 	return price, quantity, nil
 }
+
+// ParseDecimal parses this PriceLevel's Price and Quantity as
+// exact decimals.
+func (p *PriceLevel) ParseDecimal() (price, quantity Decimal, err error) {
+	price, err = ParseDecimal(p.Price)
+	if err != nil {
+		return Decimal{}, Decimal{}, err
+	}
+	quantity, err = ParseDecimal(p.Quantity)
+	if err != nil {
+		return price, Decimal{}, err
+	}
+	return price, quantity, nil
+}
@@ -0,0 +1,304 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Rate limit types reported by exchange info and the response headers
+const (
+	RateLimitRequestWeight = "REQUEST_WEIGHT"
+	RateLimitOrders        = "ORDERS"
+	RateLimitRawRequests   = "RAW_REQUESTS"
+)
+
+// ErrRateLimited is matched by errors.Is for every *RateLimitError
+var ErrRateLimited = errors.New("rate limited")
+
+// RateLimitError is returned by a fail fast RateLimiter when a request would
+// breach a limit or when the client is backing off after a 429/418 response
+type RateLimitError struct {
+	Type       string
+	Interval   time.Duration
+	Limit      int64
+	RetryAfter time.Duration
+}
+
+// Error return the limit breached and how long to wait
+func (e *RateLimitError) Error() string {
+	if e.Type == "" {
+		return fmt.Sprintf("<RateLimitError> banned, retry after %s", e.RetryAfter)
+	}
+	return fmt.Sprintf("<RateLimitError> %s limit %d per %s reached, retry after %s", e.Type, e.Limit, e.Interval, e.RetryAfter)
+}
+
+// Is make errors.Is(err, ErrRateLimited) true
+func (e *RateLimitError) Is(target error) bool {
+	return target == ErrRateLimited
+}
+
+// RateLimit define a limit enforced by the exchange over a fixed window
+type RateLimit struct {
+	Type     string
+	Interval time.Duration
+	Limit    int64
+}
+
+// ParseRateLimit convert a rate limit from exchange info, ok is false for an unknown interval
+func ParseRateLimit(limitType, interval string, intervalNum, limit int64) (rl RateLimit, ok bool) {
+	var unit time.Duration
+	switch interval {
+	case "SECOND":
+		unit = time.Second
+	case "MINUTE":
+		unit = time.Minute
+	case "HOUR":
+		unit = time.Hour
+	case "DAY":
+		unit = 24 * time.Hour
+	default:
+		return rl, false
+	}
+	if intervalNum <= 0 {
+		intervalNum = 1
+	}
+	return RateLimit{Type: limitType, Interval: time.Duration(intervalNum) * unit, Limit: limit}, true
+}
+
+type rateLimitKey struct {
+	limitType string
+	interval  time.Duration
+}
+
+type rateCounter struct {
+	limit  int64
+	used   int64
+	window time.Time
+}
+
+// roll reset the counter when now is past its window
+func (r *rateCounter) roll(interval time.Duration, now time.Time) {
+	window := now.Truncate(interval)
+	if !window.Equal(r.window) {
+		r.window = window
+		r.used = 0
+	}
+}
+
+// RateLimiter keep track of the request weight and order count used in each
+// window and wait, or fail fast, before a request would breach a limit. Limits
+// are seeded from exchange info and counters are kept in sync with the
+// X-MBX-USED-WEIGHT-* and X-MBX-ORDER-COUNT-* response headers.
+type RateLimiter struct {
+	// FailFast return a *RateLimitError instead of blocking until the window resets
+	FailFast bool
+
+	mu       sync.Mutex
+	counters map[rateLimitKey]*rateCounter
+	retryAt  time.Time
+	now      func() time.Time
+}
+
+// NewRateLimiter init a rate limiter with limits
+func NewRateLimiter(limits ...RateLimit) *RateLimiter {
+	l := &RateLimiter{
+		counters: map[rateLimitKey]*rateCounter{},
+		now:      time.Now,
+	}
+	l.SetLimits(limits...)
+	return l
+}
+
+// SetLimits replace the limits enforced, the usage of the current windows is kept
+func (l *RateLimiter) SetLimits(limits ...RateLimit) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, counter := range l.counters {
+		counter.limit = 0
+	}
+	for _, limit := range limits {
+		l.counter(limit.Type, limit.Interval).limit = limit.Limit
+	}
+}
+
+// Limits return the limits enforced
+func (l *RateLimiter) Limits() []RateLimit {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	limits := make([]RateLimit, 0, len(l.counters))
+	for key, counter := range l.counters {
+		if counter.limit > 0 {
+			limits = append(limits, RateLimit{Type: key.limitType, Interval: key.interval, Limit: counter.limit})
+		}
+	}
+	return limits
+}
+
+// Used return the usage of a limit in the current window
+func (l *RateLimiter) Used(limitType string, interval time.Duration) int64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	counter, ok := l.counters[rateLimitKey{limitType, interval}]
+	if !ok {
+		return 0
+	}
+	counter.roll(interval, l.now())
+	return counter.used
+}
+
+func (l *RateLimiter) counter(limitType string, interval time.Duration) *rateCounter {
+	key := rateLimitKey{limitType, interval}
+	counter, ok := l.counters[key]
+	if !ok {
+		counter = &rateCounter{}
+		l.counters[key] = counter
+	}
+	return counter
+}
+
+// Wait reserve weight and orders in every window, blocking until they fit
+// or ctx is done. With FailFast set a *RateLimitError is returned instead.
+func (l *RateLimiter) Wait(ctx context.Context, weight, orders int64) error {
+	for {
+		err := l.reserve(weight, orders)
+		if err == nil {
+			return nil
+		}
+		rerr := err.(*RateLimitError)
+		if l.FailFast {
+			return rerr
+		}
+		timer := time.NewTimer(rerr.RetryAfter)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+func (l *RateLimiter) reserve(weight, orders int64) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := l.now()
+	if now.Before(l.retryAt) {
+		return &RateLimitError{RetryAfter: l.retryAt.Sub(now)}
+	}
+	var breached *RateLimitError
+	for key, counter := range l.counters {
+		counter.roll(key.interval, now)
+		cost := rateLimitCost(key.limitType, weight, orders)
+		// A request costing more than a whole window is left to the server
+		if counter.limit <= 0 || cost == 0 || counter.used == 0 || counter.used+cost <= counter.limit {
+			continue
+		}
+		retryAfter := counter.window.Add(key.interval).Sub(now)
+		if breached == nil || retryAfter > breached.RetryAfter {
+			breached = &RateLimitError{
+				Type:       key.limitType,
+				Interval:   key.interval,
+				Limit:      counter.limit,
+				RetryAfter: retryAfter,
+			}
+		}
+	}
+	if breached != nil {
+		return breached
+	}
+	for key, counter := range l.counters {
+		counter.used += rateLimitCost(key.limitType, weight, orders)
+	}
+	return nil
+}
+
+func rateLimitCost(limitType string, weight, orders int64) int64 {
+	switch limitType {
+	case RateLimitRequestWeight:
+		return weight
+	case RateLimitOrders:
+		return orders
+	case RateLimitRawRequests:
+		return 1
+	}
+	return 0
+}
+
+// Update sync the counters with the usage reported in the response headers
+// and back off for Retry-After when the response is a 429 or a 418
+func (l *RateLimiter) Update(statusCode int, header http.Header) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := l.now()
+	for key, values := range header {
+		if len(values) == 0 {
+			continue
+		}
+		var limitType string
+		name := strings.ToUpper(key)
+		switch {
+		case strings.HasPrefix(name, "X-MBX-USED-WEIGHT-"):
+			limitType = RateLimitRequestWeight
+		case strings.HasPrefix(name, "X-MBX-ORDER-COUNT-"):
+			limitType = RateLimitOrders
+		default:
+			continue
+		}
+		interval, ok := parseHeaderInterval(name[strings.LastIndex(name, "-")+1:])
+		if !ok {
+			continue
+		}
+		used, err := strconv.ParseInt(values[0], 10, 64)
+		if err != nil {
+			continue
+		}
+		counter := l.counter(limitType, interval)
+		counter.roll(interval, now)
+		// Requests still in flight are reserved but not yet counted by the server
+		if used > counter.used {
+			counter.used = used
+		}
+	}
+	if statusCode != http.StatusTooManyRequests && statusCode != http.StatusTeapot {
+		return
+	}
+	seconds, err := strconv.ParseInt(header.Get("Retry-After"), 10, 64)
+	if err != nil || seconds <= 0 {
+		return
+	}
+	retryAt := now.Add(time.Duration(seconds) * time.Second)
+	if retryAt.After(l.retryAt) {
+		l.retryAt = retryAt
+	}
+}
+
+// parseHeaderInterval parse the interval suffix of a usage header such as 1M or 10S
+func parseHeaderInterval(s string) (time.Duration, bool) {
+	if len(s) < 2 {
+		return 0, false
+	}
+	num, err := strconv.ParseInt(s[:len(s)-1], 10, 64)
+	if err != nil || num <= 0 {
+		return 0, false
+	}
+	var unit time.Duration
+	switch s[len(s)-1] {
+	case 'S':
+		unit = time.Second
+	case 'M':
+		unit = time.Minute
+	case 'H':
+		unit = time.Hour
+	case 'D':
+		unit = 24 * time.Hour
+	default:
+		return 0, false
+	}
+	return time.Duration(num) * unit, true
+}
@@ -0,0 +1,121 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newTestRateLimiter(now *time.Time, limits ...RateLimit) *RateLimiter {
+	l := NewRateLimiter(limits...)
+	l.now = func() time.Time {
+		return *now
+	}
+	return l
+}
+
+func TestParseRateLimit(t *testing.T) {
+	r := require.New(t)
+	rl, ok := ParseRateLimit("ORDERS", "SECOND", 10, 50)
+	r.True(ok)
+	r.Equal(RateLimit{Type: RateLimitOrders, Interval: 10 * time.Second, Limit: 50}, rl)
+	rl, ok = ParseRateLimit("REQUEST_WEIGHT", "DAY", 1, 1000)
+	r.True(ok)
+	r.Equal(24*time.Hour, rl.Interval)
+	_, ok = ParseRateLimit("REQUEST_WEIGHT", "WEEK", 1, 1000)
+	r.False(ok)
+}
+
+func TestRateLimiterFailFast(t *testing.T) {
+	r := require.New(t)
+	now := time.Date(2024, 1, 1, 0, 0, 10, 0, time.UTC)
+	l := newTestRateLimiter(&now,
+		RateLimit{Type: RateLimitRequestWeight, Interval: time.Minute, Limit: 10},
+		RateLimit{Type: RateLimitOrders, Interval: 10 * time.Second, Limit: 2},
+	)
+	l.FailFast = true
+	ctx := context.Background()
+
+	r.NoError(l.Wait(ctx, 6, 1))
+	r.NoError(l.Wait(ctx, 4, 1))
+	r.Equal(int64(10), l.Used(RateLimitRequestWeight, time.Minute))
+	r.Equal(int64(2), l.Used(RateLimitOrders, 10*time.Second))
+
+	err := l.Wait(ctx, 1, 0)
+	r.True(errors.Is(err, ErrRateLimited))
+	var rerr *RateLimitError
+	r.True(errors.As(err, &rerr))
+	r.Equal(RateLimitRequestWeight, rerr.Type)
+	r.Equal(50*time.Second, rerr.RetryAfter)
+
+	// The order window resets before the weight window
+	now = now.Add(10 * time.Second)
+	r.Equal(int64(0), l.Used(RateLimitOrders, 10*time.Second))
+	r.Error(l.Wait(ctx, 1, 1))
+	r.NoError(l.Wait(ctx, 0, 1))
+
+	now = now.Add(time.Minute)
+	r.NoError(l.Wait(ctx, 10, 0))
+}
+
+func TestRateLimiterUpdate(t *testing.T) {
+	r := require.New(t)
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	l := newTestRateLimiter(&now, RateLimit{Type: RateLimitRequestWeight, Interval: time.Minute, Limit: 1200})
+	l.FailFast = true
+
+	header := http.Header{}
+	header.Set("X-Mbx-Used-Weight", "1195")
+	header.Set("X-Mbx-Used-Weight-1m", "1195")
+	header.Set("X-Mbx-Order-Count-10s", "3")
+	l.Update(http.StatusOK, header)
+	r.Equal(int64(1195), l.Used(RateLimitRequestWeight, time.Minute))
+	r.Equal(int64(3), l.Used(RateLimitOrders, 10*time.Second))
+	r.Error(l.Wait(context.Background(), 10, 0))
+
+	// A lower count from an older response does not undo reservations
+	header.Set("X-Mbx-Used-Weight-1m", "20")
+	l.Update(http.StatusOK, header)
+	r.Equal(int64(1195), l.Used(RateLimitRequestWeight, time.Minute))
+}
+
+func TestRateLimiterRetryAfter(t *testing.T) {
+	r := require.New(t)
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	l := newTestRateLimiter(&now)
+	l.FailFast = true
+
+	header := http.Header{}
+	header.Set("Retry-After", "30")
+	l.Update(http.StatusOK, header)
+	r.NoError(l.Wait(context.Background(), 1, 0))
+
+	l.Update(http.StatusTeapot, header)
+	err := l.Wait(context.Background(), 1, 0)
+	var rerr *RateLimitError
+	r.True(errors.As(err, &rerr))
+	r.Equal(30*time.Second, rerr.RetryAfter)
+
+	now = now.Add(30 * time.Second)
+	r.NoError(l.Wait(context.Background(), 1, 0))
+}
+
+func TestRateLimiterWaitBlocks(t *testing.T) {
+	r := require.New(t)
+	l := NewRateLimiter()
+	header := http.Header{}
+	header.Set("Retry-After", "1")
+	l.Update(http.StatusTooManyRequests, header)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	r.Equal(context.DeadlineExceeded, l.Wait(ctx, 1, 0))
+
+	start := time.Now()
+	r.NoError(l.Wait(context.Background(), 1, 0))
+	r.True(time.Since(start) > 500*time.Millisecond)
+}
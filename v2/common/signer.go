@@ -0,0 +1,148 @@
+package common
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// Signer sign the payload of a signed request, which is the query string
+// followed by the form body, and return the value of the signature parameter
+type Signer interface {
+	Sign(payload []byte) (string, error)
+}
+
+// HMACSigner sign with HMAC-SHA256 and a secret key, the signature is hex encoded
+type HMACSigner struct {
+	SecretKey []byte
+}
+
+// NewHMACSigner init a HMAC-SHA256 signer
+func NewHMACSigner(secretKey string) *HMACSigner {
+	return &HMACSigner{SecretKey: []byte(secretKey)}
+}
+
+// Sign return the hex encoded HMAC-SHA256 of payload
+func (s *HMACSigner) Sign(payload []byte) (string, error) {
+	mac := hmac.New(sha256.New, s.SecretKey)
+	_, err := mac.Write(payload)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(mac.Sum(nil)), nil
+}
+
+// RSASigner sign with RSASSA-PKCS1-v1_5 over SHA-256, the signature is base64 encoded
+type RSASigner struct {
+	PrivateKey *rsa.PrivateKey
+}
+
+// NewRSASigner init a RSA signer from a PKCS#8 PEM encoded private key
+func NewRSASigner(pemBytes []byte) (*RSASigner, error) {
+	key, err := parsePrivateKeyPEM(pemBytes)
+	if err != nil {
+		return nil, err
+	}
+	rsaKey, ok := key.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("expected a RSA private key, got %T", key)
+	}
+	return &RSASigner{PrivateKey: rsaKey}, nil
+}
+
+// NewRSASignerFromFile init a RSA signer from a PKCS#8 PEM file
+func NewRSASignerFromFile(path string) (*RSASigner, error) {
+	pemBytes, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return NewRSASigner(pemBytes)
+}
+
+// Sign return the base64 encoded RSA signature of payload
+func (s *RSASigner) Sign(payload []byte) (string, error) {
+	hashed := sha256.Sum256(payload)
+	signature, err := rsa.SignPKCS1v15(rand.Reader, s.PrivateKey, crypto.SHA256, hashed[:])
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(signature), nil
+}
+
+// Ed25519Signer sign with Ed25519, the signature is base64 encoded
+type Ed25519Signer struct {
+	PrivateKey ed25519.PrivateKey
+}
+
+// NewEd25519Signer init an Ed25519 signer from a PKCS#8 PEM encoded private key
+func NewEd25519Signer(pemBytes []byte) (*Ed25519Signer, error) {
+	key, err := parsePrivateKeyPEM(pemBytes)
+	if err != nil {
+		return nil, err
+	}
+	edKey, ok := key.(ed25519.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("expected an Ed25519 private key, got %T", key)
+	}
+	return &Ed25519Signer{PrivateKey: edKey}, nil
+}
+
+// NewEd25519SignerFromFile init an Ed25519 signer from a PKCS#8 PEM file
+func NewEd25519SignerFromFile(path string) (*Ed25519Signer, error) {
+	pemBytes, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return NewEd25519Signer(pemBytes)
+}
+
+// Sign return the base64 encoded Ed25519 signature of payload
+func (s *Ed25519Signer) Sign(payload []byte) (string, error) {
+	return base64.StdEncoding.EncodeToString(ed25519.Sign(s.PrivateKey, payload)), nil
+}
+
+// NewSignerFromPEM init a RSA or Ed25519 signer depending on the type of the
+// PKCS#8 PEM encoded private key, so keys can be rotated without code changes
+func NewSignerFromPEM(pemBytes []byte) (Signer, error) {
+	key, err := parsePrivateKeyPEM(pemBytes)
+	if err != nil {
+		return nil, err
+	}
+	switch key := key.(type) {
+	case *rsa.PrivateKey:
+		return &RSASigner{PrivateKey: key}, nil
+	case ed25519.PrivateKey:
+		return &Ed25519Signer{PrivateKey: key}, nil
+	}
+	return nil, fmt.Errorf("unsupported private key type %T", key)
+}
+
+// NewSignerFromFile init a RSA or Ed25519 signer from a PKCS#8 PEM file
+func NewSignerFromFile(path string) (Signer, error) {
+	pemBytes, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return NewSignerFromPEM(pemBytes)
+}
+
+func parsePrivateKeyPEM(pemBytes []byte) (interface{}, error) {
+	block, _ := pem.Decode(pemBytes)
+	if block == nil {
+		return nil, errors.New("no PEM data found")
+	}
+	if block.Type != "PRIVATE KEY" {
+		return nil, fmt.Errorf("expected a PKCS#8 PRIVATE KEY block, got %s", block.Type)
+	}
+	return x509.ParsePKCS8PrivateKey(block.Bytes)
+}
@@ -0,0 +1,86 @@
+package common
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const signerPayload = "symbol=LTCBTC&side=BUY&type=LIMIT&timeInForce=GTC&quantity=1&price=0.1&recvWindow=5000&timestamp=1499827319559"
+
+func encodePKCS8(t *testing.T, key interface{}) []byte {
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+}
+
+func TestHMACSigner(t *testing.T) {
+	r := require.New(t)
+	s := NewHMACSigner("NhqPtmdSJYdKjVHjA7PZj4Mge3R5YNiP1e3UZjInClVN65XAbvqqM6A7H5fATj0j")
+	signature, err := s.Sign([]byte(signerPayload))
+	r.NoError(err)
+	r.Equal("c8db56825ae71d6d79447849e617115f4a920fa2acdcab2b053c4b2838bd6b71", signature)
+}
+
+func TestRSASigner(t *testing.T) {
+	r := require.New(t)
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	r.NoError(err)
+	s, err := NewRSASigner(encodePKCS8(t, key))
+	r.NoError(err)
+
+	signature, err := s.Sign([]byte(signerPayload))
+	r.NoError(err)
+	raw, err := base64.StdEncoding.DecodeString(signature)
+	r.NoError(err)
+	hashed := sha256.Sum256([]byte(signerPayload))
+	r.NoError(rsa.VerifyPKCS1v15(&key.PublicKey, crypto.SHA256, hashed[:], raw))
+
+	_, edKey, _ := ed25519.GenerateKey(rand.Reader)
+	_, err = NewRSASigner(encodePKCS8(t, edKey))
+	r.Error(err)
+}
+
+func TestEd25519Signer(t *testing.T) {
+	r := require.New(t)
+	pub, key, err := ed25519.GenerateKey(rand.Reader)
+	r.NoError(err)
+	path := filepath.Join(t.TempDir(), "key.pem")
+	r.NoError(os.WriteFile(path, encodePKCS8(t, key), 0600))
+	s, err := NewEd25519SignerFromFile(path)
+	r.NoError(err)
+
+	signature, err := s.Sign([]byte(signerPayload))
+	r.NoError(err)
+	raw, err := base64.StdEncoding.DecodeString(signature)
+	r.NoError(err)
+	r.True(ed25519.Verify(pub, []byte(signerPayload), raw))
+}
+
+func TestNewSignerFromPEM(t *testing.T) {
+	r := require.New(t)
+	_, edKey, _ := ed25519.GenerateKey(rand.Reader)
+	s, err := NewSignerFromPEM(encodePKCS8(t, edKey))
+	r.NoError(err)
+	r.IsType(&Ed25519Signer{}, s)
+
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	s, err = NewSignerFromPEM(encodePKCS8(t, rsaKey))
+	r.NoError(err)
+	r.IsType(&RSASigner{}, s)
+
+	_, err = NewSignerFromPEM([]byte("not a key"))
+	r.Error(err)
+	_, err = NewSignerFromPEM(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(rsaKey)}))
+	r.Error(err)
+}
@@ -0,0 +1,178 @@
+package common
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+)
+
+// OverflowPolicy define what a stream does with an event when its channel
+// is full
+type OverflowPolicy int
+
+// Global enums
+const (
+	// OverflowBlock wait for the consumer, which stops reading the
+	// connection until there is room again
+	OverflowBlock OverflowPolicy = iota
+	// OverflowDropOldest discard the oldest buffered event to make room
+	OverflowDropOldest
+	// OverflowDropNewest discard the incoming event
+	OverflowDropNewest
+)
+
+// StreamConfig is the configuration of a channel based stream
+type StreamConfig struct {
+	// Buffer is the capacity of the event channel, at least 1 with the drop
+	// policies
+	Buffer int
+	// Overflow is the policy applied when the channel is full
+	Overflow OverflowPolicy
+	// OnError is called for the errors the stream survives, such as a
+	// message which could not be decoded or a reconnection
+	OnError func(err error)
+}
+
+// StreamOption define option of a channel based stream
+type StreamOption func(*StreamConfig)
+
+// WithBuffer set the capacity of the event channel, 64 by default
+func WithBuffer(size int) StreamOption {
+	return func(c *StreamConfig) {
+		c.Buffer = size
+	}
+}
+
+// WithOverflow set the policy applied when the channel is full,
+// OverflowBlock by default
+func WithOverflow(policy OverflowPolicy) StreamOption {
+	return func(c *StreamConfig) {
+		c.Overflow = policy
+	}
+}
+
+// WithErrHandler set the handler of the errors the stream survives
+func WithErrHandler(handler func(err error)) StreamOption {
+	return func(c *StreamConfig) {
+		c.OnError = handler
+	}
+}
+
+// ServeFunc start a callback based stream, such as a WsXxxServe function
+type ServeFunc[E any] func(handler func(event E), errHandler func(err error)) (doneC, stopC chan struct{}, err error)
+
+// Stream deliver the events of a websocket stream on a channel. The channel
+// is closed once the stream ends, either because its context is done or
+// because the connection was lost for good, Err then tells why.
+type Stream[E any] struct {
+	events  chan E
+	doneC   chan struct{}
+	dropped atomic.Uint64
+
+	mu      sync.Mutex
+	lastErr error
+	err     error
+}
+
+// NewStream start serve and deliver its events on the channel of the
+// returned stream until ctx is done. The error of the connection is returned
+// as is.
+func NewStream[E any](ctx context.Context, serve ServeFunc[E], opts ...StreamOption) (*Stream[E], error) {
+	cfg := &StreamConfig{Buffer: 64}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	if cfg.Buffer < 1 && cfg.Overflow != OverflowBlock {
+		cfg.Buffer = 1
+	}
+	s := &Stream[E]{
+		events: make(chan E, cfg.Buffer),
+		doneC:  make(chan struct{}),
+	}
+	handler := func(event E) {
+		s.push(ctx, cfg.Overflow, event)
+	}
+	errHandler := func(err error) {
+		s.mu.Lock()
+		s.lastErr = err
+		s.mu.Unlock()
+		if cfg.OnError != nil {
+			cfg.OnError(err)
+		}
+	}
+	doneC, stopC, err := serve(handler, errHandler)
+	if err != nil {
+		return nil, err
+	}
+	go func() {
+		select {
+		case <-ctx.Done():
+			close(stopC)
+			<-doneC
+		case <-doneC:
+		}
+		// The handler is not called anymore once doneC is closed
+		s.mu.Lock()
+		s.err = ctx.Err()
+		if s.err == nil {
+			s.err = s.lastErr
+		}
+		s.mu.Unlock()
+		close(s.events)
+		close(s.doneC)
+	}()
+	return s, nil
+}
+
+func (s *Stream[E]) push(ctx context.Context, policy OverflowPolicy, event E) {
+	switch policy {
+	case OverflowDropNewest:
+		select {
+		case s.events <- event:
+		default:
+			s.dropped.Add(1)
+		}
+	case OverflowDropOldest:
+		for {
+			select {
+			case s.events <- event:
+				return
+			default:
+			}
+			select {
+			case <-s.events:
+				s.dropped.Add(1)
+			default:
+			}
+		}
+	default:
+		select {
+		case s.events <- event:
+		case <-ctx.Done():
+		}
+	}
+}
+
+// Events return the channel of the events, closed once the stream ended
+func (s *Stream[E]) Events() <-chan E {
+	return s.events
+}
+
+// Done is closed once the stream ended
+func (s *Stream[E]) Done() <-chan struct{} {
+	return s.doneC
+}
+
+// Err return why the stream ended, the context error when it was cancelled
+// or the last error of the connection otherwise. It is nil while the stream
+// is running.
+func (s *Stream[E]) Err() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.err
+}
+
+// Dropped return the number of events discarded by the overflow policy
+func (s *Stream[E]) Dropped() uint64 {
+	return s.dropped.Load()
+}
@@ -0,0 +1,598 @@
+package common
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// ErrStreamClientClosed is returned when using a StreamClient after Close
+var ErrStreamClientClosed = errors.New("websocket stream client closed")
+
+// StreamsDroppedError is reported when a connection of a StreamClient is
+// lost for good. Its streams are no longer subscribed and can be subscribed
+// again.
+type StreamsDroppedError struct {
+	Streams []string
+	// Err is why the connection could not be redialed
+	Err error
+}
+
+func (e *StreamsDroppedError) Error() string {
+	return "websocket streams dropped: " + strings.Join(e.Streams, ", ") + ": " + e.Err.Error()
+}
+
+func (e *StreamsDroppedError) Unwrap() error {
+	return e.Err
+}
+
+// StreamConnHooks follow a connection served by a StreamTransport, they are
+// called from the reading goroutine
+type StreamConnHooks struct {
+	// Message is called with every message read
+	Message func(message []byte)
+	// Disconnected is called with the error of every lost connection
+	Disconnected func(err error)
+	// Reconnected is called with every redialed connection before it is read
+	Reconnected func(c *websocket.Conn)
+}
+
+// StreamTransport dial and serve the connections of a StreamClient, with the
+// reconnection policy of its package
+type StreamTransport struct {
+	// Dial open a connection to endpoint
+	Dial func(endpoint string) (*websocket.Conn, error)
+	// Serve read c, and the connections redialed after it, until stopC is
+	// closed, returning nil, or until redialing gives up, returning why.
+	// The connections are not pinged, StreamClient keeps them alive.
+	Serve func(endpoint string, c *websocket.Conn, hooks *StreamConnHooks, stopC chan struct{}) error
+}
+
+// StreamClient multiplexes streams over combined stream connections and
+// subscribes or unsubscribes them live with the SUBSCRIBE and UNSUBSCRIBE
+// methods instead of encoding them in the endpoint. Streams are sharded over
+// as many connections as needed to stay within Binance's per connection
+// limits, and a dropped connection is redialed and resubscribed to its streams.
+type StreamClient struct {
+	// Endpoint is the combined stream endpoint without any stream in it
+	Endpoint string
+	// MaxStreamsPerConnection is the number of streams a connection holds before a new one is opened
+	MaxStreamsPerConnection int
+	// MaxMessagesPerSecond is the number of messages sent per second on a
+	// connection, pings and pongs included
+	MaxMessagesPerSecond int
+	// Keepalive is the interval between two pings, a connection whose ping
+	// is not answered within it is closed. 0 disables the pings.
+	Keepalive time.Duration
+	// Metrics receives the message count and event lag of the streams
+	Metrics Metrics
+
+	transport  StreamTransport
+	errHandler func(err error)
+	nextID     int64
+
+	mu       sync.RWMutex
+	conns    []*streamConn
+	handlers map[string]func(data []byte)
+	closed   bool
+}
+
+// NewStreamClient init a stream client, connections are opened on the first subscription.
+// Errors that do not belong to a call, like dropped connections, are reported to errHandler.
+func NewStreamClient(endpoint string, transport StreamTransport, errHandler func(err error)) *StreamClient {
+	return &StreamClient{
+		Endpoint:                endpoint,
+		MaxStreamsPerConnection: 1024,
+		MaxMessagesPerSecond:    5,
+		transport:               transport,
+		errHandler:              errHandler,
+		handlers:                make(map[string]func(data []byte)),
+	}
+}
+
+// Subscribe subscribe to raw streams like "btcusdt@depth", handler receives the data payload of each stream event
+func (c *StreamClient) Subscribe(ctx context.Context, streams []string, handler func(data []byte)) error {
+	shards, err := c.assign(streams, handler)
+	if err != nil {
+		return err
+	}
+	for i, shard := range shards {
+		_, err := shard.conn.call(ctx, "SUBSCRIBE", shard.streams)
+		if err != nil {
+			c.rollback(shards, shards[:i])
+			return err
+		}
+	}
+	return nil
+}
+
+// streamShard is the part of a subscription sent on a connection
+type streamShard struct {
+	conn    *streamConn
+	streams []string
+}
+
+// assign the new streams to connections with room for them, dialing the
+// missing connections without holding c.mu
+func (c *StreamClient) assign(streams []string, handler func(data []byte)) ([]*streamShard, error) {
+	var dialed []*streamConn
+	c.mu.Lock()
+	for c.missingRoom(streams, dialed) > 0 && !c.closed {
+		c.mu.Unlock()
+		conn, err := c.dial()
+		if err != nil {
+			closeConns(dialed)
+			return nil, err
+		}
+		dialed = append(dialed, conn)
+		c.mu.Lock()
+	}
+	if c.closed {
+		c.mu.Unlock()
+		closeConns(dialed)
+		return nil, ErrStreamClientClosed
+	}
+	var shards []*streamShard
+	index := make(map[*streamConn]*streamShard)
+	for _, stream := range streams {
+		if _, ok := c.handlers[stream]; ok {
+			c.handlers[stream] = handler
+			continue
+		}
+		conn := c.connWithRoom(c.conns)
+		if conn == nil {
+			conn = c.connWithRoom(dialed)
+			c.conns = append(c.conns, conn)
+			dialed = removeConn(dialed, conn)
+		}
+		conn.streams[stream] = struct{}{}
+		c.handlers[stream] = handler
+		shard, ok := index[conn]
+		if !ok {
+			shard = &streamShard{conn: conn}
+			index[conn] = shard
+			shards = append(shards, shard)
+		}
+		shard.streams = append(shard.streams, stream)
+	}
+	c.mu.Unlock()
+	// Another subscription may have made room meanwhile
+	closeConns(dialed)
+	return shards, nil
+}
+
+// missingRoom return how many of the new streams do not fit in the
+// connections and dialed ones, c.mu must be held
+func (c *StreamClient) missingRoom(streams []string, dialed []*streamConn) int {
+	missing := 0
+	seen := make(map[string]bool)
+	for _, stream := range streams {
+		if _, ok := c.handlers[stream]; !ok && !seen[stream] {
+			seen[stream] = true
+			missing++
+		}
+	}
+	for _, conns := range [][]*streamConn{c.conns, dialed} {
+		for _, conn := range conns {
+			if room := c.MaxStreamsPerConnection - len(conn.streams); room > 0 {
+				missing -= room
+			}
+		}
+	}
+	return missing
+}
+
+// connWithRoom return the first of conns with room for one more stream, c.mu must be held
+func (c *StreamClient) connWithRoom(conns []*streamConn) *streamConn {
+	for _, conn := range conns {
+		if len(conn.streams) < c.MaxStreamsPerConnection {
+			return conn
+		}
+	}
+	return nil
+}
+
+// rollback forget streams whose subscription failed, unsubscribing the
+// shards the server accepted and closing the connections left without any
+// stream
+func (c *StreamClient) rollback(shards, accepted []*streamShard) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	for _, shard := range accepted {
+		_, err := shard.conn.call(ctx, "UNSUBSCRIBE", shard.streams)
+		if err != nil {
+			c.errHandler(err)
+		}
+	}
+	c.mu.Lock()
+	var empty []*streamConn
+	for _, shard := range shards {
+		for _, stream := range shard.streams {
+			delete(shard.conn.streams, stream)
+			delete(c.handlers, stream)
+		}
+		if len(shard.conn.streams) == 0 {
+			c.conns = removeConn(c.conns, shard.conn)
+			empty = append(empty, shard.conn)
+		}
+	}
+	c.mu.Unlock()
+	closeConns(empty)
+}
+
+// Unsubscribe unsubscribe from streams, connections left without any stream are closed
+func (c *StreamClient) Unsubscribe(ctx context.Context, streams []string) error {
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		return ErrStreamClientClosed
+	}
+	shards := make(map[*streamConn][]string)
+	for _, stream := range streams {
+		for _, conn := range c.conns {
+			if _, ok := conn.streams[stream]; ok {
+				shards[conn] = append(shards[conn], stream)
+				break
+			}
+		}
+	}
+	c.mu.Unlock()
+
+	for conn, streams := range shards {
+		_, err := conn.call(ctx, "UNSUBSCRIBE", streams)
+		if err != nil {
+			return err
+		}
+		c.mu.Lock()
+		for _, stream := range streams {
+			delete(conn.streams, stream)
+			delete(c.handlers, stream)
+		}
+		empty := len(conn.streams) == 0
+		if empty {
+			c.conns = removeConn(c.conns, conn)
+		}
+		c.mu.Unlock()
+		if empty {
+			conn.close()
+		}
+	}
+	return nil
+}
+
+func removeConn(conns []*streamConn, conn *streamConn) []*streamConn {
+	for i := range conns {
+		if conns[i] == conn {
+			return append(conns[:i], conns[i+1:]...)
+		}
+	}
+	return conns
+}
+
+func closeConns(conns []*streamConn) {
+	for _, conn := range conns {
+		conn.close()
+	}
+}
+
+// Subscriptions return the streams subscribed through this client
+func (c *StreamClient) Subscriptions() []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	res := make([]string, 0, len(c.handlers))
+	for stream := range c.handlers {
+		res = append(res, stream)
+	}
+	return res
+}
+
+// ListSubscriptions ask every connection for its subscriptions with LIST_SUBSCRIPTIONS
+func (c *StreamClient) ListSubscriptions(ctx context.Context) ([]string, error) {
+	c.mu.RLock()
+	conns := append([]*streamConn{}, c.conns...)
+	c.mu.RUnlock()
+
+	res := make([]string, 0)
+	for _, conn := range conns {
+		data, err := conn.call(ctx, "LIST_SUBSCRIPTIONS", nil)
+		if err != nil {
+			return nil, err
+		}
+		var streams []string
+		err = json.Unmarshal(data, &streams)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, streams...)
+	}
+	return res, nil
+}
+
+// Close close all connections, the client can not be used afterwards
+func (c *StreamClient) Close() {
+	c.mu.Lock()
+	conns := c.conns
+	c.conns = nil
+	c.closed = true
+	c.mu.Unlock()
+	closeConns(conns)
+}
+
+func (c *StreamClient) dispatch(msg *streamMessage) {
+	c.mu.RLock()
+	handler, ok := c.handlers[msg.Stream]
+	c.mu.RUnlock()
+	if ok {
+		handler(msg.Data)
+	}
+}
+
+// drop forget conn and its streams once it is lost for good
+func (c *StreamClient) drop(conn *streamConn, err error) {
+	c.mu.Lock()
+	c.conns = removeConn(c.conns, conn)
+	streams := make([]string, 0, len(conn.streams))
+	for stream := range conn.streams {
+		streams = append(streams, stream)
+		delete(c.handlers, stream)
+	}
+	conn.streams = make(map[string]struct{})
+	c.mu.Unlock()
+	if len(streams) > 0 {
+		c.errHandler(&StreamsDroppedError{Streams: streams, Err: err})
+	}
+}
+
+type streamRequest struct {
+	Method string   `json:"method"`
+	Params []string `json:"params,omitempty"`
+	ID     int64    `json:"id"`
+}
+
+// streamMessage is either a stream event or the response to a request
+type streamMessage struct {
+	Stream string          `json:"stream"`
+	Data   json.RawMessage `json:"data"`
+	Result json.RawMessage `json:"result"`
+	Error  *APIError       `json:"error"`
+	ID     *int64          `json:"id"`
+}
+
+// streamConn is one connection of a StreamClient
+type streamConn struct {
+	client *StreamClient
+	// streams is guarded by client.mu
+	streams map[string]struct{}
+
+	writeMu  sync.Mutex
+	conn     *websocket.Conn
+	lastSent time.Time
+
+	pendingMu sync.Mutex
+	pending   map[int64]chan *streamMessage
+
+	stopC chan struct{}
+	doneC chan struct{}
+}
+
+// dial open a new connection of c
+func (c *StreamClient) dial() (*streamConn, error) {
+	ws, err := c.transport.Dial(c.Endpoint)
+	if err != nil {
+		return nil, err
+	}
+	conn := &streamConn{
+		client:  c,
+		streams: make(map[string]struct{}),
+		conn:    ws,
+		pending: make(map[int64]chan *streamMessage),
+		stopC:   make(chan struct{}),
+		doneC:   make(chan struct{}),
+	}
+	conn.setup(ws)
+	go conn.serve(ws)
+	return conn, nil
+}
+
+func (s *streamConn) serve(ws *websocket.Conn) {
+	defer close(s.doneC)
+	hooks := &StreamConnHooks{
+		Message:      s.handle,
+		Disconnected: s.failPending,
+		Reconnected: func(ws *websocket.Conn) {
+			s.setup(ws)
+			s.writeMu.Lock()
+			s.conn = ws
+			s.writeMu.Unlock()
+			go s.resubscribe()
+		},
+	}
+	err := s.client.transport.Serve(s.client.Endpoint, ws, hooks, s.stopC)
+	s.failPending(err)
+	if err != nil {
+		s.client.drop(s, err)
+	}
+}
+
+// setup answer the pings of the server and ping it every Keepalive, both
+// within the message rate limit
+func (s *streamConn) setup(ws *websocket.Conn) {
+	ws.SetPingHandler(func(data string) error {
+		err := s.writeControl(ws, websocket.PongMessage, []byte(data))
+		if e, ok := err.(net.Error); err == websocket.ErrCloseSent || ok && e.Timeout() {
+			return nil
+		}
+		return err
+	})
+	if s.client.Keepalive > 0 {
+		s.keepAlive(ws, s.client.Keepalive)
+	}
+}
+
+// keepAlive ping ws every interval, closing it when the last ping was not
+// answered in time. The first ping is sent right away, ahead of any request.
+func (s *streamConn) keepAlive(ws *websocket.Conn, interval time.Duration) {
+	// The pong handler runs on the reading goroutine
+	var lastResponse atomic.Int64
+	ws.SetPongHandler(func(string) error {
+		lastResponse.Store(time.Now().UnixNano())
+		return nil
+	})
+	sent := time.Now().UnixNano()
+	err := s.writeControl(ws, websocket.PingMessage, nil)
+	if err != nil {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			<-ticker.C
+			if lastResponse.Load() < sent {
+				ws.Close()
+				return
+			}
+			sent = time.Now().UnixNano()
+			err := s.writeControl(ws, websocket.PingMessage, nil)
+			if err != nil {
+				return
+			}
+		}
+	}()
+}
+
+// resubscribe subscribe a redialed connection to the streams it held before
+func (s *streamConn) resubscribe() {
+	s.client.mu.RLock()
+	streams := make([]string, 0, len(s.streams))
+	for stream := range s.streams {
+		streams = append(streams, stream)
+	}
+	s.client.mu.RUnlock()
+	if len(streams) == 0 {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	_, err := s.call(ctx, "SUBSCRIBE", streams)
+	if err != nil {
+		s.client.errHandler(err)
+	}
+}
+
+func (s *streamConn) handle(message []byte) {
+	msg := new(streamMessage)
+	err := json.Unmarshal(message, msg)
+	if err != nil {
+		s.client.errHandler(err)
+		return
+	}
+	if msg.Stream == "" && msg.ID != nil {
+		s.pendingMu.Lock()
+		ch, ok := s.pending[*msg.ID]
+		delete(s.pending, *msg.ID)
+		s.pendingMu.Unlock()
+		if ok {
+			ch <- msg
+		}
+		return
+	}
+	RecordMessage(s.client.Metrics, msg.Stream, msg.Data)
+	s.client.dispatch(msg)
+}
+
+// failPending release the calls waiting for a response on a lost connection
+func (s *streamConn) failPending(err error) {
+	if err == nil {
+		err = ErrStreamClientClosed
+	}
+	s.pendingMu.Lock()
+	defer s.pendingMu.Unlock()
+	for id, ch := range s.pending {
+		ch <- &streamMessage{Error: &APIError{Code: -1, Message: err.Error()}}
+		delete(s.pending, id)
+	}
+}
+
+// call send a request and wait for its response, honouring the message rate limit
+func (s *streamConn) call(ctx context.Context, method string, params []string) (json.RawMessage, error) {
+	req := &streamRequest{
+		Method: method,
+		Params: params,
+		ID:     atomic.AddInt64(&s.client.nextID, 1),
+	}
+	ch := make(chan *streamMessage, 1)
+	s.pendingMu.Lock()
+	s.pending[req.ID] = ch
+	s.pendingMu.Unlock()
+	defer func() {
+		s.pendingMu.Lock()
+		delete(s.pending, req.ID)
+		s.pendingMu.Unlock()
+	}()
+
+	err := s.send(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case msg := <-ch:
+		if msg.Error != nil {
+			return nil, msg.Error
+		}
+		return msg.Result, nil
+	}
+}
+
+func (s *streamConn) send(ctx context.Context, req *streamRequest) error {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	err := s.pace(ctx)
+	if err != nil {
+		return err
+	}
+	return s.conn.WriteJSON(req)
+}
+
+func (s *streamConn) writeControl(ws *websocket.Conn, messageType int, data []byte) error {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	err := s.pace(context.Background())
+	if err != nil {
+		return err
+	}
+	return ws.WriteControl(messageType, data, time.Now().Add(10*time.Second))
+}
+
+// pace wait until the next message can be sent within the rate limit,
+// s.writeMu must be held
+func (s *streamConn) pace(ctx context.Context) error {
+	wait := time.Until(s.lastSent.Add(time.Second / time.Duration(s.client.MaxMessagesPerSecond)))
+	if wait > 0 {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(wait):
+		}
+	}
+	s.lastSent = time.Now()
+	return nil
+}
+
+func (s *streamConn) close() {
+	select {
+	case <-s.stopC:
+	default:
+		close(s.stopC)
+	}
+	<-s.doneC
+}
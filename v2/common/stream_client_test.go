@@ -0,0 +1,325 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/suite"
+)
+
+type streamClientTestSuite struct {
+	suite.Suite
+	server     *httptest.Server
+	mu         sync.Mutex
+	conns      []*websocket.Conn
+	subscribed map[string]bool
+	pings      int
+	refuse     bool
+	client     *StreamClient
+	errs       chan error
+}
+
+func TestStreamClient(t *testing.T) {
+	suite.Run(t, new(streamClientTestSuite))
+}
+
+// SetupTest starts a server that keeps the subscriptions of each connection
+// and pushes one event for every stream subscribed. The client redials a
+// dropped connection once.
+func (s *streamClientTestSuite) SetupTest() {
+	s.conns = nil
+	s.subscribed = map[string]bool{}
+	s.pings = 0
+	s.refuse = false
+	upgrader := websocket.Upgrader{}
+	s.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		refuse := s.refuse
+		s.mu.Unlock()
+		if refuse {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		c, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer c.Close()
+		s.mu.Lock()
+		s.conns = append(s.conns, c)
+		s.mu.Unlock()
+		c.SetPingHandler(func(data string) error {
+			s.mu.Lock()
+			s.pings++
+			s.mu.Unlock()
+			return c.WriteControl(websocket.PongMessage, []byte(data), time.Now().Add(time.Second))
+		})
+		subscribed := map[string]bool{}
+		for {
+			req := new(streamRequest)
+			err := c.ReadJSON(req)
+			if err != nil {
+				return
+			}
+			switch req.Method {
+			case "SUBSCRIBE":
+				if len(req.Params) > 0 && req.Params[0] == "invalid" {
+					c.WriteMessage(websocket.TextMessage, []byte(fmt.Sprintf(`{"error":{"code":2,"msg":"Invalid request"},"id":%d}`, req.ID)))
+					continue
+				}
+				c.WriteMessage(websocket.TextMessage, []byte(fmt.Sprintf(`{"result":null,"id":%d}`, req.ID)))
+				for _, stream := range req.Params {
+					subscribed[stream] = true
+					s.mu.Lock()
+					s.subscribed[stream] = true
+					s.mu.Unlock()
+					c.WriteMessage(websocket.TextMessage, []byte(fmt.Sprintf(`{"stream":%q,"data":{}}`, stream)))
+				}
+			case "UNSUBSCRIBE":
+				s.mu.Lock()
+				for _, stream := range req.Params {
+					delete(subscribed, stream)
+					delete(s.subscribed, stream)
+				}
+				s.mu.Unlock()
+				c.WriteMessage(websocket.TextMessage, []byte(fmt.Sprintf(`{"result":null,"id":%d}`, req.ID)))
+			case "LIST_SUBSCRIPTIONS":
+				streams := []string{}
+				for stream := range subscribed {
+					streams = append(streams, fmt.Sprintf("%q", stream))
+				}
+				c.WriteMessage(websocket.TextMessage, []byte(fmt.Sprintf(`{"result":[%s],"id":%d}`, strings.Join(streams, ","), req.ID)))
+			}
+		}
+	}))
+	s.errs = make(chan error, 10)
+	endpoint := "ws" + strings.TrimPrefix(s.server.URL, "http") + "/stream"
+	s.client = NewStreamClient(endpoint, StreamTransport{Dial: testDial, Serve: testServe}, func(err error) {
+		select {
+		case s.errs <- err:
+		default:
+		}
+	})
+	s.client.MaxMessagesPerSecond = 100
+}
+
+func (s *streamClientTestSuite) TearDownTest() {
+	s.client.Close()
+	s.server.Close()
+}
+
+func testDial(endpoint string) (*websocket.Conn, error) {
+	c, _, err := websocket.DefaultDialer.Dial(endpoint, nil)
+	return c, err
+}
+
+// testServe read c and redial it once every time it drops
+func testServe(endpoint string, c *websocket.Conn, hooks *StreamConnHooks, stopC chan struct{}) error {
+	for {
+		err := testReadConn(c, hooks.Message, stopC)
+		if err == nil {
+			return nil
+		}
+		hooks.Disconnected(err)
+		c, err = testDial(endpoint)
+		if err != nil {
+			return err
+		}
+		hooks.Reconnected(c)
+	}
+}
+
+func testReadConn(c *websocket.Conn, handler func(message []byte), stopC chan struct{}) error {
+	connDoneC := make(chan struct{})
+	defer close(connDoneC)
+	go func() {
+		select {
+		case <-stopC:
+		case <-connDoneC:
+		}
+		c.Close()
+	}()
+	for {
+		_, message, err := c.ReadMessage()
+		if err != nil {
+			select {
+			case <-stopC:
+				return nil
+			default:
+				return err
+			}
+		}
+		handler(message)
+	}
+}
+
+func (s *streamClientTestSuite) serverConns() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.conns)
+}
+
+func (s *streamClientTestSuite) clientConns() int {
+	s.client.mu.RLock()
+	defer s.client.mu.RUnlock()
+	return len(s.client.conns)
+}
+
+func (s *streamClientTestSuite) TestSubscribeShardsConnections() {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	s.client.MaxStreamsPerConnection = 2
+	streams := []string{"a@trade", "b@trade", "c@trade", "d@trade", "e@trade"}
+	err := s.client.Subscribe(ctx, streams, func(data []byte) {})
+	s.Require().NoError(err)
+	s.Equal(3, s.serverConns())
+
+	listed, err := s.client.ListSubscriptions(ctx)
+	s.Require().NoError(err)
+	sort.Strings(listed)
+	s.Equal(streams, listed)
+
+	err = s.client.Unsubscribe(ctx, []string{"e@trade", "a@trade"})
+	s.Require().NoError(err)
+	listed, err = s.client.ListSubscriptions(ctx)
+	s.Require().NoError(err)
+	sort.Strings(listed)
+	s.Equal([]string{"b@trade", "c@trade", "d@trade"}, listed)
+	s.Equal(2, s.clientConns())
+}
+
+func (s *streamClientTestSuite) TestConcurrentSubscribe() {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	s.client.MaxStreamsPerConnection = 1
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			err := s.client.Subscribe(ctx, []string{fmt.Sprintf("%d@trade", i)}, func(data []byte) {})
+			s.NoError(err)
+		}(i)
+	}
+	wg.Wait()
+	// The connections dialed concurrently are all kept or closed
+	s.Equal(5, s.clientConns())
+	s.Len(s.client.Subscriptions(), 5)
+}
+
+func (s *streamClientTestSuite) TestMessageRateLimit() {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	s.client.MaxMessagesPerSecond = 10
+	start := time.Now()
+	for _, stream := range []string{"a@trade", "b@trade", "c@trade", "d@trade"} {
+		err := s.client.Subscribe(ctx, []string{stream}, func(data []byte) {})
+		s.Require().NoError(err)
+	}
+	// 4 messages on one connection at 10 per second
+	s.GreaterOrEqual(time.Since(start), 300*time.Millisecond)
+}
+
+func (s *streamClientTestSuite) TestPingRateLimit() {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	s.client.MaxMessagesPerSecond = 2
+	s.client.Keepalive = time.Hour
+	start := time.Now()
+	err := s.client.Subscribe(ctx, []string{"a@trade"}, func(data []byte) {})
+	s.Require().NoError(err)
+	// The ping sent on connection takes the first slot
+	s.GreaterOrEqual(time.Since(start), 500*time.Millisecond)
+	s.Eventually(func() bool {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		return s.pings == 1
+	}, time.Second, time.Millisecond)
+}
+
+func (s *streamClientTestSuite) TestKeepalive() {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	s.client.Keepalive = 20 * time.Millisecond
+	err := s.client.Subscribe(ctx, []string{"a@trade"}, func(data []byte) {})
+	s.Require().NoError(err)
+	s.Eventually(func() bool {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		return s.pings > 2
+	}, time.Second, time.Millisecond)
+	s.Equal(1, s.serverConns())
+}
+
+func (s *streamClientTestSuite) TestSubscribeError() {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	err := s.client.Subscribe(ctx, []string{"invalid"}, func(data []byte) {})
+	s.Require().Error(err)
+	s.Empty(s.client.Subscriptions())
+
+	// The shards accepted before the failure are unsubscribed and the
+	// connections opened for the subscription are closed
+	s.client.MaxStreamsPerConnection = 1
+	err = s.client.Subscribe(ctx, []string{"a@trade", "invalid"}, func(data []byte) {})
+	s.Require().Error(err)
+	s.Empty(s.client.Subscriptions())
+	s.Equal(0, s.clientConns())
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.Empty(s.subscribed)
+}
+
+func (s *streamClientTestSuite) TestResubscribeAfterReconnect() {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	events := make(chan []byte, 10)
+	err := s.client.Subscribe(ctx, []string{"btcusdt@aggTrade"}, func(data []byte) {
+		events <- data
+	})
+	s.Require().NoError(err)
+	<-events
+
+	s.mu.Lock()
+	s.conns[0].Close()
+	s.mu.Unlock()
+	select {
+	case <-events:
+	case <-time.After(5 * time.Second):
+		s.FailNow("timed out waiting for resubscription")
+	}
+	s.Equal(2, s.serverConns())
+}
+
+func (s *streamClientTestSuite) TestDroppedConnection() {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	err := s.client.Subscribe(ctx, []string{"a@trade", "b@trade"}, func(data []byte) {})
+	s.Require().NoError(err)
+
+	s.mu.Lock()
+	s.refuse = true
+	s.conns[0].Close()
+	s.mu.Unlock()
+	select {
+	case err := <-s.errs:
+		dropped := new(StreamsDroppedError)
+		s.Require().True(errors.As(err, &dropped))
+		sort.Strings(dropped.Streams)
+		s.Equal([]string{"a@trade", "b@trade"}, dropped.Streams)
+		s.True(errors.Is(err, websocket.ErrBadHandshake))
+	case <-time.After(5 * time.Second):
+		s.FailNow("timed out waiting for the dropped streams")
+	}
+	s.Equal(0, s.clientConns())
+	s.Empty(s.client.Subscriptions())
+}
@@ -0,0 +1,110 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeServe serve the events sent on eventC until stopC is closed or
+// eventC is closed, in which case err is reported first
+type fakeServe struct {
+	eventC chan int
+	err    error
+}
+
+func (f *fakeServe) serve(handler func(int), errHandler func(error)) (doneC, stopC chan struct{}, err error) {
+	doneC = make(chan struct{})
+	stopC = make(chan struct{})
+	go func() {
+		defer close(doneC)
+		for {
+			select {
+			case <-stopC:
+				return
+			case event, ok := <-f.eventC:
+				if !ok {
+					errHandler(f.err)
+					return
+				}
+				handler(event)
+			}
+		}
+	}()
+	return doneC, stopC, nil
+}
+
+func collect(t *testing.T, s *Stream[int]) []int {
+	var events []int
+	timeout := time.After(time.Second)
+	for {
+		select {
+		case event, ok := <-s.Events():
+			if !ok {
+				return events
+			}
+			events = append(events, event)
+		case <-timeout:
+			require.FailNow(t, "stream not closed")
+		}
+	}
+}
+
+func TestStreamOverflow(t *testing.T) {
+	for _, c := range []struct {
+		policy  OverflowPolicy
+		events  []int
+		dropped uint64
+	}{
+		{OverflowDropNewest, []int{0, 1}, 3},
+		{OverflowDropOldest, []int{3, 4}, 3},
+	} {
+		f := &fakeServe{eventC: make(chan int), err: errors.New("closed")}
+		s, err := NewStream[int](context.Background(), f.serve, WithBuffer(2), WithOverflow(c.policy))
+		require.NoError(t, err)
+		for i := 0; i < 5; i++ {
+			f.eventC <- i
+		}
+		close(f.eventC)
+		<-s.Done()
+		assert.Equal(t, c.events, collect(t, s))
+		assert.Equal(t, c.dropped, s.Dropped())
+		assert.EqualError(t, s.Err(), "closed")
+	}
+}
+
+func TestStreamBlock(t *testing.T) {
+	assert := assert.New(t)
+	f := &fakeServe{eventC: make(chan int)}
+	var errs []error
+	ctx, cancel := context.WithCancel(context.Background())
+	s, err := NewStream[int](ctx, f.serve, WithBuffer(0), WithErrHandler(func(err error) {
+		errs = append(errs, err)
+	}))
+	require.NoError(t, err)
+	go func() {
+		for i := 0; i < 3; i++ {
+			f.eventC <- i
+		}
+	}()
+	for i := 0; i < 3; i++ {
+		assert.Equal(i, <-s.Events())
+	}
+	assert.Nil(s.Err())
+	cancel()
+	assert.Empty(collect(t, s))
+	assert.Equal(context.Canceled, s.Err())
+	assert.Zero(s.Dropped())
+	assert.Empty(errs)
+}
+
+func TestStreamServeError(t *testing.T) {
+	_, err := NewStream[int](context.Background(), func(handler func(int), errHandler func(error)) (doneC, stopC chan struct{}, err error) {
+		return nil, nil, ErrUnknown
+	})
+	assert.Equal(t, ErrUnknown, err)
+}
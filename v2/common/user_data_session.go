@@ -0,0 +1,217 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrUserDataStreamClosed is reported when the user data stream connection
+// drops, the session then reconnects with a fresh listen key
+var ErrUserDataStreamClosed = errors.New("user data stream closed")
+
+// ErrListenKeyExpired is reported when the server expired the listen key,
+// the session then reconnects with a fresh listen key
+var ErrListenKeyExpired = errors.New("listen key expired")
+
+// ErrUserDataSessionStarted is returned by Start when the session was
+// already started
+var ErrUserDataSessionStarted = errors.New("user data session already started")
+
+// ListenKeyFuncs create, extend and delete the listen key of an account type
+type ListenKeyFuncs struct {
+	Start     func(ctx context.Context) (listenKey string, err error)
+	Keepalive func(ctx context.Context, listenKey string) error
+	Close     func(ctx context.Context, listenKey string) error
+}
+
+// UserDataServeFunc serve the user data stream of listenKey without
+// reconnecting, expired is called when the server reports the key expired
+type UserDataServeFunc func(listenKey string, expired func()) (doneC, stopC chan struct{}, err error)
+
+// UserDataSession keep a user data stream running: it obtains a listen key,
+// extends it every KeepaliveInterval, and reconnects with a fresh key when
+// the key expires or the connection drops. Events may be missed between two
+// connections, so OnResync is called after every connection, including the
+// first one, to tell consumers to fetch the account and open orders again.
+type UserDataSession struct {
+	// KeepaliveInterval between two keepalive requests, keys expire after 60 minutes
+	KeepaliveInterval time.Duration
+	// MinBackoff is the delay before retrying a failed connection
+	MinBackoff time.Duration
+	// MaxBackoff caps the exponentially growing delay between retries
+	MaxBackoff time.Duration
+	// OnResync is called once connected, events before it may be lost
+	OnResync func()
+	// OnError is called for every failure the session recovers from
+	OnError func(err error)
+
+	keys  ListenKeyFuncs
+	serve UserDataServeFunc
+
+	mu        sync.Mutex
+	listenKey string
+	started   bool
+	doneC     chan struct{}
+	doneOnce  sync.Once
+}
+
+// NewUserDataSession init a user data session, see Start
+func NewUserDataSession(keys ListenKeyFuncs, serve UserDataServeFunc) *UserDataSession {
+	return &UserDataSession{
+		KeepaliveInterval: 30 * time.Minute,
+		MinBackoff:        time.Second,
+		MaxBackoff:        time.Minute,
+		keys:              keys,
+		serve:             serve,
+		doneC:             make(chan struct{}),
+	}
+}
+
+// ListenKey return the listen key of the current connection
+func (s *UserDataSession) ListenKey() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.listenKey
+}
+
+// Done is closed once the session stopped after ctx was done and the listen
+// key was closed, or once Start failed
+func (s *UserDataSession) Done() <-chan struct{} {
+	return s.doneC
+}
+
+func (s *UserDataSession) done() {
+	s.doneOnce.Do(func() { close(s.doneC) })
+}
+
+// userDataConn is a running stream and the signal of its key expiry
+type userDataConn struct {
+	listenKey string
+	doneC     chan struct{}
+	stopC     chan struct{}
+	expiredC  chan struct{}
+}
+
+func (c *userDataConn) stop() {
+	close(c.stopC)
+	<-c.doneC
+}
+
+// Start connect the stream then keep it running in the background until
+// ctx is done. The error of the first connection is returned as is, the
+// session is then done. A session can only be started once.
+func (s *UserDataSession) Start(ctx context.Context) error {
+	s.mu.Lock()
+	started := s.started
+	s.started = true
+	s.mu.Unlock()
+	if started {
+		return ErrUserDataSessionStarted
+	}
+	conn, err := s.connect(ctx)
+	if err != nil {
+		s.done()
+		return err
+	}
+	go s.run(ctx, conn)
+	return nil
+}
+
+func (s *UserDataSession) connect(ctx context.Context) (*userDataConn, error) {
+	listenKey, err := s.keys.Start(ctx)
+	if err != nil {
+		return nil, err
+	}
+	expiredC := make(chan struct{})
+	var once sync.Once
+	expired := func() {
+		once.Do(func() { close(expiredC) })
+	}
+	doneC, stopC, err := s.serve(listenKey, expired)
+	if err != nil {
+		// The key would otherwise live until the server expires it
+		s.closeKey(listenKey)
+		return nil, err
+	}
+	s.mu.Lock()
+	s.listenKey = listenKey
+	s.mu.Unlock()
+	if s.OnResync != nil {
+		s.OnResync()
+	}
+	return &userDataConn{listenKey: listenKey, doneC: doneC, stopC: stopC, expiredC: expiredC}, nil
+}
+
+func (s *UserDataSession) run(ctx context.Context, conn *userDataConn) {
+	defer s.done()
+	for {
+		err := s.serveConn(ctx, conn)
+		if err == nil {
+			s.closeKey(conn.listenKey)
+			return
+		}
+		s.error(err)
+		conn = nil
+		for attempts := 1; conn == nil; attempts++ {
+			if !errors.Is(err, ErrListenKeyExpired) || attempts > 1 {
+				select {
+				case <-ctx.Done():
+					return
+				case <-time.After(Backoff(attempts, s.MinBackoff, s.MaxBackoff)):
+				}
+			}
+			conn, err = s.connect(ctx)
+			if err != nil {
+				if ctx.Err() != nil {
+					return
+				}
+				s.error(err)
+			}
+		}
+	}
+}
+
+// serveConn extend the key of conn until ctx is done, returning nil, or until
+// the connection must be replaced, returning the reason
+func (s *UserDataSession) serveConn(ctx context.Context, conn *userDataConn) error {
+	ticker := time.NewTicker(s.KeepaliveInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			conn.stop()
+			return nil
+		case <-conn.doneC:
+			return ErrUserDataStreamClosed
+		case <-conn.expiredC:
+			conn.stop()
+			return ErrListenKeyExpired
+		case <-ticker.C:
+			err := s.keys.Keepalive(ctx, conn.listenKey)
+			if err == nil || ctx.Err() != nil {
+				continue
+			}
+			if apiErr, ok := AsAPIError(err); ok && apiErr.Code == CodeInvalidListenKey {
+				conn.stop()
+				return err
+			}
+			// The key lives 60 minutes, the next keepalive may succeed
+			s.error(err)
+		}
+	}
+}
+
+// closeKey close listenKey with a fresh context since ctx may be done
+func (s *UserDataSession) closeKey(listenKey string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	s.keys.Close(ctx, listenKey)
+}
+
+func (s *UserDataSession) error(err error) {
+	if s.OnError != nil {
+		s.OnError(err)
+	}
+}
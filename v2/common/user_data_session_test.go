@@ -0,0 +1,194 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type userDataSessionTestSuite struct {
+	suite.Suite
+	mu           sync.Mutex
+	started      int
+	keepalives   []string
+	closed       []string
+	keepaliveErr error
+	conns        []*fakeUserDataConn
+	resyncs      int
+	errs         []error
+	session      *UserDataSession
+}
+
+type fakeUserDataConn struct {
+	listenKey string
+	expired   func()
+	doneC     chan struct{}
+	stopC     chan struct{}
+}
+
+func TestUserDataSession(t *testing.T) {
+	suite.Run(t, new(userDataSessionTestSuite))
+}
+
+func (s *userDataSessionTestSuite) SetupTest() {
+	s.started = 0
+	s.keepalives = nil
+	s.closed = nil
+	s.keepaliveErr = nil
+	s.conns = nil
+	s.resyncs = 0
+	s.errs = nil
+	s.session = NewUserDataSession(ListenKeyFuncs{
+		Start: func(ctx context.Context) (string, error) {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			s.started++
+			return fmt.Sprintf("key%d", s.started), nil
+		},
+		Keepalive: func(ctx context.Context, listenKey string) error {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			s.keepalives = append(s.keepalives, listenKey)
+			return s.keepaliveErr
+		},
+		Close: func(ctx context.Context, listenKey string) error {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			s.closed = append(s.closed, listenKey)
+			return nil
+		},
+	}, func(listenKey string, expired func()) (doneC, stopC chan struct{}, err error) {
+		conn := &fakeUserDataConn{listenKey: listenKey, expired: expired, doneC: make(chan struct{}), stopC: make(chan struct{})}
+		go func() {
+			<-conn.stopC
+			close(conn.doneC)
+		}()
+		s.mu.Lock()
+		s.conns = append(s.conns, conn)
+		s.mu.Unlock()
+		return conn.doneC, conn.stopC, nil
+	})
+	s.session.KeepaliveInterval = 20 * time.Millisecond
+	s.session.MinBackoff = time.Millisecond
+	s.session.MaxBackoff = time.Millisecond
+	s.session.OnResync = func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.resyncs++
+	}
+	s.session.OnError = func(err error) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.errs = append(s.errs, err)
+	}
+}
+
+func (s *userDataSessionTestSuite) conn(i int) *fakeUserDataConn {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.conns[i]
+}
+
+func (s *userDataSessionTestSuite) waitConns(n int) {
+	s.Eventually(func() bool {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		return len(s.conns) == n
+	}, time.Second, time.Millisecond)
+}
+
+func (s *userDataSessionTestSuite) TestLifecycle() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	s.Require().NoError(s.session.Start(ctx))
+	s.Equal("key1", s.session.ListenKey())
+	s.Eventually(func() bool {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		return len(s.keepalives) > 0
+	}, time.Second, time.Millisecond)
+
+	// The key expiry reconnects with a new key
+	s.conn(0).expired()
+	s.waitConns(2)
+	s.Equal("key2", s.conn(1).listenKey)
+	s.Eventually(func() bool { return s.session.ListenKey() == "key2" }, time.Second, time.Millisecond)
+
+	// So does a dropped connection
+	close(s.conn(1).stopC)
+	s.waitConns(3)
+
+	// And an unknown key on keepalive
+	s.mu.Lock()
+	s.keepaliveErr = &APIError{Code: CodeInvalidListenKey}
+	s.mu.Unlock()
+	s.waitConns(4)
+	s.mu.Lock()
+	s.keepaliveErr = nil
+	s.mu.Unlock()
+
+	cancel()
+	select {
+	case <-s.session.Done():
+	case <-time.After(time.Second):
+		s.FailNow("session not done")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.Equal(4, s.resyncs)
+	s.Equal([]string{"key4"}, s.closed)
+	s.True(errors.Is(s.errs[0], ErrListenKeyExpired))
+	s.True(errors.Is(s.errs[1], ErrUserDataStreamClosed))
+	s.True(errors.Is(s.errs[2], &APIError{Code: CodeInvalidListenKey}))
+	for _, conn := range s.conns {
+		select {
+		case <-conn.doneC:
+		default:
+			s.Fail("connection not stopped", conn.listenKey)
+		}
+	}
+}
+
+func (s *userDataSessionTestSuite) TestStartError() {
+	s.session.keys.Start = func(ctx context.Context) (string, error) {
+		return "", ErrRejectedAPIKey
+	}
+	err := s.session.Start(context.Background())
+	s.True(errors.Is(err, ErrRejectedAPIKey))
+	s.Equal(0, s.resyncs)
+	select {
+	case <-s.session.Done():
+	default:
+		s.Fail("session not done after a failed start")
+	}
+}
+
+func (s *userDataSessionTestSuite) TestDialError() {
+	s.session.serve = func(listenKey string, expired func()) (doneC, stopC chan struct{}, err error) {
+		return nil, nil, ErrUserDataStreamClosed
+	}
+	err := s.session.Start(context.Background())
+	s.True(errors.Is(err, ErrUserDataStreamClosed))
+	select {
+	case <-s.session.Done():
+	default:
+		s.Fail("session not done after a failed dial")
+	}
+	// The key obtained before the dial is not leaked
+	s.Equal([]string{"key1"}, s.closed)
+}
+
+func (s *userDataSessionTestSuite) TestStartTwice() {
+	ctx, cancel := context.WithCancel(context.Background())
+	s.Require().NoError(s.session.Start(ctx))
+	s.Equal(ErrUserDataSessionStarted, s.session.Start(ctx))
+	s.waitConns(1)
+	cancel()
+	<-s.session.Done()
+	s.Equal([]string{"key1"}, s.closed)
+}
@@ -3,12 +3,11 @@ package delivery
 import (
 	"bytes"
 	"context"
-	"crypto/hmac"
-	"crypto/sha256"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"log"
+	"log/slog"
 	"net/http"
 	"net/url"
 	"os"
@@ -187,12 +186,13 @@ func getApiEndpoint() string {
 // Services will be created by the form client.NewXXXService().
 func NewClient(apiKey, secretKey string) *Client {
 	return &Client{
-		APIKey:     apiKey,
-		SecretKey:  secretKey,
-		BaseURL:    getApiEndpoint(),
-		UserAgent:  "Binance/golang",
-		HTTPClient: http.DefaultClient,
-		Logger:     log.New(os.Stderr, "Binance-golang ", log.LstdFlags),
+		APIKey:      apiKey,
+		SecretKey:   secretKey,
+		BaseURL:     getApiEndpoint(),
+		UserAgent:   "Binance/golang",
+		HTTPClient:  http.DefaultClient,
+		Logger:      log.New(os.Stderr, "Binance-golang ", log.LstdFlags),
+		RateLimiter: common.NewRateLimiter(),
 	}
 }
 
@@ -208,13 +208,51 @@ type Client struct {
 	Debug      bool
 	Logger     *log.Logger
 	TimeOffset int64
-	do         doFunc
-}
-
+	// Signer signs the requests, HMAC-SHA256 with SecretKey when nil
+	Signer common.Signer
+	// ClockSync, when set, replaces TimeOffset once synced and signed
+	// requests failing with -1021 are retried once after a resync
+	ClockSync *common.ClockSync
+	// RateLimiter throttles requests to the /dapi endpoints, it is seeded by
+	// ExchangeInfoService and can be set to nil to disable throttling
+	RateLimiter *common.RateLimiter
+	// Middlewares wrap the sending of every request, the first one is the
+	// outermost, see common.RetryMiddleware and common.LoggingMiddleware
+	Middlewares []common.Middleware
+	// LogHandler receives a record of every request, with secrets redacted
+	LogHandler slog.Handler
+	// Metrics receives the latency, status and used weight of every request
+	Metrics common.Metrics
+	do      doFunc
+}
+
+// debug log a line when Debug is set, with the signatures of the URLs and
+// bodies and the listen keys of the responses redacted
 func (c *Client) debug(format string, v ...interface{}) {
 	if c.Debug {
-		c.Logger.Printf(format, v...)
+		c.Logger.Print(common.RedactString(fmt.Sprintf(format, v...)))
+	}
+}
+
+// logger return the structured logger of the client, discarding records
+// when LogHandler is nil
+func (c *Client) logger() *slog.Logger {
+	return common.NewLogger(c.LogHandler)
+}
+
+func (c *Client) signer() common.Signer {
+	if c.Signer != nil {
+		return c.Signer
+	}
+	return common.NewHMACSigner(c.SecretKey)
+}
+
+// timeOffset return the offset measured by ClockSync once synced and TimeOffset otherwise
+func (c *Client) timeOffset() int64 {
+	if c.ClockSync != nil && c.ClockSync.Synced() {
+		return c.ClockSync.Offset()
 	}
+	return c.TimeOffset
 }
 
 func (c *Client) parseRequest(r *request, opts ...RequestOption) (err error) {
@@ -232,7 +270,7 @@ func (c *Client) parseRequest(r *request, opts ...RequestOption) (err error) {
 		r.setParam(recvWindowKey, r.recvWindow)
 	}
 	if r.secType == secTypeSigned {
-		r.setParam(timestampKey, currentTimestamp()-c.TimeOffset)
+		r.setParam(timestampKey, currentTimestamp()-c.timeOffset())
 	}
 	queryString := r.query.Encode()
 	body := &bytes.Buffer{}
@@ -251,13 +289,12 @@ func (c *Client) parseRequest(r *request, opts ...RequestOption) (err error) {
 
 	if r.secType == secTypeSigned {
 		raw := fmt.Sprintf("%s%s", queryString, bodyString)
-		mac := hmac.New(sha256.New, []byte(c.SecretKey))
-		_, err = mac.Write([]byte(raw))
+		signature, err := c.signer().Sign([]byte(raw))
 		if err != nil {
 			return err
 		}
 		v := url.Values{}
-		v.Set(signatureKey, fmt.Sprintf("%x", (mac.Sum(nil))))
+		v.Set(signatureKey, signature)
 		if queryString == "" {
 			queryString = v.Encode()
 		} else {
@@ -267,7 +304,7 @@ func (c *Client) parseRequest(r *request, opts ...RequestOption) (err error) {
 	if queryString != "" {
 		fullURL = fmt.Sprintf("%s?%s", fullURL, queryString)
 	}
-	c.debug("full url: %s, body: %s", fullURL, bodyString)
+	c.debug("full url: %s, body: %s", common.RedactString(fullURL), common.RedactString(bodyString))
 
 	r.fullURL = fullURL
 	r.header = header
@@ -276,25 +313,56 @@ func (c *Client) parseRequest(r *request, opts ...RequestOption) (err error) {
 }
 
 func (c *Client) callAPI(ctx context.Context, r *request, opts ...RequestOption) (data []byte, err error) {
+	data, err = c.send(ctx, r, opts...)
+	// Retry once with a fresh offset when the clock drifted out of the
+	// recvWindow, the options are already applied to r
+	if err != nil && r.secType == secTypeSigned && c.ClockSync != nil && common.IsInvalidTimestamp(err) {
+		if _, serr := c.ClockSync.Resync(ctx); serr == nil {
+			data, err = c.send(ctx, r)
+		}
+	}
+	return data, err
+}
+
+func (c *Client) send(ctx context.Context, r *request, opts ...RequestOption) (data []byte, err error) {
 	err = c.parseRequest(r, opts...)
 	if err != nil {
 		return []byte{}, err
 	}
+	weight, orders, limited := requestWeight(r)
+	limited = limited && c.RateLimiter != nil
+	if limited {
+		err = c.RateLimiter.Wait(ctx, weight, orders)
+		if err != nil {
+			return []byte{}, err
+		}
+	}
+	start, status := time.Now(), 0
+	var resHeader http.Header
+	defer func() {
+		latency := time.Since(start)
+		common.LogRequest(ctx, c.logger(), r.method, r.endpoint, weight, status, latency, err)
+		common.RecordRequest(c.Metrics, r.method, r.endpoint, status, resHeader, latency, err)
+	}()
 	req, err := http.NewRequest(r.method, r.fullURL, r.body)
 	if err != nil {
 		return []byte{}, err
 	}
 	req = req.WithContext(ctx)
 	req.Header = r.header
-	c.debug("request: %#v", req)
+	c.debug("request: %s %s", req.Method, common.RedactString(req.URL.String()))
 	f := c.do
 	if f == nil {
 		f = c.HTTPClient.Do
 	}
-	res, err := f(req)
+	res, err := common.Chain(common.Doer(f), c.Middlewares...)(req)
 	if err != nil {
 		return []byte{}, err
 	}
+	status, resHeader = res.StatusCode, res.Header
+	if limited {
+		c.RateLimiter.Update(res.StatusCode, res.Header)
+	}
 	data, err = ioutil.ReadAll(res.Body)
 	if err != nil {
 		return []byte{}, err
@@ -308,7 +376,7 @@ func (c *Client) callAPI(ctx context.Context, r *request, opts ...RequestOption)
 		}
 	}()
 	c.debug("response: %#v", res)
-	c.debug("response body: %s", string(data))
+	c.debug("response body: %s", common.RedactString(string(data)))
 	c.debug("response status code: %d", res.StatusCode)
 
 	if res.StatusCode >= http.StatusBadRequest {
@@ -317,6 +385,10 @@ func (c *Client) callAPI(ctx context.Context, r *request, opts ...RequestOption)
 		if e != nil {
 			c.debug("failed to unmarshal json: %s", e)
 		}
+		apiErr.StatusCode = res.StatusCode
+		apiErr.Method = r.method
+		apiErr.Endpoint = r.endpoint
+		apiErr.Weight = weight
 		return nil, apiErr
 	}
 	return data, nil
@@ -337,6 +409,11 @@ func (c *Client) NewSetServerTimeService() *SetServerTimeService {
 	return &SetServerTimeService{c: c}
 }
 
+// NewDepthService init depth service
+func (c *Client) NewDepthService() *DepthService {
+	return &DepthService{c: c}
+}
+
 // NewKlinesService init klines service
 func (c *Client) NewKlinesService() *KlinesService {
 	return &KlinesService{c: c}
@@ -397,6 +474,11 @@ func (c *Client) NewCancelAllOpenOrdersService() *CancelAllOpenOrdersService {
 	return &CancelAllOpenOrdersService{c: c}
 }
 
+// NewCountdownCancelAllService init countdown cancel all service
+func (c *Client) NewCountdownCancelAllService() *CountdownCancelAllService {
+	return &CountdownCancelAllService{c: c}
+}
+
 // NewListOpenOrdersService init list open orders service
 func (c *Client) NewListOpenOrdersService() *ListOpenOrdersService {
 	return &ListOpenOrdersService{c: c}
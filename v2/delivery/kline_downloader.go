@@ -0,0 +1,31 @@
+package delivery
+
+import (
+	"context"
+
+	"github.com/vv1zard/go-binance/v2/common"
+)
+
+// NewKlineDownloader init a downloader of the COIN-M futures klines caching
+// them under dir, an empty dir disables the cache. Up to 1000 klines are
+// requested at once, which costs less weight than the 1500 maximum.
+func (c *Client) NewKlineDownloader(dir string) *common.KlineDownloader {
+	d := &common.KlineDownloader{
+		Market: "delivery",
+		Limit:  1000,
+		Fetch: func(ctx context.Context, symbol, interval string, startTime, endTime int64, limit int) ([]*common.Kline, error) {
+			klines, err := c.NewKlinesService().Symbol(symbol).Interval(interval).
+				StartTime(startTime).EndTime(endTime).Limit(limit).Do(ctx)
+			res := make([]*common.Kline, len(klines))
+			for i, kline := range klines {
+				k := common.Kline(*kline)
+				res[i] = &k
+			}
+			return res, err
+		},
+	}
+	if dir != "" {
+		d.Cache = common.NewKlineCache(dir)
+	}
+	return d
+}
@@ -0,0 +1,92 @@
+package delivery
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type orderBookTestSuite struct {
+	baseTestSuite
+	origWsServe func(*WsConfig, WsHandler, ErrHandler) (chan struct{}, chan struct{}, error)
+	wsHandler   WsHandler
+	updates     chan struct{}
+	errs        chan error
+}
+
+func TestOrderBook(t *testing.T) {
+	suite.Run(t, new(orderBookTestSuite))
+}
+
+func (s *orderBookTestSuite) SetupTest() {
+	s.baseTestSuite.SetupTest()
+	s.origWsServe = wsServe
+	wsServe = func(cfg *WsConfig, handler WsHandler, errHandler ErrHandler) (doneC, stopC chan struct{}, err error) {
+		s.wsHandler = handler
+		doneC = make(chan struct{})
+		stopC = make(chan struct{})
+		go func() {
+			<-stopC
+			close(doneC)
+		}()
+		return doneC, stopC, nil
+	}
+	s.updates = make(chan struct{}, 10)
+	s.errs = make(chan error, 10)
+}
+
+func (s *orderBookTestSuite) TearDownTest() {
+	wsServe = s.origWsServe
+}
+
+func (s *orderBookTestSuite) serve() (*OrderBook, chan struct{}) {
+	updates, errs := s.updates, s.errs
+	book := s.client.NewOrderBook("BTCUSD_PERP")
+	book.OnUpdate = func(book *OrderBook) {
+		updates <- struct{}{}
+	}
+	_, stopC, err := book.Serve(func(err error) {
+		select {
+		case errs <- err:
+		default:
+		}
+	})
+	s.r().NoError(err)
+	return book, stopC
+}
+
+func (s *orderBookTestSuite) waitUpdate() {
+	select {
+	case <-s.updates:
+	case <-time.After(time.Second):
+		s.FailNow("timed out waiting for book update")
+	}
+}
+
+func (s *orderBookTestSuite) TestSync() {
+	data := []byte(`{
+		"lastUpdateId": 160,
+		"bids": [["9638.0", "10"], ["9637.9", "5"]],
+		"asks": [["9638.2", "100"]]
+	}`)
+	s.mockDo(data, nil)
+	defer s.assertDo()
+	book, stopC := s.serve()
+	defer close(stopC)
+
+	// Stale event dropped, second one covers the snapshot.
+	s.wsHandler([]byte(`{"e":"depthUpdate","s":"BTCUSD_PERP","U":150,"u":155,"pu":149,"b":[["9638.0","1"]],"a":[]}`))
+	s.wsHandler([]byte(`{"e":"depthUpdate","s":"BTCUSD_PERP","U":157,"u":161,"pu":155,"b":[["9638.1","2"]],"a":[]}`))
+	s.waitUpdate()
+	s.r().True(book.Synced())
+	s.r().Equal(int64(161), book.LastUpdateID())
+	bid, _ := book.BestBid()
+	s.r().Equal(Bid{Price: "9638.1", Quantity: "2"}, bid)
+
+	s.wsHandler([]byte(`{"e":"depthUpdate","s":"BTCUSD_PERP","U":162,"u":165,"pu":161,"b":[["9638.1","0"]],"a":[["9638.2","0"],["9638.3","3"]]}`))
+	s.waitUpdate()
+	bids, asks := book.Depth(5)
+	s.r().Equal([]Bid{{Price: "9638.0", Quantity: "10"}, {Price: "9637.9", Quantity: "5"}}, bids)
+	s.r().Equal([]Ask{{Price: "9638.3", Quantity: "3"}}, asks)
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"encoding/json"
 	"net/http"
+
+	"github.com/vv1zard/go-binance/v2/common"
 )
 
 // CreateOrderService create order
@@ -343,6 +345,36 @@ type Order struct {
 	PriceProtect     bool             `json:"priceProtect"`
 }
 
+// PriceDecimal parse Price as an exact decimal
+func (o *Order) PriceDecimal() (common.Decimal, error) {
+	return common.ParseDecimal(o.Price)
+}
+
+// OrigQuantityDecimal parse OrigQuantity as an exact decimal
+func (o *Order) OrigQuantityDecimal() (common.Decimal, error) {
+	return common.ParseDecimal(o.OrigQuantity)
+}
+
+// ExecutedQuantityDecimal parse ExecutedQuantity as an exact decimal
+func (o *Order) ExecutedQuantityDecimal() (common.Decimal, error) {
+	return common.ParseDecimal(o.ExecutedQuantity)
+}
+
+// CumBaseDecimal parse CumBase as an exact decimal
+func (o *Order) CumBaseDecimal() (common.Decimal, error) {
+	return common.ParseDecimal(o.CumBase)
+}
+
+// StopPriceDecimal parse StopPrice as an exact decimal
+func (o *Order) StopPriceDecimal() (common.Decimal, error) {
+	return common.ParseDecimal(o.StopPrice)
+}
+
+// AvgPriceDecimal parse AvgPrice as an exact decimal
+func (o *Order) AvgPriceDecimal() (common.Decimal, error) {
+	return common.ParseDecimal(o.AvgPrice)
+}
+
 // ListOrdersService all account orders; active, canceled, or filled
 type ListOrdersService struct {
 	c         *Client
@@ -534,6 +566,55 @@ func (s *CancelAllOpenOrdersService) Do(ctx context.Context, opts ...RequestOpti
 	return nil
 }
 
+// CountdownCancelAllService arm a countdown cancelling all the open orders
+// of a symbol once it ends, unless armed again before
+type CountdownCancelAllService struct {
+	c             *Client
+	symbol        string
+	countdownTime int64
+}
+
+// Symbol set symbol
+func (s *CountdownCancelAllService) Symbol(symbol string) *CountdownCancelAllService {
+	s.symbol = symbol
+	return s
+}
+
+// CountdownTime set countdownTime in milliseconds, 0 cancels the countdown
+func (s *CountdownCancelAllService) CountdownTime(countdownTime int64) *CountdownCancelAllService {
+	s.countdownTime = countdownTime
+	return s
+}
+
+// Do send request
+func (s *CountdownCancelAllService) Do(ctx context.Context, opts ...RequestOption) (res *CountdownCancelAllResponse, err error) {
+	r := &request{
+		method:   http.MethodPost,
+		endpoint: "/dapi/v1/countdownCancelAll",
+		secType:  secTypeSigned,
+	}
+	r.setFormParams(params{
+		"symbol":        s.symbol,
+		"countdownTime": s.countdownTime,
+	})
+	data, err := s.c.callAPI(ctx, r, opts...)
+	if err != nil {
+		return nil, err
+	}
+	res = new(CountdownCancelAllResponse)
+	err = json.Unmarshal(data, res)
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// CountdownCancelAllResponse define countdown cancel all response
+type CountdownCancelAllResponse struct {
+	Symbol        string `json:"symbol"`
+	CountdownTime string `json:"countdownTime"`
+}
+
 // ListLiquidationOrdersService list liquidation orders
 type ListLiquidationOrdersService struct {
 	c         *Client
@@ -529,6 +529,28 @@ func (s *orderServiceTestSuite) TestCancelAllOpenOrders() {
 	s.r().NoError(err)
 }
 
+func (s *orderServiceTestSuite) TestCountdownCancelAll() {
+	data := []byte(`{
+		"symbol": "BTCUSDT",
+		"countdownTime": "100000"
+	}`)
+	s.mockDo(data, nil)
+	defer s.assertDo()
+
+	s.assertReq(func(r *request) {
+		e := newSignedRequest().setFormParams(params{
+			"symbol":        "BTCUSDT",
+			"countdownTime": 100000,
+		})
+		s.assertRequestEqual(e, r)
+	})
+
+	res, err := s.client.NewCountdownCancelAllService().Symbol("BTCUSDT").
+		CountdownTime(100000).Do(newContext())
+	s.r().NoError(err)
+	s.r().Equal(&CountdownCancelAllResponse{Symbol: "BTCUSDT", CountdownTime: "100000"}, res)
+}
+
 func (s *orderServiceTestSuite) TestListLiquidationOrders() {
 	data := []byte(`[
 		{
@@ -0,0 +1,97 @@
+package delivery
+
+import (
+	"fmt"
+
+	"github.com/vv1zard/go-binance/v2/common"
+)
+
+// OrderValidator check orders against the filters of their symbol before
+// they are sent, so orders which would be rejected by a filter do not cost
+// request weight. Failures are returned as *common.FilterError.
+//
+// PERCENT_PRICE depends on the mark price and is left to the server.
+type OrderValidator struct {
+	// Round snap prices and quantities off their tick or step size instead of
+	// rejecting the order. Quantities are rounded down, prices are rounded
+	// down for buys and up for sells.
+	Round bool
+
+	symbols map[string]*Symbol
+}
+
+// NewOrderValidator init an order validator with the symbols of a cached
+// exchange info
+func NewOrderValidator(info *ExchangeInfo) *OrderValidator {
+	symbols := make(map[string]*Symbol, len(info.Symbols))
+	for i := range info.Symbols {
+		symbols[info.Symbols[i].Symbol] = &info.Symbols[i]
+	}
+	return &OrderValidator{symbols: symbols}
+}
+
+// ValidateOrder validate an order, rounding its prices and quantity in place
+// when Round is set
+func (v *OrderValidator) ValidateOrder(s *CreateOrderService) error {
+	symbol, ok := v.symbols[s.symbol]
+	if !ok {
+		return fmt.Errorf("%w: %s", common.ErrSymbolNotFound, s.symbol)
+	}
+	var quantity *string
+	if s.quantity != "" {
+		quantity = &s.quantity
+	}
+	priceMode := common.RoundDown
+	if s.side == SideTypeSell {
+		priceMode = common.RoundUp
+	}
+	if f := symbol.PriceFilter(); f != nil {
+		filter := common.StepFilter{Filter: string(SymbolFilterTypePrice), Min: f.MinPrice, Max: f.MaxPrice, Step: f.TickSize}
+		if err := v.apply(filter, s.symbol, "price", s.price, priceMode); err != nil {
+			return err
+		}
+		if err := v.apply(filter, s.symbol, "stopPrice", s.stopPrice, priceMode); err != nil {
+			return err
+		}
+		if err := v.apply(filter, s.symbol, "activationPrice", s.activationPrice, priceMode); err != nil {
+			return err
+		}
+	}
+	if isMarketOrder(s.orderType) {
+		if f := symbol.MarketLotSizeFilter(); f != nil {
+			filter := common.StepFilter{Filter: string(SymbolFilterTypeMarketLotSize), Min: f.MinQuantity, Max: f.MaxQuantity, Step: f.StepSize}
+			if err := v.apply(filter, s.symbol, "quantity", quantity, common.RoundDown); err != nil {
+				return err
+			}
+		}
+	} else if f := symbol.LotSizeFilter(); f != nil {
+		filter := common.StepFilter{Filter: string(SymbolFilterTypeLotSize), Min: f.MinQuantity, Max: f.MaxQuantity, Step: f.StepSize}
+		if err := v.apply(filter, s.symbol, "quantity", quantity, common.RoundDown); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// apply check a parameter against filter, writing the rounded value back
+func (v *OrderValidator) apply(filter common.StepFilter, symbol, param string, value *string, mode common.RoundingMode) error {
+	if value == nil {
+		return nil
+	}
+	res, err := filter.Apply(symbol, param, *value, v.Round, mode)
+	if err != nil {
+		return err
+	}
+	*value = res
+	return nil
+}
+
+// isMarketOrder tell whether orders of type t are filled at market price
+// and checked against MARKET_LOT_SIZE instead of LOT_SIZE
+func isMarketOrder(t OrderType) bool {
+	switch t {
+	case OrderTypeMarket, OrderTypeStopMarket, OrderTypeTakeProfitMarket, OrderTypeTrailingStopMarket:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,71 @@
+package delivery
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/vv1zard/go-binance/v2/common"
+)
+
+type orderValidatorTestSuite struct {
+	baseTestSuite
+	validator *OrderValidator
+}
+
+func TestOrderValidator(t *testing.T) {
+	suite.Run(t, new(orderValidatorTestSuite))
+}
+
+func (s *orderValidatorTestSuite) SetupTest() {
+	s.baseTestSuite.SetupTest()
+	s.validator = NewOrderValidator(&ExchangeInfo{
+		Symbols: []Symbol{
+			{
+				Symbol: "BTCUSD_PERP",
+				Filters: []map[string]interface{}{
+					{"filterType": "PRICE_FILTER", "minPrice": "556.80", "maxPrice": "4529764", "tickSize": "0.10"},
+					{"filterType": "LOT_SIZE", "minQty": "1", "maxQty": "1000000", "stepSize": "1"},
+					{"filterType": "MARKET_LOT_SIZE", "minQty": "1", "maxQty": "60000", "stepSize": "1"},
+				},
+			},
+		},
+	})
+}
+
+func (s *orderValidatorTestSuite) assertFilterError(err error, filter SymbolFilterType, param string) {
+	r := s.r()
+	var filterErr *common.FilterError
+	r.True(errors.As(err, &filterErr), "unexpected error %v", err)
+	r.Equal(string(filter), filterErr.Filter)
+	r.Equal(param, filterErr.Param)
+}
+
+func (s *orderValidatorTestSuite) newOrder() *CreateOrderService {
+	return s.client.NewCreateOrderService().Symbol("BTCUSD_PERP").Side(SideTypeBuy).
+		Type(OrderTypeLimit).TimeInForce(TimeInForceTypeGTC)
+}
+
+func (s *orderValidatorTestSuite) TestValidate() {
+	s.r().NoError(s.validator.ValidateOrder(s.newOrder().Price("43187.1").Quantity("3")))
+
+	err := s.validator.ValidateOrder(s.newOrder().Price("43187.15").Quantity("3"))
+	s.assertFilterError(err, SymbolFilterTypePrice, "price")
+
+	err = s.validator.ValidateOrder(s.newOrder().Type(OrderTypeStopMarket).StopPrice("43187.15").Quantity("3"))
+	s.assertFilterError(err, SymbolFilterTypePrice, "stopPrice")
+
+	err = s.validator.ValidateOrder(s.newOrder().Type(OrderTypeMarket).Quantity("60001"))
+	s.assertFilterError(err, SymbolFilterTypeMarketLotSize, "quantity")
+
+	// A close position order has no quantity
+	s.r().NoError(s.validator.ValidateOrder(s.newOrder().Type(OrderTypeStopMarket).StopPrice("43187.1").ClosePosition(true)))
+}
+
+func (s *orderValidatorTestSuite) TestRound() {
+	s.validator.Round = true
+	order := s.newOrder().Side(SideTypeSell).Price("43187.11").Quantity("3.9")
+	s.r().NoError(s.validator.ValidateOrder(order))
+	s.r().Equal("43187.2", *order.price)
+	s.r().Equal("3", order.quantity)
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"encoding/json"
 	"net/http"
+
+	"github.com/vv1zard/go-binance/v2/common"
 )
 
 // GetPositionRiskService get account balance
@@ -65,3 +67,28 @@ type PositionRisk struct {
 	IsAutoAddMargin  string `json:"isAutoAddMargin"`
 	PositionSide     string `json:"positionSide"`
 }
+
+// EntryPriceDecimal parse EntryPrice as an exact decimal
+func (p *PositionRisk) EntryPriceDecimal() (common.Decimal, error) {
+	return common.ParseDecimal(p.EntryPrice)
+}
+
+// MarkPriceDecimal parse MarkPrice as an exact decimal
+func (p *PositionRisk) MarkPriceDecimal() (common.Decimal, error) {
+	return common.ParseDecimal(p.MarkPrice)
+}
+
+// PositionAmtDecimal parse PositionAmt as an exact decimal
+func (p *PositionRisk) PositionAmtDecimal() (common.Decimal, error) {
+	return common.ParseDecimal(p.PositionAmt)
+}
+
+// UnRealizedProfitDecimal parse UnRealizedProfit as an exact decimal
+func (p *PositionRisk) UnRealizedProfitDecimal() (common.Decimal, error) {
+	return common.ParseDecimal(p.UnRealizedProfit)
+}
+
+// LiquidationPriceDecimal parse LiquidationPrice as an exact decimal
+func (p *PositionRisk) LiquidationPriceDecimal() (common.Decimal, error) {
+	return common.ParseDecimal(p.LiquidationPrice)
+}
@@ -0,0 +1,104 @@
+package delivery
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+)
+
+// requestWeights is the weight of each /fapi endpoint, endpoints missing
+// from the table weigh 1. Weights depending on the parameters are computed
+// by requestWeight.
+var requestWeights = map[string]int64{
+	"GET /dapi/v1/trades":              5,
+	"GET /dapi/v1/historicalTrades":    20,
+	"GET /dapi/v1/aggTrades":           20,
+	"POST /dapi/v1/batchOrders":        5,
+	"GET /dapi/v1/allOrders":           20,
+	"GET /dapi/v1/forceOrders":         20,
+	"GET /dapi/v1/allForceOrders":      20,
+	"GET /dapi/v1/account":             5,
+	"GET /dapi/v1/userTrades":          20,
+	"GET /dapi/v1/income":              20,
+	"GET /dapi/v1/commissionRate":      20,
+	"POST /dapi/v1/countdownCancelAll": 10,
+	"GET /dapi/v1/positionSide/dual":   30,
+}
+
+// orderCounts is the number of orders placed by each endpoint
+var orderCounts = map[string]int64{
+	"POST /dapi/v1/order": 1,
+	"PUT /dapi/v1/order":  1,
+}
+
+// requestWeight return the weight and order count of r, tracked is false for
+// endpoints outside of the /fapi rate limits such as /futures/data
+func requestWeight(r *request) (weight, orders int64, tracked bool) {
+	if !strings.HasPrefix(r.endpoint, "/dapi/") {
+		return 0, 0, false
+	}
+	key := r.method + " " + r.endpoint
+	orders = orderCounts[key]
+	switch key {
+	case "GET /dapi/v1/depth":
+		return depthWeight(r.query.Get("limit")), orders, true
+	case "GET /dapi/v1/klines", "GET /dapi/v1/continuousKlines", "GET /dapi/v1/indexPriceKlines", "GET /dapi/v1/markPriceKlines":
+		return klinesWeight(r.query.Get("limit")), orders, true
+	case "GET /dapi/v1/ticker/24hr", "GET /dapi/v1/openOrders":
+		return symbolWeight(r, 1, 40), orders, true
+	case "GET /dapi/v1/ticker/price":
+		return symbolWeight(r, 1, 2), orders, true
+	case "GET /dapi/v1/ticker/bookTicker":
+		return symbolWeight(r, 2, 5), orders, true
+	case "POST /dapi/v1/batchOrders":
+		var batch []json.RawMessage
+		if json.Unmarshal([]byte(r.form.Get("batchOrders")), &batch) == nil {
+			orders = int64(len(batch))
+		}
+	}
+	weight, ok := requestWeights[key]
+	if !ok {
+		weight = 1
+	}
+	return weight, orders, true
+}
+
+func depthWeight(limit string) int64 {
+	n, err := strconv.Atoi(limit)
+	if err != nil {
+		n = 500
+	}
+	switch {
+	case n <= 50:
+		return 2
+	case n <= 100:
+		return 5
+	case n <= 500:
+		return 10
+	}
+	return 20
+}
+
+func klinesWeight(limit string) int64 {
+	n, err := strconv.Atoi(limit)
+	if err != nil {
+		n = 500
+	}
+	switch {
+	case n < 100:
+		return 1
+	case n < 500:
+		return 2
+	case n <= 1000:
+		return 5
+	}
+	return 10
+}
+
+// symbolWeight return single when the request is for one symbol and all otherwise
+func symbolWeight(r *request, single, all int64) int64 {
+	if r.query.Get("symbol") != "" {
+		return single
+	}
+	return all
+}
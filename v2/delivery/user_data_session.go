@@ -0,0 +1,40 @@
+package delivery
+
+import (
+	"context"
+
+	"github.com/vv1zard/go-binance/v2/common"
+)
+
+// NewUserDataSession init a managed session of the COIN-M futures user data stream,
+// errHandler also receives the errors the session recovers from. Call Start
+// on the returned session to connect.
+func (c *Client) NewUserDataSession(handler WsUserDataHandler, errHandler ErrHandler) *common.UserDataSession {
+	return newUserDataSession(common.ListenKeyFuncs{
+		Start: func(ctx context.Context) (string, error) {
+			return c.NewStartUserStreamService().Do(ctx)
+		},
+		Keepalive: func(ctx context.Context, listenKey string) error {
+			return c.NewKeepaliveUserStreamService().ListenKey(listenKey).Do(ctx)
+		},
+		Close: func(ctx context.Context, listenKey string) error {
+			return c.NewCloseUserStreamService().ListenKey(listenKey).Do(ctx)
+		},
+	}, handler, errHandler)
+}
+
+// newUserDataSession serve the stream without reconnection, the session
+// reconnects itself with a fresh listen key
+func newUserDataSession(keys common.ListenKeyFuncs, handler WsUserDataHandler, errHandler ErrHandler) *common.UserDataSession {
+	session := common.NewUserDataSession(keys, func(listenKey string, expired func()) (doneC, stopC chan struct{}, err error) {
+		cfg := &WsConfig{Endpoint: getUserDataEndpoint(listenKey), LogHandler: WebsocketLogHandler, Metrics: WebsocketMetrics}
+		return wsUserDataServe(cfg, func(event *WsUserDataEvent) {
+			if event.Event == UserDataEventTypeListenKeyExpired {
+				expired()
+			}
+			handler(event)
+		}, errHandler)
+	})
+	session.OnError = errHandler
+	return session
+}
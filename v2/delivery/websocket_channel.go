@@ -0,0 +1,155 @@
+package delivery
+
+import (
+	"context"
+	"time"
+
+	"github.com/vv1zard/go-binance/v2/common"
+)
+
+// WsAggTradeStream is WsAggTradeServe delivering events on a channel until ctx is done
+func WsAggTradeStream(ctx context.Context, symbol string, opts ...common.StreamOption) (*common.Stream[*WsAggTradeEvent], error) {
+	return common.NewStream[*WsAggTradeEvent](ctx, func(handler func(*WsAggTradeEvent), errHandler func(error)) (doneC, stopC chan struct{}, err error) {
+		return WsAggTradeServe(symbol, handler, errHandler)
+	}, opts...)
+}
+
+// WsIndexPriceStream is WsIndexPriceServe delivering events on a channel until ctx is done
+func WsIndexPriceStream(ctx context.Context, symbol string, opts ...common.StreamOption) (*common.Stream[*WsIndexPriceEvent], error) {
+	return common.NewStream[*WsIndexPriceEvent](ctx, func(handler func(*WsIndexPriceEvent), errHandler func(error)) (doneC, stopC chan struct{}, err error) {
+		return WsIndexPriceServe(symbol, handler, errHandler)
+	}, opts...)
+}
+
+// WsMarkPriceStream is WsMarkPriceServe delivering events on a channel until ctx is done
+func WsMarkPriceStream(ctx context.Context, symbol string, opts ...common.StreamOption) (*common.Stream[*WsMarkPriceEvent], error) {
+	return common.NewStream[*WsMarkPriceEvent](ctx, func(handler func(*WsMarkPriceEvent), errHandler func(error)) (doneC, stopC chan struct{}, err error) {
+		return WsMarkPriceServe(symbol, handler, errHandler)
+	}, opts...)
+}
+
+// WsPairMarkPriceStream is WsPairMarkPriceServe delivering events on a channel until ctx is done
+func WsPairMarkPriceStream(ctx context.Context, opts ...common.StreamOption) (*common.Stream[WsPairMarkPriceEvent], error) {
+	return common.NewStream[WsPairMarkPriceEvent](ctx, func(handler func(WsPairMarkPriceEvent), errHandler func(error)) (doneC, stopC chan struct{}, err error) {
+		return WsPairMarkPriceServe(handler, errHandler)
+	}, opts...)
+}
+
+// WsKlineStream is WsKlineServe delivering events on a channel until ctx is done
+func WsKlineStream(ctx context.Context, symbol string, interval string, opts ...common.StreamOption) (*common.Stream[*WsKlineEvent], error) {
+	return common.NewStream[*WsKlineEvent](ctx, func(handler func(*WsKlineEvent), errHandler func(error)) (doneC, stopC chan struct{}, err error) {
+		return WsKlineServe(symbol, interval, handler, errHandler)
+	}, opts...)
+}
+
+// WsContinuousKlineStream is WsContinuousKlineServe delivering events on a channel until ctx is done
+func WsContinuousKlineStream(ctx context.Context, pair string, contractType string, interval string, opts ...common.StreamOption) (*common.Stream[*WsContinuousKlineEvent], error) {
+	return common.NewStream[*WsContinuousKlineEvent](ctx, func(handler func(*WsContinuousKlineEvent), errHandler func(error)) (doneC, stopC chan struct{}, err error) {
+		return WsContinuousKlineServe(pair, contractType, interval, handler, errHandler)
+	}, opts...)
+}
+
+// WsIndexPriceKlineStream is WsIndexPriceKlineServe delivering events on a channel until ctx is done
+func WsIndexPriceKlineStream(ctx context.Context, pair string, interval string, opts ...common.StreamOption) (*common.Stream[*WsIndexPriceKlineEvent], error) {
+	return common.NewStream[*WsIndexPriceKlineEvent](ctx, func(handler func(*WsIndexPriceKlineEvent), errHandler func(error)) (doneC, stopC chan struct{}, err error) {
+		return WsIndexPriceKlineServe(pair, interval, handler, errHandler)
+	}, opts...)
+}
+
+// WsMarkPriceKlineStream is WsMarkPriceKlineServe delivering events on a channel until ctx is done
+func WsMarkPriceKlineStream(ctx context.Context, symbol string, interval string, opts ...common.StreamOption) (*common.Stream[*WsMarkPriceKlineEvent], error) {
+	return common.NewStream[*WsMarkPriceKlineEvent](ctx, func(handler func(*WsMarkPriceKlineEvent), errHandler func(error)) (doneC, stopC chan struct{}, err error) {
+		return WsMarkPriceKlineServe(symbol, interval, handler, errHandler)
+	}, opts...)
+}
+
+// WsMiniMarketTickerStream is WsMiniMarketTickerServe delivering events on a channel until ctx is done
+func WsMiniMarketTickerStream(ctx context.Context, symbol string, opts ...common.StreamOption) (*common.Stream[*WsMiniMarketTickerEvent], error) {
+	return common.NewStream[*WsMiniMarketTickerEvent](ctx, func(handler func(*WsMiniMarketTickerEvent), errHandler func(error)) (doneC, stopC chan struct{}, err error) {
+		return WsMiniMarketTickerServe(symbol, handler, errHandler)
+	}, opts...)
+}
+
+// WsAllMiniMarketTickerStream is WsAllMiniMarketTickerServe delivering events on a channel until ctx is done
+func WsAllMiniMarketTickerStream(ctx context.Context, opts ...common.StreamOption) (*common.Stream[WsAllMiniMarketTickerEvent], error) {
+	return common.NewStream[WsAllMiniMarketTickerEvent](ctx, func(handler func(WsAllMiniMarketTickerEvent), errHandler func(error)) (doneC, stopC chan struct{}, err error) {
+		return WsAllMiniMarketTickerServe(handler, errHandler)
+	}, opts...)
+}
+
+// WsMarketTickerStream is WsMarketTickerServe delivering events on a channel until ctx is done
+func WsMarketTickerStream(ctx context.Context, symbol string, opts ...common.StreamOption) (*common.Stream[*WsMarketTickerEvent], error) {
+	return common.NewStream[*WsMarketTickerEvent](ctx, func(handler func(*WsMarketTickerEvent), errHandler func(error)) (doneC, stopC chan struct{}, err error) {
+		return WsMarketTickerServe(symbol, handler, errHandler)
+	}, opts...)
+}
+
+// WsAllMarketTickerStream is WsAllMarketTickerServe delivering events on a channel until ctx is done
+func WsAllMarketTickerStream(ctx context.Context, opts ...common.StreamOption) (*common.Stream[WsAllMarketTickerEvent], error) {
+	return common.NewStream[WsAllMarketTickerEvent](ctx, func(handler func(WsAllMarketTickerEvent), errHandler func(error)) (doneC, stopC chan struct{}, err error) {
+		return WsAllMarketTickerServe(handler, errHandler)
+	}, opts...)
+}
+
+// WsBookTickerStream is WsBookTickerServe delivering events on a channel until ctx is done
+func WsBookTickerStream(ctx context.Context, symbol string, opts ...common.StreamOption) (*common.Stream[*WsBookTickerEvent], error) {
+	return common.NewStream[*WsBookTickerEvent](ctx, func(handler func(*WsBookTickerEvent), errHandler func(error)) (doneC, stopC chan struct{}, err error) {
+		return WsBookTickerServe(symbol, handler, errHandler)
+	}, opts...)
+}
+
+// WsAllBookTickerStream is WsAllBookTickerServe delivering events on a channel until ctx is done
+func WsAllBookTickerStream(ctx context.Context, opts ...common.StreamOption) (*common.Stream[*WsBookTickerEvent], error) {
+	return common.NewStream[*WsBookTickerEvent](ctx, func(handler func(*WsBookTickerEvent), errHandler func(error)) (doneC, stopC chan struct{}, err error) {
+		return WsAllBookTickerServe(handler, errHandler)
+	}, opts...)
+}
+
+// WsLiquidationOrderStream is WsLiquidationOrderServe delivering events on a channel until ctx is done
+func WsLiquidationOrderStream(ctx context.Context, symbol string, opts ...common.StreamOption) (*common.Stream[*WsLiquidationOrderEvent], error) {
+	return common.NewStream[*WsLiquidationOrderEvent](ctx, func(handler func(*WsLiquidationOrderEvent), errHandler func(error)) (doneC, stopC chan struct{}, err error) {
+		return WsLiquidationOrderServe(symbol, handler, errHandler)
+	}, opts...)
+}
+
+// WsAllLiquidationOrderStream is WsAllLiquidationOrderServe delivering events on a channel until ctx is done
+func WsAllLiquidationOrderStream(ctx context.Context, opts ...common.StreamOption) (*common.Stream[*WsLiquidationOrderEvent], error) {
+	return common.NewStream[*WsLiquidationOrderEvent](ctx, func(handler func(*WsLiquidationOrderEvent), errHandler func(error)) (doneC, stopC chan struct{}, err error) {
+		return WsAllLiquidationOrderServe(handler, errHandler)
+	}, opts...)
+}
+
+// WsPartialDepthStream is WsPartialDepthServe delivering events on a channel until ctx is done
+func WsPartialDepthStream(ctx context.Context, symbol string, levels int, opts ...common.StreamOption) (*common.Stream[*WsDepthEvent], error) {
+	return common.NewStream[*WsDepthEvent](ctx, func(handler func(*WsDepthEvent), errHandler func(error)) (doneC, stopC chan struct{}, err error) {
+		return WsPartialDepthServe(symbol, levels, handler, errHandler)
+	}, opts...)
+}
+
+// WsPartialDepthStreamWithRate is WsPartialDepthServeWithRate delivering events on a channel until ctx is done
+func WsPartialDepthStreamWithRate(ctx context.Context, symbol string, levels int, rate *time.Duration, opts ...common.StreamOption) (*common.Stream[*WsDepthEvent], error) {
+	return common.NewStream[*WsDepthEvent](ctx, func(handler func(*WsDepthEvent), errHandler func(error)) (doneC, stopC chan struct{}, err error) {
+		return WsPartialDepthServeWithRate(symbol, levels, rate, handler, errHandler)
+	}, opts...)
+}
+
+// WsDiffDepthStream is WsDiffDepthServe delivering events on a channel until ctx is done
+func WsDiffDepthStream(ctx context.Context, symbol string, opts ...common.StreamOption) (*common.Stream[*WsDepthEvent], error) {
+	return common.NewStream[*WsDepthEvent](ctx, func(handler func(*WsDepthEvent), errHandler func(error)) (doneC, stopC chan struct{}, err error) {
+		return WsDiffDepthServe(symbol, handler, errHandler)
+	}, opts...)
+}
+
+// WsDiffDepthStreamWithRate is WsDiffDepthServeWithRate delivering events on a channel until ctx is done
+func WsDiffDepthStreamWithRate(ctx context.Context, symbol string, rate *time.Duration, opts ...common.StreamOption) (*common.Stream[*WsDepthEvent], error) {
+	return common.NewStream[*WsDepthEvent](ctx, func(handler func(*WsDepthEvent), errHandler func(error)) (doneC, stopC chan struct{}, err error) {
+		return WsDiffDepthServeWithRate(symbol, rate, handler, errHandler)
+	}, opts...)
+}
+
+// WsUserDataStream is WsUserDataServe delivering events on a channel until ctx is done
+func WsUserDataStream(ctx context.Context, listenKey string, opts ...common.StreamOption) (*common.Stream[*WsUserDataEvent], error) {
+	return common.NewStream[*WsUserDataEvent](ctx, func(handler func(*WsUserDataEvent), errHandler func(error)) (doneC, stopC chan struct{}, err error) {
+		return WsUserDataServe(listenKey, handler, errHandler)
+	}, opts...)
+}
@@ -3,6 +3,9 @@ package binance
 import (
 	"context"
 	"net/http"
+	"time"
+
+	"github.com/vv1zard/go-binance/v2/common"
 )
 
 // ListDepositsService fetches deposit history.
@@ -103,6 +106,36 @@ func (s *ListDepositsService) Do(ctx context.Context) (res []*Deposit, err error
 	return res, nil
 }
 
+// Iterate walk the deposits from StartTime up to EndTime in windows of 90 days
+// with as many requests as needed, Limit sets the page size. The deposits are
+// returned oldest first.
+func (s *ListDepositsService) Iterate() *common.Iterator[*Deposit] {
+	p := common.Pager[*Deposit]{
+		Fetch: func(ctx context.Context, q common.PageQuery) ([]*Deposit, error) {
+			page := &ListDepositsService{c: s.c, coin: s.coin, status: s.status, txId: s.txId,
+				startTime: &q.StartTime, endTime: &q.EndTime, offset: &q.Offset, limit: &q.Limit}
+			return page.Do(ctx)
+		},
+		Limit:     1000,
+		MaxWindow: 90 * 24 * time.Hour,
+		Time:      func(deposit *Deposit) int64 { return deposit.InsertTime },
+		Key: func(deposit *Deposit) interface{} {
+			return [3]string{deposit.TxID, deposit.Coin, deposit.Amount}
+		},
+		Offset: true,
+	}
+	if s.limit != nil {
+		p.Limit = *s.limit
+	}
+	if s.startTime != nil {
+		p.StartTime = *s.startTime
+	}
+	if s.endTime != nil {
+		p.EndTime = *s.endTime
+	}
+	return p.Iterator()
+}
+
 // Deposit represents a single deposit entry.
 type Deposit struct {
 	Amount        string `json:"amount"`
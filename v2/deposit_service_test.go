@@ -1,6 +1,10 @@
 package binance
 
 import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/suite"
@@ -97,6 +101,49 @@ func (s *depositServiceTestSuite) TestListDeposits() {
 	}, deposits[1])
 }
 
+func (s *depositServiceTestSuite) TestListDepositsIterate() {
+	day := int64(24 * 60 * 60 * 1000)
+	start := int64(1599620082000)
+	// One deposit every 30 days, the endpoint returns the newest first
+	var queries []string
+	s.client.Client.do = func(req *http.Request) (*http.Response, error) {
+		query := req.URL.Query()
+		queries = append(queries, fmt.Sprintf("coin=%s offset=%s limit=%s", query.Get("coin"), query.Get("offset"), query.Get("limit")))
+		startTime, _ := strconv.ParseInt(query.Get("startTime"), 10, 64)
+		endTime, _ := strconv.ParseInt(query.Get("endTime"), 10, 64)
+		offset, _ := strconv.Atoi(query.Get("offset"))
+		s.r().True(endTime-startTime < 90*day)
+		var deposits []string
+		for i := 6; i >= 0; i-- {
+			insertTime := start + int64(i)*30*day
+			if insertTime >= startTime && insertTime <= endTime {
+				deposits = append(deposits, fmt.Sprintf(`{"coin": "BTC", "txId": "tx%d", "insertTime": %d}`, i, insertTime))
+			}
+		}
+		deposits = deposits[offset:]
+		if len(deposits) > 2 {
+			deposits = deposits[:2]
+		}
+		return newHTTPResponse([]byte("["+strings.Join(deposits, ",")+"]"), http.StatusOK), nil
+	}
+	deposits, err := s.client.NewListDepositsService().Coin("BTC").Limit(2).
+		StartTime(start).EndTime(start + 180*day).Iterate().All(newContext())
+	r := s.r()
+	r.NoError(err)
+	var txIDs []string
+	for _, deposit := range deposits {
+		txIDs = append(txIDs, deposit.TxID)
+	}
+	r.Equal([]string{"tx0", "tx1", "tx2", "tx3", "tx4", "tx5", "tx6"}, txIDs)
+	r.Equal([]string{
+		"coin=BTC offset=0 limit=2",
+		"coin=BTC offset=2 limit=2",
+		"coin=BTC offset=0 limit=2",
+		"coin=BTC offset=2 limit=2",
+		"coin=BTC offset=0 limit=2",
+	}, queries)
+}
+
 func (s *depositServiceTestSuite) assertDepositEqual(e, a *Deposit) {
 	r := s.r()
 	r.Equal(e.Amount, a.Amount, "Amount")
@@ -4,6 +4,8 @@ import (
 	"context"
 	"net/http"
 	"strings"
+
+	"github.com/vv1zard/go-binance/v2/common"
 )
 
 // ExchangeInfoService exchange info service
@@ -53,7 +55,9 @@ func (s *ExchangeInfoService) Do(ctx context.Context, opts ...RequestOption) (re
 	if err != nil {
 		return nil, err
 	}
-
+	if s.c.RateLimiter != nil {
+		s.c.RateLimiter.SetLimits(res.limits()...)
+	}
 	return res, nil
 }
 
@@ -64,6 +68,25 @@ type ExchangeInfo struct {
 	RateLimits      []RateLimit   `json:"rateLimits"`
 	ExchangeFilters []interface{} `json:"exchangeFilters"`
 	Symbols         []Symbol      `json:"symbols"`
+	Sors            []SOR         `json:"sors"`
+}
+
+// SOR define the symbols of a base asset the Smart Order Routing routes
+// across
+type SOR struct {
+	BaseAsset string   `json:"baseAsset"`
+	Symbols   []string `json:"symbols"`
+}
+
+func (e *ExchangeInfo) limits() []common.RateLimit {
+	limits := make([]common.RateLimit, 0, len(e.RateLimits))
+	for _, rl := range e.RateLimits {
+		limit, ok := common.ParseRateLimit(rl.RateLimitType, rl.Interval, rl.IntervalNum, rl.Limit)
+		if ok {
+			limits = append(limits, limit)
+		}
+	}
+	return limits
 }
 
 // RateLimit struct
@@ -2,8 +2,10 @@ package binance
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/suite"
+	"github.com/vv1zard/go-binance/v2/common"
 )
 
 type exchangeInfoServiceTestSuite struct {
@@ -54,6 +56,9 @@ func (s *exchangeInfoServiceTestSuite) TestExchangeInfo() {
 				"isMarginTradingAllowed": false,
 				"filters":[{"filterType":"PRICE_FILTER","minPrice":"0.00000100","maxPrice":"100000.00000000","tickSize":"0.00000100"},{"filterType":"LOT_SIZE","minQty":"0.00100000","maxQty":"100000.00000000","stepSize":"0.00100000"},{"filterType":"MIN_NOTIONAL","minNotional":"0.00100000"},{"filterType": "MAX_NUM_ALGO_ORDERS", "maxNumAlgoOrders": 5}]
 			}
+		],
+		"sors": [
+			{"baseAsset": "BTC", "symbols": ["BTCUSDT", "BTCUSDC"]}
 		]
 	}`)
 	s.mockDo(data, nil)
@@ -99,8 +104,17 @@ func (s *exchangeInfoServiceTestSuite) TestExchangeInfo() {
 				},
 			},
 		},
+		Sors: []SOR{
+			{BaseAsset: "BTC", Symbols: []string{"BTCUSDT", "BTCUSDC"}},
+		},
 	}
 	s.assertExchangeInfoEqual(ei, res)
+	s.r().Equal(ei.Sors, res.Sors)
+	s.r().ElementsMatch([]common.RateLimit{
+		{Type: common.RateLimitRequestWeight, Interval: time.Minute, Limit: 1200},
+		{Type: common.RateLimitOrders, Interval: 10 * time.Second, Limit: 10},
+		{Type: common.RateLimitOrders, Interval: 24 * time.Hour, Limit: 100000},
+	}, s.client.RateLimiter.Limits())
 
 	eLotSizeFilter := &LotSizeFilter{
 		MaxQuantity: "100000.00000000",
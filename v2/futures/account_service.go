@@ -4,6 +4,8 @@ import (
 	"context"
 	"encoding/json"
 	"net/http"
+
+	"github.com/vv1zard/go-binance/v2/common"
 )
 
 // GetBalanceService get account balance
@@ -13,12 +15,7 @@ type GetBalanceService struct {
 
 // Do send request
 func (s *GetBalanceService) Do(ctx context.Context, opts ...RequestOption) (res []*Balance, err error) {
-	r := &request{
-		method:   http.MethodGet,
-		endpoint: "/fapi/v2/balance",
-		secType:  secTypeSigned,
-	}
-	data, _, err := s.c.callAPI(ctx, r, opts...)
+	data, _, err := s.c.callAPI(ctx, s.buildRequest(), opts...)
 	if err != nil {
 		return []*Balance{}, err
 	}
@@ -30,6 +27,15 @@ func (s *GetBalanceService) Do(ctx context.Context, opts ...RequestOption) (res
 	return res, nil
 }
 
+func (s *GetBalanceService) buildRequest() *request {
+	r := &request{
+		method:   http.MethodGet,
+		endpoint: "/fapi/v2/balance",
+		secType:  secTypeSigned,
+	}
+	return r
+}
+
 // Balance define user balance of your account
 type Balance struct {
 	AccountAlias       string `json:"accountAlias"`
@@ -41,6 +47,26 @@ type Balance struct {
 	MaxWithdrawAmount  string `json:"maxWithdrawAmount"`
 }
 
+// BalanceDecimal parse Balance as an exact decimal
+func (b *Balance) BalanceDecimal() (common.Decimal, error) {
+	return common.ParseDecimal(b.Balance)
+}
+
+// CrossWalletBalanceDecimal parse CrossWalletBalance as an exact decimal
+func (b *Balance) CrossWalletBalanceDecimal() (common.Decimal, error) {
+	return common.ParseDecimal(b.CrossWalletBalance)
+}
+
+// CrossUnPnlDecimal parse CrossUnPnl as an exact decimal
+func (b *Balance) CrossUnPnlDecimal() (common.Decimal, error) {
+	return common.ParseDecimal(b.CrossUnPnl)
+}
+
+// AvailableBalanceDecimal parse AvailableBalance as an exact decimal
+func (b *Balance) AvailableBalanceDecimal() (common.Decimal, error) {
+	return common.ParseDecimal(b.AvailableBalance)
+}
+
 // GetAccountService get account info
 type GetAccountService struct {
 	c *Client
@@ -0,0 +1,417 @@
+package futures
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/vv1zard/go-binance/v2/common"
+)
+
+// AccountState keeps the balances, positions and open orders of the futures
+// account in sync with the user data stream. Events are buffered while the
+// account and the open orders are fetched, events older than the snapshot
+// are dropped, and a new snapshot is fetched whenever an event shows that
+// previous ones were missed, such as a trade filling more than the last
+// known quantity.
+type AccountState struct {
+	// OnUpdate is called after every snapshot or event applied to the state
+	OnUpdate func(state *AccountState)
+
+	c          *Client
+	ctx        context.Context
+	errHandler ErrHandler
+	mu         sync.RWMutex
+	synced     bool
+	syncing    bool
+	// outdated is set when a snapshot being fetched may miss events
+	outdated  bool
+	buffer    []*WsUserDataEvent
+	balances  map[string]*stateBalance
+	positions map[positionKey]*statePosition
+	orders    map[int64]*Order
+}
+
+// stateBalance is a balance and the time of its last update
+type stateBalance struct {
+	WsBalance
+	updateTime int64
+}
+
+// statePosition is a position and the time of its last update
+type statePosition struct {
+	WsPosition
+	updateTime int64
+}
+
+type positionKey struct {
+	symbol string
+	side   PositionSideType
+}
+
+// NewAccountState init an account state, call Serve to start syncing it
+func (c *Client) NewAccountState() *AccountState {
+	return &AccountState{
+		c:         c,
+		ctx:       context.Background(),
+		balances:  make(map[string]*stateBalance),
+		positions: make(map[positionKey]*statePosition),
+		orders:    make(map[int64]*Order),
+	}
+}
+
+// Serve start a user data session feeding the state until ctx is done. A
+// snapshot is fetched after every connection of the session since events
+// may be lost in between.
+func (s *AccountState) Serve(ctx context.Context, errHandler ErrHandler) (*common.UserDataSession, error) {
+	s.ctx = ctx
+	s.errHandler = errHandler
+	session := s.c.NewUserDataSession(s.HandleEvent, errHandler)
+	session.OnResync = s.Resync
+	return session, session.Start(ctx)
+}
+
+// Synced tell whether the state is currently in sync with the stream
+func (s *AccountState) Synced() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.synced
+}
+
+// Balance return the wallet balance of asset, ChangeBalance is not set
+func (s *AccountState) Balance(asset string) (WsBalance, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	b, ok := s.balances[asset]
+	if !ok {
+		return WsBalance{}, false
+	}
+	return b.WsBalance, true
+}
+
+// Balances return the wallet balances of all assets, sorted by asset
+func (s *AccountState) Balances() []WsBalance {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	res := make([]WsBalance, 0, len(s.balances))
+	for _, b := range s.balances {
+		res = append(res, b.WsBalance)
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].Asset < res[j].Asset })
+	return res
+}
+
+// Position return the open position of symbol on side, side is
+// PositionSideTypeBoth in one-way mode
+func (s *AccountState) Position(symbol string, side PositionSideType) (WsPosition, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	p, ok := s.positions[positionKey{symbol, side}]
+	if !ok {
+		return WsPosition{}, false
+	}
+	return p.WsPosition, true
+}
+
+// Positions return the open positions, sorted by symbol and side
+func (s *AccountState) Positions() []WsPosition {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	res := make([]WsPosition, 0, len(s.positions))
+	for _, p := range s.positions {
+		res = append(res, p.WsPosition)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].Symbol != res[j].Symbol {
+			return res[i].Symbol < res[j].Symbol
+		}
+		return res[i].Side < res[j].Side
+	})
+	return res
+}
+
+// OpenOrders return the open orders of symbol, or of all symbols when symbol
+// is empty, sorted by order id
+func (s *AccountState) OpenOrders(symbol string) []Order {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	res := make([]Order, 0, len(s.orders))
+	for _, o := range s.orders {
+		if symbol == "" || o.Symbol == symbol {
+			res = append(res, *o)
+		}
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].OrderID < res[j].OrderID })
+	return res
+}
+
+// Resync fetch a new snapshot in the background, events are buffered and
+// replayed on top of it. Call it when events may have been lost, such as
+// after a reconnection.
+func (s *AccountState) Resync() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.startResync()
+}
+
+// startResync, s.mu must be held
+func (s *AccountState) startResync() {
+	s.synced = false
+	s.outdated = true
+	if !s.syncing {
+		s.syncing = true
+		go s.resync()
+	}
+}
+
+// HandleEvent apply a user data event, it is the handler of the session
+// started by Serve
+func (s *AccountState) HandleEvent(event *WsUserDataEvent) {
+	s.mu.Lock()
+	if !s.synced {
+		s.buffer = append(s.buffer, event)
+		if !s.syncing {
+			s.startResync()
+		}
+		s.mu.Unlock()
+		return
+	}
+	applied, err := s.applyEvent(event, false)
+	if err != nil {
+		s.buffer = []*WsUserDataEvent{event}
+		s.startResync()
+	}
+	s.mu.Unlock()
+	if err != nil {
+		s.error(err)
+		return
+	}
+	if applied {
+		s.notify()
+	}
+}
+
+// applyEvent apply an event on top of the state, s.mu must be held. While
+// replaying the buffer, updates of orders missing from the snapshot are
+// skipped since they ended before it.
+func (s *AccountState) applyEvent(event *WsUserDataEvent, replay bool) (applied bool, err error) {
+	switch event.Event {
+	case UserDataEventTypeAccountUpdate:
+		for _, b := range event.AccountUpdate.Balances {
+			known, ok := s.balances[b.Asset]
+			if ok && event.TransactionTime < known.updateTime {
+				continue
+			}
+			b.ChangeBalance = ""
+			s.balances[b.Asset] = &stateBalance{WsBalance: b, updateTime: event.TransactionTime}
+			applied = true
+		}
+		for _, p := range event.AccountUpdate.Positions {
+			key := positionKey{p.Symbol, p.Side}
+			known, ok := s.positions[key]
+			if ok && event.TransactionTime < known.updateTime {
+				continue
+			}
+			open, err := isOpenPosition(p.Amount)
+			if err != nil {
+				return false, err
+			}
+			if open {
+				s.positions[key] = &statePosition{WsPosition: p, updateTime: event.TransactionTime}
+			} else {
+				delete(s.positions, key)
+			}
+			applied = true
+		}
+	case UserDataEventTypeOrderTradeUpdate:
+		return s.applyOrderUpdate(&event.OrderTradeUpdate, replay)
+	}
+	return applied, nil
+}
+
+func (s *AccountState) applyOrderUpdate(u *WsOrderTradeUpdate, replay bool) (applied bool, err error) {
+	known, ok := s.orders[u.ID]
+	if !ok {
+		if u.ExecutionType != OrderExecutionTypeNew {
+			// The order was created before the stream, only a missed
+			// update of an order still open matters
+			if replay || !isOpenOrderStatus(u.Status) {
+				return false, nil
+			}
+			return false, common.ErrAccountStateGap
+		}
+		known = &Order{Time: u.TradeTime}
+	} else {
+		if u.ExecutionType == OrderExecutionTypeNew {
+			return false, nil
+		}
+		last := ""
+		if u.ExecutionType == OrderExecutionTypeTrade || u.ExecutionType == OrderExecutionTypeCalculated {
+			last = u.LastFilledQty
+		}
+		stale, err := common.CheckFill(known.ExecutedQuantity, u.AccumulatedFilledQty, last)
+		if err != nil || stale {
+			return false, err
+		}
+	}
+	order := &Order{
+		Symbol:           u.Symbol,
+		OrderID:          u.ID,
+		ClientOrderID:    u.ClientOrderID,
+		Price:            u.OriginalPrice,
+		ReduceOnly:       u.IsReduceOnly,
+		OrigQuantity:     u.OriginalQty,
+		ExecutedQuantity: u.AccumulatedFilledQty,
+		Status:           u.Status,
+		TimeInForce:      u.TimeInForce,
+		Type:             u.Type,
+		Side:             u.Side,
+		StopPrice:        u.StopPrice,
+		Time:             known.Time,
+		UpdateTime:       u.TradeTime,
+		WorkingType:      u.WorkingType,
+		ActivatePrice:    u.ActivationPrice,
+		PriceRate:        u.CallbackRate,
+		AvgPrice:         u.AveragePrice,
+		OrigType:         string(u.OriginalType),
+		PositionSide:     u.PositionSide,
+		PriceProtect:     u.PriceProtect,
+		ClosePosition:    u.IsClosingPosition,
+	}
+	if isOpenOrderStatus(order.Status) {
+		s.orders[order.OrderID] = order
+	} else {
+		delete(s.orders, order.OrderID)
+	}
+	return true, nil
+}
+
+// isOpenOrderStatus tell whether orders with status may still be filled
+func isOpenOrderStatus(status OrderStatusType) bool {
+	switch status {
+	case OrderStatusTypeFilled, OrderStatusTypeCanceled, OrderStatusTypeRejected, OrderStatusTypeExpired:
+		return false
+	}
+	return true
+}
+
+// isOpenPosition tell whether a position amount is not zero
+func isOpenPosition(amount string) (bool, error) {
+	d, err := common.ParseDecimal(amount)
+	if err != nil {
+		return false, err
+	}
+	return !d.IsZero(), nil
+}
+
+// resync fetch snapshots until the buffered events apply on top of one
+func (s *AccountState) resync() {
+	ctx := s.ctx
+	attempts := 0
+	for {
+		if attempts > 0 {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(common.Backoff(attempts, 100*time.Millisecond, 10*time.Second)):
+			}
+		}
+		attempts++
+		s.mu.Lock()
+		s.outdated = false
+		s.mu.Unlock()
+		account, err := s.c.NewGetAccountService().Do(ctx)
+		if err == nil {
+			var orders []*Order
+			orders, err = s.c.NewListOpenOrdersService().Do(ctx)
+			if err == nil {
+				var done bool
+				done, err = s.reset(account, orders)
+				if err == nil && !done {
+					// Resync was called again while fetching, events
+					// may be missing from this snapshot
+					attempts = 0
+					continue
+				}
+			}
+		}
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			s.error(err)
+			continue
+		}
+		s.notify()
+		return
+	}
+}
+
+// reset replace the state with a snapshot and replay the buffered events,
+// the snapshot is dropped when outdated
+func (s *AccountState) reset(account *Account, orders []*Order) (done bool, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.outdated {
+		return false, nil
+	}
+	s.balances = make(map[string]*stateBalance, len(account.Assets))
+	for _, a := range account.Assets {
+		s.balances[a.Asset] = &stateBalance{
+			WsBalance:  WsBalance{Asset: a.Asset, Balance: a.WalletBalance, CrossWalletBalance: a.CrossWalletBalance},
+			updateTime: a.UpdateTime,
+		}
+	}
+	s.positions = make(map[positionKey]*statePosition, len(account.Positions))
+	for _, p := range account.Positions {
+		open, err := isOpenPosition(p.PositionAmt)
+		if err != nil {
+			return false, err
+		}
+		if !open {
+			continue
+		}
+		// The stream reports the margin type in lower case
+		marginType := MarginType("cross")
+		if p.Isolated {
+			marginType = MarginType("isolated")
+		}
+		s.positions[positionKey{p.Symbol, p.PositionSide}] = &statePosition{
+			WsPosition: WsPosition{
+				Symbol:        p.Symbol,
+				Side:          p.PositionSide,
+				Amount:        p.PositionAmt,
+				MarginType:    marginType,
+				EntryPrice:    p.EntryPrice,
+				UnrealizedPnL: p.UnrealizedProfit,
+			},
+			updateTime: p.UpdateTime,
+		}
+	}
+	s.orders = make(map[int64]*Order, len(orders))
+	for _, o := range orders {
+		s.orders[o.OrderID] = o
+	}
+	for _, event := range s.buffer {
+		if _, err := s.applyEvent(event, true); err != nil {
+			return false, err
+		}
+	}
+	s.buffer = nil
+	s.synced = true
+	s.syncing = false
+	return true, nil
+}
+
+func (s *AccountState) notify() {
+	if s.OnUpdate != nil {
+		s.OnUpdate(s)
+	}
+}
+
+func (s *AccountState) error(err error) {
+	if s.errHandler != nil {
+		s.errHandler(err)
+	}
+}
@@ -0,0 +1,188 @@
+package futures
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/vv1zard/go-binance/v2/common"
+)
+
+type accountStateTestSuite struct {
+	baseTestSuite
+	origWsServe func(*WsConfig, WsHandler, ErrHandler) (chan struct{}, chan struct{}, error)
+	wsHandler   WsHandler
+	mu          sync.Mutex
+	account     string
+	openOrders  string
+	updates     chan struct{}
+	errs        chan error
+}
+
+func TestAccountState(t *testing.T) {
+	suite.Run(t, new(accountStateTestSuite))
+}
+
+func (s *accountStateTestSuite) SetupTest() {
+	s.baseTestSuite.SetupTest()
+	s.origWsServe = wsServe
+	wsServe = func(cfg *WsConfig, handler WsHandler, errHandler ErrHandler) (doneC, stopC chan struct{}, err error) {
+		s.mu.Lock()
+		s.wsHandler = handler
+		s.mu.Unlock()
+		doneC = make(chan struct{})
+		stopC = make(chan struct{})
+		go func() {
+			<-stopC
+			close(doneC)
+		}()
+		return doneC, stopC, nil
+	}
+	s.client.Client.do = func(req *http.Request) (*http.Response, error) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		switch req.URL.Path {
+		case "/fapi/v1/listenKey":
+			return newHTTPResponse([]byte(`{"listenKey": "fakeListenKey"}`), http.StatusOK), nil
+		case "/fapi/v2/account":
+			return newHTTPResponse([]byte(s.account), http.StatusOK), nil
+		case "/fapi/v1/openOrders":
+			return newHTTPResponse([]byte(s.openOrders), http.StatusOK), nil
+		}
+		return newHTTPResponse([]byte(`{"code": -1000, "msg": "unexpected"}`), http.StatusBadRequest), nil
+	}
+	s.updates = make(chan struct{}, 10)
+	s.errs = make(chan error, 10)
+}
+
+func (s *accountStateTestSuite) TearDownTest() {
+	wsServe = s.origWsServe
+}
+
+func (s *accountStateTestSuite) setSnapshot(usdtBalance, executed string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.account = fmt.Sprintf(`{
+		"assets": [
+			{"asset": "USDT", "walletBalance": "%s", "crossWalletBalance": "%s", "updateTime": 100}
+		],
+		"positions": [
+			{"symbol": "BTCUSDT", "positionSide": "BOTH", "positionAmt": "0.5", "entryPrice": "100",
+			 "unrealizedProfit": "1", "isolated": false, "updateTime": 100},
+			{"symbol": "ETHUSDT", "positionSide": "BOTH", "positionAmt": "0", "entryPrice": "0",
+			 "unrealizedProfit": "0", "isolated": false, "updateTime": 0}
+		]
+	}`, usdtBalance, usdtBalance)
+	s.openOrders = fmt.Sprintf(`[{
+		"symbol": "BTCUSDT", "orderId": 1, "clientOrderId": "a", "price": "100", "origQty": "1",
+		"executedQty": "%s", "status": "NEW", "timeInForce": "GTC", "type": "LIMIT", "side": "BUY",
+		"positionSide": "BOTH", "time": 90, "updateTime": 90
+	}]`, executed)
+}
+
+func (s *accountStateTestSuite) send(message string) {
+	s.mu.Lock()
+	handler := s.wsHandler
+	s.mu.Unlock()
+	handler([]byte(message))
+}
+
+func (s *accountStateTestSuite) sendOrderUpdate(id int64, executionType, status, last, filled string, time int64) {
+	s.send(fmt.Sprintf(`{
+		"e": "ORDER_TRADE_UPDATE", "E": %d, "T": %d, "o": {
+			"s": "BTCUSDT", "c": "a", "S": "BUY", "o": "LIMIT", "f": "GTC", "q": "1", "p": "100",
+			"ap": "100", "sp": "0", "x": "%s", "X": "%s", "i": %d, "l": "%s", "z": "%s", "L": "100",
+			"T": %d, "t": 1, "b": "0", "a": "0", "m": false, "R": false, "wt": "CONTRACT_PRICE",
+			"ot": "LIMIT", "ps": "BOTH", "cp": false, "rp": "0"
+		}
+	}`, time, time, executionType, status, id, last, filled, time))
+}
+
+func (s *accountStateTestSuite) waitUpdate() {
+	select {
+	case <-s.updates:
+	case <-time.After(time.Second):
+		s.FailNow("timed out waiting for state update")
+	}
+}
+
+func (s *accountStateTestSuite) TestSync() {
+	s.setSnapshot("1000", "0")
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	state := s.client.NewAccountState()
+	state.OnUpdate = func(state *AccountState) {
+		s.updates <- struct{}{}
+	}
+	session, err := state.Serve(ctx, func(err error) {
+		s.errs <- err
+	})
+	s.r().NoError(err)
+	s.waitUpdate()
+	s.r().True(state.Synced())
+	s.r().Equal([]WsBalance{
+		{Asset: "USDT", Balance: "1000", CrossWalletBalance: "1000"},
+	}, state.Balances())
+	s.r().Equal([]WsPosition{
+		{Symbol: "BTCUSDT", Side: PositionSideTypeBoth, Amount: "0.5", MarginType: "cross", EntryPrice: "100", UnrealizedPnL: "1"},
+	}, state.Positions())
+	s.r().Len(state.OpenOrders("BTCUSDT"), 1)
+
+	// Stale updates are skipped, closed positions removed
+	s.send(`{"e": "ACCOUNT_UPDATE", "E": 99, "T": 99, "a": {"m": "ORDER",
+		"B": [{"a": "USDT", "wb": "0", "cw": "0", "bc": "0"}], "P": []}}`)
+	s.send(`{"e": "ACCOUNT_UPDATE", "E": 110, "T": 110, "a": {"m": "ORDER",
+		"B": [{"a": "USDT", "wb": "990", "cw": "990", "bc": "0"}],
+		"P": [{"s": "BTCUSDT", "pa": "0", "ep": "0", "cr": "-10", "up": "0", "mt": "cross", "iw": "0", "ps": "BOTH"},
+			{"s": "ETHUSDT", "pa": "-2", "ep": "10", "cr": "0", "up": "0", "mt": "isolated", "iw": "5", "ps": "BOTH"}]}}`)
+	s.waitUpdate()
+	usdt, ok := state.Balance("USDT")
+	s.r().True(ok)
+	s.r().Equal("990", usdt.Balance)
+	_, ok = state.Position("BTCUSDT", PositionSideTypeBoth)
+	s.r().False(ok)
+	eth, ok := state.Position("ETHUSDT", PositionSideTypeBoth)
+	s.r().True(ok)
+	s.r().Equal("-2", eth.Amount)
+
+	s.sendOrderUpdate(1, "TRADE", "PARTIALLY_FILLED", "0.4", "0.4", 130)
+	s.waitUpdate()
+	s.sendOrderUpdate(2, "NEW", "NEW", "0", "0", 131)
+	s.waitUpdate()
+	orders := state.OpenOrders("")
+	s.r().Len(orders, 2)
+	s.r().Equal("0.4", orders[0].ExecutedQuantity)
+	s.r().Equal(OrderStatusTypePartiallyFilled, orders[0].Status)
+	s.r().Equal(int64(90), orders[0].Time)
+	s.sendOrderUpdate(2, "CANCELED", "CANCELED", "0", "0", 132)
+	s.waitUpdate()
+	s.r().Len(state.OpenOrders(""), 1)
+
+	// A trade was missed, the state is fetched again
+	s.setSnapshot("2000", "0.9")
+	s.sendOrderUpdate(1, "TRADE", "PARTIALLY_FILLED", "0.2", "0.9", 140)
+	select {
+	case err := <-s.errs:
+		s.r().True(errors.Is(err, common.ErrAccountStateGap))
+	case <-time.After(time.Second):
+		s.FailNow("gap not reported")
+	}
+	s.waitUpdate()
+	orders = state.OpenOrders("BTCUSDT")
+	s.r().Len(orders, 1)
+	s.r().Equal("0.9", orders[0].ExecutedQuantity)
+	usdt, _ = state.Balance("USDT")
+	s.r().Equal("2000", usdt.Balance)
+
+	cancel()
+	select {
+	case <-session.Done():
+	case <-time.After(time.Second):
+		s.FailNow("session not done")
+	}
+}
@@ -0,0 +1,57 @@
+package futures
+
+import (
+	"context"
+	"time"
+
+	"github.com/vv1zard/go-binance/v2/common"
+)
+
+// BackfillBars feed b with the closed 1m klines of symbol since start, to
+// call on startup before feeding the live stream
+func (c *Client) BackfillBars(ctx context.Context, b *common.BarBuilder, symbol string, start time.Time) error {
+	series, err := c.NewKlineDownloader("").Download(ctx, symbol, "1m", start, time.Now())
+	if err != nil {
+		return err
+	}
+	return b.Backfill(series.Klines)
+}
+
+// BarTrade convert the event for BarBuilder.AddTrade
+func (e *WsAggTradeEvent) BarTrade() (common.BarTrade, error) {
+	return newBarTrade(e.TradeTime, e.Price, e.Quantity, e.LastTradeID-e.FirstTradeID+1)
+}
+
+// BarTrade convert the event for BarBuilder.AddTrade
+func (e *WsTradeEvent) BarTrade() (common.BarTrade, error) {
+	return newBarTrade(e.TradeTime, e.Price, e.Quantity, 1)
+}
+
+// CommonKline convert the kline for BarBuilder.AddKline
+func (w *WsKline) CommonKline() *common.Kline {
+	return &common.Kline{
+		OpenTime:                 w.StartTime,
+		Open:                     w.Open,
+		High:                     w.High,
+		Low:                      w.Low,
+		Close:                    w.Close,
+		Volume:                   w.Volume,
+		CloseTime:                w.EndTime,
+		QuoteAssetVolume:         w.QuoteVolume,
+		TradeNum:                 w.TradeNum,
+		TakerBuyBaseAssetVolume:  w.ActiveBuyVolume,
+		TakerBuyQuoteAssetVolume: w.ActiveBuyQuoteVolume,
+	}
+}
+
+func newBarTrade(t int64, price, quantity string, count int64) (common.BarTrade, error) {
+	p, err := common.ParseDecimal(price)
+	if err != nil {
+		return common.BarTrade{}, err
+	}
+	q, err := common.ParseDecimal(quantity)
+	if err != nil {
+		return common.BarTrade{}, err
+	}
+	return common.BarTrade{Time: t, Price: p, Quantity: q, Count: count}, nil
+}
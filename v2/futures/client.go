@@ -3,13 +3,12 @@ package futures
 import (
 	"bytes"
 	"context"
-	"crypto/hmac"
-	"crypto/sha256"
 	"crypto/tls"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"log"
+	"log/slog"
 	"net/http"
 	"net/url"
 	"os"
@@ -55,6 +54,9 @@ type SideEffectType string
 // WorkingType define working type
 type WorkingType string
 
+// SelfTradePreventionModeType define self trade prevention mode
+type SelfTradePreventionModeType string
+
 // MarginType define margin type
 type MarginType string
 
@@ -124,6 +126,11 @@ const (
 	WorkingTypeMarkPrice     WorkingType = "MARK_PRICE"
 	WorkingTypeContractPrice WorkingType = "CONTRACT_PRICE"
 
+	SelfTradePreventionModeNone        SelfTradePreventionModeType = "NONE"
+	SelfTradePreventionModeExpireTaker SelfTradePreventionModeType = "EXPIRE_TAKER"
+	SelfTradePreventionModeExpireMaker SelfTradePreventionModeType = "EXPIRE_MAKER"
+	SelfTradePreventionModeExpireBoth  SelfTradePreventionModeType = "EXPIRE_BOTH"
+
 	SymbolStatusTypePreTrading   SymbolStatusType = "PRE_TRADING"
 	SymbolStatusTypeTrading      SymbolStatusType = "TRADING"
 	SymbolStatusTypePostTrading  SymbolStatusType = "POST_TRADING"
@@ -211,23 +218,25 @@ func getApiEndpointOrder() string {
 // Services will be created by the form client.NewXXXService().
 func NewClient(apiKey, secretKey string) *Client {
 	return &Client{
-		APIKey:     apiKey,
-		SecretKey:  secretKey,
-		BaseURL:    getApiEndpoint(),
-		UserAgent:  "Binance/golang",
-		HTTPClient: http.DefaultClient,
-		Logger:     log.New(os.Stderr, "Binance-golang ", log.LstdFlags),
+		APIKey:      apiKey,
+		SecretKey:   secretKey,
+		BaseURL:     getApiEndpoint(),
+		UserAgent:   "Binance/golang",
+		HTTPClient:  http.DefaultClient,
+		Logger:      log.New(os.Stderr, "Binance-golang ", log.LstdFlags),
+		RateLimiter: common.NewRateLimiter(),
 	}
 }
 
 func NewClientOrder(apiKey, secretKey string) *Client {
 	return &Client{
-		APIKey:     apiKey,
-		SecretKey:  secretKey,
-		BaseURL:    getApiEndpointOrder(),
-		UserAgent:  "Binance/golang",
-		HTTPClient: http.DefaultClient,
-		Logger:     log.New(os.Stderr, "Binance-golang ", log.LstdFlags),
+		APIKey:      apiKey,
+		SecretKey:   secretKey,
+		BaseURL:     getApiEndpointOrder(),
+		UserAgent:   "Binance/golang",
+		HTTPClient:  http.DefaultClient,
+		Logger:      log.New(os.Stderr, "Binance-golang ", log.LstdFlags),
+		RateLimiter: common.NewRateLimiter(),
 	}
 }
 
@@ -249,7 +258,8 @@ func NewProxiedClient(apiKey, secretKey, proxyUrl string) *Client {
 		HTTPClient: &http.Client{
 			Transport: tr,
 		},
-		Logger: log.New(os.Stderr, "Binance-golang ", log.LstdFlags),
+		Logger:      log.New(os.Stderr, "Binance-golang ", log.LstdFlags),
+		RateLimiter: common.NewRateLimiter(),
 	}
 }
 
@@ -265,13 +275,51 @@ type Client struct {
 	Debug      bool
 	Logger     *log.Logger
 	TimeOffset int64
-	do         doFunc
-}
-
+	// Signer signs the requests, HMAC-SHA256 with SecretKey when nil
+	Signer common.Signer
+	// ClockSync, when set, replaces TimeOffset once synced and signed
+	// requests failing with -1021 are retried once after a resync
+	ClockSync *common.ClockSync
+	// RateLimiter throttles requests to the /fapi endpoints, it is seeded by
+	// ExchangeInfoService and can be set to nil to disable throttling
+	RateLimiter *common.RateLimiter
+	// Middlewares wrap the sending of every request, the first one is the
+	// outermost, see common.RetryMiddleware and common.LoggingMiddleware
+	Middlewares []common.Middleware
+	// LogHandler receives a record of every request, with secrets redacted
+	LogHandler slog.Handler
+	// Metrics receives the latency, status and used weight of every request
+	Metrics common.Metrics
+	do      doFunc
+}
+
+// debug log a line when Debug is set, with the signatures of the URLs and
+// bodies and the listen keys of the responses redacted
 func (c *Client) debug(format string, v ...interface{}) {
 	if c.Debug {
-		c.Logger.Printf(format, v...)
+		c.Logger.Print(common.RedactString(fmt.Sprintf(format, v...)))
+	}
+}
+
+// logger return the structured logger of the client, discarding records
+// when LogHandler is nil
+func (c *Client) logger() *slog.Logger {
+	return common.NewLogger(c.LogHandler)
+}
+
+func (c *Client) signer() common.Signer {
+	if c.Signer != nil {
+		return c.Signer
+	}
+	return common.NewHMACSigner(c.SecretKey)
+}
+
+// timeOffset return the offset measured by ClockSync once synced and TimeOffset otherwise
+func (c *Client) timeOffset() int64 {
+	if c.ClockSync != nil && c.ClockSync.Synced() {
+		return c.ClockSync.Offset()
 	}
+	return c.TimeOffset
 }
 
 func (c *Client) parseRequest(r *request, opts ...RequestOption) (err error) {
@@ -289,7 +337,7 @@ func (c *Client) parseRequest(r *request, opts ...RequestOption) (err error) {
 		r.setParam(recvWindowKey, r.recvWindow)
 	}
 	if r.secType == secTypeSigned {
-		r.setParam(timestampKey, currentTimestamp()-c.TimeOffset)
+		r.setParam(timestampKey, currentTimestamp()-c.timeOffset())
 	}
 	queryString := r.query.Encode()
 	body := &bytes.Buffer{}
@@ -308,13 +356,12 @@ func (c *Client) parseRequest(r *request, opts ...RequestOption) (err error) {
 
 	if r.secType == secTypeSigned {
 		raw := fmt.Sprintf("%s%s", queryString, bodyString)
-		mac := hmac.New(sha256.New, []byte(c.SecretKey))
-		_, err = mac.Write([]byte(raw))
+		signature, err := c.signer().Sign([]byte(raw))
 		if err != nil {
 			return err
 		}
 		v := url.Values{}
-		v.Set(signatureKey, fmt.Sprintf("%x", (mac.Sum(nil))))
+		v.Set(signatureKey, signature)
 		if queryString == "" {
 			queryString = v.Encode()
 		} else {
@@ -324,7 +371,7 @@ func (c *Client) parseRequest(r *request, opts ...RequestOption) (err error) {
 	if queryString != "" {
 		fullURL = fmt.Sprintf("%s?%s", fullURL, queryString)
 	}
-	c.debug("full url: %s, body: %s", fullURL, bodyString)
+	c.debug("full url: %s, body: %s", common.RedactString(fullURL), common.RedactString(bodyString))
 
 	r.fullURL = fullURL
 	r.header = header
@@ -333,25 +380,56 @@ func (c *Client) parseRequest(r *request, opts ...RequestOption) (err error) {
 }
 
 func (c *Client) callAPI(ctx context.Context, r *request, opts ...RequestOption) (data []byte, header *http.Header, err error) {
+	data, header, err = c.send(ctx, r, opts...)
+	// Retry once with a fresh offset when the clock drifted out of the
+	// recvWindow, the options are already applied to r
+	if err != nil && r.secType == secTypeSigned && c.ClockSync != nil && common.IsInvalidTimestamp(err) {
+		if _, serr := c.ClockSync.Resync(ctx); serr == nil {
+			data, header, err = c.send(ctx, r)
+		}
+	}
+	return data, header, err
+}
+
+func (c *Client) send(ctx context.Context, r *request, opts ...RequestOption) (data []byte, header *http.Header, err error) {
 	err = c.parseRequest(r, opts...)
 	if err != nil {
 		return []byte{}, &http.Header{}, err
 	}
+	weight, orders, limited := requestWeight(r)
+	limited = limited && c.RateLimiter != nil
+	if limited {
+		err = c.RateLimiter.Wait(ctx, weight, orders)
+		if err != nil {
+			return []byte{}, &http.Header{}, err
+		}
+	}
+	start, status := time.Now(), 0
+	var resHeader http.Header
+	defer func() {
+		latency := time.Since(start)
+		common.LogRequest(ctx, c.logger(), r.method, r.endpoint, weight, status, latency, err)
+		common.RecordRequest(c.Metrics, r.method, r.endpoint, status, resHeader, latency, err)
+	}()
 	req, err := http.NewRequest(r.method, r.fullURL, r.body)
 	if err != nil {
 		return []byte{}, &http.Header{}, err
 	}
 	req = req.WithContext(ctx)
 	req.Header = r.header
-	c.debug("request: %#v", req)
+	c.debug("request: %s %s", req.Method, common.RedactString(req.URL.String()))
 	f := c.do
 	if f == nil {
 		f = c.HTTPClient.Do
 	}
-	res, err := f(req)
+	res, err := common.Chain(common.Doer(f), c.Middlewares...)(req)
 	if err != nil {
 		return []byte{}, &http.Header{}, err
 	}
+	status, resHeader = res.StatusCode, res.Header
+	if limited {
+		c.RateLimiter.Update(res.StatusCode, res.Header)
+	}
 	data, err = ioutil.ReadAll(res.Body)
 	if err != nil {
 		return []byte{}, &http.Header{}, err
@@ -365,7 +443,7 @@ func (c *Client) callAPI(ctx context.Context, r *request, opts ...RequestOption)
 		}
 	}()
 	c.debug("response: %#v", res)
-	c.debug("response body: %s", string(data))
+	c.debug("response body: %s", common.RedactString(string(data)))
 	c.debug("response status code: %d", res.StatusCode)
 
 	if res.StatusCode >= http.StatusBadRequest {
@@ -374,6 +452,10 @@ func (c *Client) callAPI(ctx context.Context, r *request, opts ...RequestOption)
 		if e != nil {
 			c.debug("failed to unmarshal json: %s", e)
 		}
+		apiErr.StatusCode = res.StatusCode
+		apiErr.Method = r.method
+		apiErr.Endpoint = r.endpoint
+		apiErr.Weight = weight
 		return nil, &http.Header{}, apiErr
 	}
 	return data, &res.Header, nil
@@ -468,6 +550,11 @@ func (c *Client) NewCancelAllOpenOrdersService() *CancelAllOpenOrdersService {
 	return &CancelAllOpenOrdersService{c: c}
 }
 
+// NewCountdownCancelAllService init countdown cancel all service
+func (c *Client) NewCountdownCancelAllService() *CountdownCancelAllService {
+	return &CountdownCancelAllService{c: c}
+}
+
 // NewCancelMultipleOrdersService init cancel multiple orders service
 func (c *Client) NewCancelMultipleOrdersService() *CancelMultiplesOrdersService {
 	return &CancelMultiplesOrdersService{c: c}
@@ -0,0 +1,19 @@
+package futures
+
+import (
+	"context"
+	"time"
+
+	"github.com/vv1zard/go-binance/v2/common"
+)
+
+// NewCountdownHeartbeat init a dead man's switch re-arming the countdown
+// cancel all of symbols while the process is healthy. Call Start on the
+// returned heartbeat to arm it.
+func (c *Client) NewCountdownHeartbeat(symbols ...string) *common.CountdownHeartbeat {
+	return common.NewCountdownHeartbeat(func(ctx context.Context, symbol string, countdown time.Duration) error {
+		_, err := c.NewCountdownCancelAllService().Symbol(symbol).
+			CountdownTime(countdown.Milliseconds()).Do(ctx)
+		return err
+	}, symbols...)
+}
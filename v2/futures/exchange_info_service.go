@@ -5,6 +5,8 @@ import (
 	"encoding/json"
 	"net/http"
 	"strconv"
+
+	"github.com/vv1zard/go-binance/v2/common"
 )
 
 // ExchangeInfoService exchange info service
@@ -28,7 +30,9 @@ func (s *ExchangeInfoService) Do(ctx context.Context, opts ...RequestOption) (re
 	if err != nil {
 		return nil, err
 	}
-
+	if s.c.RateLimiter != nil {
+		s.c.RateLimiter.SetLimits(res.limits()...)
+	}
 	return res, nil
 }
 
@@ -41,6 +45,17 @@ type ExchangeInfo struct {
 	Symbols         []Symbol      `json:"symbols"`
 }
 
+func (e *ExchangeInfo) limits() []common.RateLimit {
+	limits := make([]common.RateLimit, 0, len(e.RateLimits))
+	for _, rl := range e.RateLimits {
+		limit, ok := common.ParseRateLimit(rl.RateLimitType, rl.Interval, rl.IntervalNum, rl.Limit)
+		if ok {
+			limits = append(limits, limit)
+		}
+	}
+	return limits
+}
+
 // RateLimit struct
 type RateLimit struct {
 	RateLimitType string `json:"rateLimitType"`
@@ -4,6 +4,10 @@ import (
 	"context"
 	"encoding/json"
 	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/vv1zard/go-binance/v2/common"
 )
 
 // GetIncomeHistoryService get position margin history service
@@ -79,6 +83,35 @@ func (s *GetIncomeHistoryService) Do(ctx context.Context, opts ...RequestOption)
 	return res, nil
 }
 
+// Iterate walk the income history from StartTime up to EndTime in windows of 7
+// days with as many requests as needed, Limit sets the page size
+func (s *GetIncomeHistoryService) Iterate(opts ...RequestOption) *common.Iterator[*IncomeHistory] {
+	p := common.Pager[*IncomeHistory]{
+		Fetch: func(ctx context.Context, q common.PageQuery) ([]*IncomeHistory, error) {
+			limit := int64(q.Limit)
+			page := &GetIncomeHistoryService{c: s.c, symbol: s.symbol, incomeType: s.incomeType,
+				startTime: &q.StartTime, endTime: &q.EndTime, limit: &limit}
+			return page.Do(ctx, opts...)
+		},
+		Limit:     1000,
+		MaxWindow: 7 * 24 * time.Hour,
+		Time:      func(income *IncomeHistory) int64 { return income.Time },
+		Key: func(income *IncomeHistory) interface{} {
+			return [4]string{strconv.FormatInt(income.TranID, 10), income.IncomeType, income.Asset, income.Symbol}
+		},
+	}
+	if s.limit != nil {
+		p.Limit = int(*s.limit)
+	}
+	if s.startTime != nil {
+		p.StartTime = *s.startTime
+	}
+	if s.endTime != nil {
+		p.EndTime = *s.endTime
+	}
+	return p.Iterator()
+}
+
 // IncomeHistory define position margin history info
 type IncomeHistory struct {
 	Asset      string `json:"asset"`
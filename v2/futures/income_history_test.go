@@ -1,6 +1,10 @@
 package futures
 
 import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/suite"
@@ -61,6 +65,51 @@ func (s *incomeHistoryServiceTestSuite) TestIncomeHistory() {
 	s.assertOrderEqual(e, orders[0])
 }
 
+func (s *incomeHistoryServiceTestSuite) TestIncomeHistoryIterate() {
+	// Commission and realized pnl of a trade share the time and tranId
+	incomes := []struct {
+		time       int64
+		tranID     int64
+		incomeType string
+	}{
+		{1570636800000, 1, "COMMISSION"},
+		{1570636800000, 1, "REALIZED_PNL"},
+		{1570636800001, 2, "COMMISSION"},
+		{1570636800001, 2, "REALIZED_PNL"},
+		{1570636800001, 3, "COMMISSION"},
+		{1570723200000, 4, "FUNDING_FEE"},
+	}
+	var queries []string
+	s.client.Client.do = func(req *http.Request) (*http.Response, error) {
+		query := req.URL.Query()
+		queries = append(queries, fmt.Sprintf("startTime=%s endTime=%s", query.Get("startTime"), query.Get("endTime")))
+		startTime, _ := strconv.ParseInt(query.Get("startTime"), 10, 64)
+		endTime, _ := strconv.ParseInt(query.Get("endTime"), 10, 64)
+		var page []string
+		for _, income := range incomes {
+			if income.time >= startTime && income.time <= endTime && len(page) < 4 {
+				page = append(page, fmt.Sprintf(`{"symbol": "BTCUSDT", "incomeType": "%s", "asset": "USDT", "time": %d, "tranId": %d}`,
+					income.incomeType, income.time, income.tranID))
+			}
+		}
+		return newHTTPResponse([]byte("["+strings.Join(page, ",")+"]"), http.StatusOK), nil
+	}
+	res, err := s.client.NewGetIncomeHistoryService().Limit(4).
+		StartTime(1570636800000).EndTime(1570723200000).Iterate().All(newContext())
+	r := s.r()
+	r.NoError(err)
+	var got []string
+	for _, income := range res {
+		got = append(got, fmt.Sprintf("%d %s", income.TranID, income.IncomeType))
+	}
+	r.Equal([]string{"1 COMMISSION", "1 REALIZED_PNL", "2 COMMISSION", "2 REALIZED_PNL", "3 COMMISSION", "4 FUNDING_FEE"}, got)
+	r.Equal([]string{
+		"startTime=1570636800000 endTime=1570723200000",
+		"startTime=1570636800001 endTime=1570723200000",
+		"startTime=1570723200000 endTime=1570723200000",
+	}, queries)
+}
+
 func (s *incomeHistoryServiceTestSuite) assertOrderEqual(e, a *IncomeHistory) {
 	r := s.r()
 	r.Equal(e.Income, a.Income, "Income")
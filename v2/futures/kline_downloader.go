@@ -0,0 +1,56 @@
+package futures
+
+import (
+	"context"
+
+	"github.com/vv1zard/go-binance/v2/common"
+)
+
+// NewKlineDownloader init a downloader of the USDⓈ-M futures klines caching
+// them under dir, an empty dir disables the cache
+func (c *Client) NewKlineDownloader(dir string) *common.KlineDownloader {
+	return newKlineDownloader("futures", dir, func(ctx context.Context, symbol, interval string, startTime, endTime int64, limit int) ([]*Kline, error) {
+		return c.NewKlinesService().Symbol(symbol).Interval(interval).
+			StartTime(startTime).EndTime(endTime).Limit(limit).Do(ctx)
+	})
+}
+
+// NewIndexPriceKlineDownloader init a downloader of the index price klines of
+// a pair, see NewKlineDownloader
+func (c *Client) NewIndexPriceKlineDownloader(dir string) *common.KlineDownloader {
+	return newKlineDownloader("futures-index-price", dir, func(ctx context.Context, pair, interval string, startTime, endTime int64, limit int) ([]*Kline, error) {
+		return c.NewIndexPriceKlinesService().Pair(pair).Interval(interval).
+			StartTime(startTime).EndTime(endTime).Limit(limit).Do(ctx)
+	})
+}
+
+// NewMarkPriceKlineDownloader init a downloader of the mark price klines, see
+// NewKlineDownloader
+func (c *Client) NewMarkPriceKlineDownloader(dir string) *common.KlineDownloader {
+	return newKlineDownloader("futures-mark-price", dir, func(ctx context.Context, symbol, interval string, startTime, endTime int64, limit int) ([]*Kline, error) {
+		return c.NewMarkPriceKlinesService().Symbol(symbol).Interval(interval).
+			StartTime(startTime).EndTime(endTime).Limit(limit).Do(ctx)
+	})
+}
+
+// newKlineDownloader requests up to 1500 klines at once, the maximum of the
+// endpoint, to keep the number of requests low
+func newKlineDownloader(market, dir string, fetch func(ctx context.Context, symbol, interval string, startTime, endTime int64, limit int) ([]*Kline, error)) *common.KlineDownloader {
+	d := &common.KlineDownloader{
+		Market: market,
+		Limit:  1500,
+		Fetch: func(ctx context.Context, symbol, interval string, startTime, endTime int64, limit int) ([]*common.Kline, error) {
+			klines, err := fetch(ctx, symbol, interval, startTime, endTime, limit)
+			res := make([]*common.Kline, len(klines))
+			for i, kline := range klines {
+				k := common.Kline(*kline)
+				res[i] = &k
+			}
+			return res, err
+		},
+	}
+	if dir != "" {
+		d.Cache = common.NewKlineCache(dir)
+	}
+	return d
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+
+	"github.com/vv1zard/go-binance/v2/common"
 )
 
 // KlinesService list klines
@@ -110,3 +112,33 @@ type Kline struct {
 	TakerBuyBaseAssetVolume  string `json:"takerBuyBaseAssetVolume"`
 	TakerBuyQuoteAssetVolume string `json:"takerBuyQuoteAssetVolume"`
 }
+
+// OpenDecimal parse Open as an exact decimal
+func (k *Kline) OpenDecimal() (common.Decimal, error) {
+	return common.ParseDecimal(k.Open)
+}
+
+// HighDecimal parse High as an exact decimal
+func (k *Kline) HighDecimal() (common.Decimal, error) {
+	return common.ParseDecimal(k.High)
+}
+
+// LowDecimal parse Low as an exact decimal
+func (k *Kline) LowDecimal() (common.Decimal, error) {
+	return common.ParseDecimal(k.Low)
+}
+
+// CloseDecimal parse Close as an exact decimal
+func (k *Kline) CloseDecimal() (common.Decimal, error) {
+	return common.ParseDecimal(k.Close)
+}
+
+// VolumeDecimal parse Volume as an exact decimal
+func (k *Kline) VolumeDecimal() (common.Decimal, error) {
+	return common.ParseDecimal(k.Volume)
+}
+
+// QuoteAssetVolumeDecimal parse QuoteAssetVolume as an exact decimal
+func (k *Kline) QuoteAssetVolumeDecimal() (common.Decimal, error) {
+	return common.ParseDecimal(k.QuoteAssetVolume)
+}
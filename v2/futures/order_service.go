@@ -7,6 +7,9 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
+	"time"
+
+	"github.com/vv1zard/go-binance/v2/common"
 )
 
 // CreateOrderService create order
@@ -29,6 +32,7 @@ type CreateOrderService struct {
 	newOrderRespType NewOrderRespType
 	closePosition    *bool
 	noLiquidation    *bool
+	stpMode          *SelfTradePreventionModeType
 }
 
 // Symbol set symbol
@@ -132,8 +136,23 @@ func (s *CreateOrderService) NoLiquidation(noLiquidation bool) *CreateOrderServi
 	return s
 }
 
+// SelfTradePreventionMode set selfTradePreventionMode, which expires the
+// orders of the same user or group that would match
+func (s *CreateOrderService) SelfTradePreventionMode(mode SelfTradePreventionModeType) *CreateOrderService {
+	s.stpMode = &mode
+	return s
+}
+
 func (s *CreateOrderService) createOrder(ctx context.Context, endpoint string, opts ...RequestOption) (data []byte, header *http.Header, err error) {
+	data, header, err = s.c.callAPI(ctx, s.buildRequest(endpoint), opts...)
+	if err != nil {
+		return []byte{}, &http.Header{}, err
+	}
+	return data, header, nil
+}
 
+// buildRequest build the request, shared with the websocket API
+func (s *CreateOrderService) buildRequest(endpoint string) *request {
 	r := &request{
 		method:   http.MethodPost,
 		endpoint: endpoint,
@@ -182,12 +201,11 @@ func (s *CreateOrderService) createOrder(ctx context.Context, endpoint string, o
 	if s.noLiquidation != nil {
 		m["nl"] = *s.noLiquidation
 	}
-	r.setFormParams(m)
-	data, header, err = s.c.callAPI(ctx, r, opts...)
-	if err != nil {
-		return []byte{}, &http.Header{}, err
+	if s.stpMode != nil {
+		m["selfTradePreventionMode"] = *s.stpMode
 	}
-	return data, header, nil
+	r.setFormParams(m)
+	return r
 }
 
 // Do send request
@@ -209,29 +227,55 @@ func (s *CreateOrderService) Do(ctx context.Context, opts ...RequestOption) (res
 
 // CreateOrderResponse define create order response
 type CreateOrderResponse struct {
-	Symbol            string           `json:"symbol"`
-	OrderID           int64            `json:"orderId"`
-	ClientOrderID     string           `json:"clientOrderId"`
-	Price             string           `json:"price"`
-	OrigQuantity      string           `json:"origQty"`
-	ExecutedQuantity  string           `json:"executedQty"`
-	CumQuote          string           `json:"cumQuote"`
-	ReduceOnly        bool             `json:"reduceOnly"`
-	Status            OrderStatusType  `json:"status"`
-	StopPrice         string           `json:"stopPrice"`
-	TimeInForce       TimeInForceType  `json:"timeInForce"`
-	Type              OrderType        `json:"type"`
-	Side              SideType         `json:"side"`
-	UpdateTime        int64            `json:"updateTime"`
-	WorkingType       WorkingType      `json:"workingType"`
-	ActivatePrice     string           `json:"activatePrice"`
-	PriceRate         string           `json:"priceRate"`
-	AvgPrice          string           `json:"avgPrice"`
-	PositionSide      PositionSideType `json:"positionSide"`
-	ClosePosition     bool             `json:"closePosition"`
-	PriceProtect      bool             `json:"priceProtect"`
-	RateLimitOrder10s string           `json:"rateLimitOrder10s,omitempty"`
-	RateLimitOrder1m  string           `json:"rateLimitOrder1m,omitempty"`
+	Symbol                  string                      `json:"symbol"`
+	OrderID                 int64                       `json:"orderId"`
+	ClientOrderID           string                      `json:"clientOrderId"`
+	Price                   string                      `json:"price"`
+	OrigQuantity            string                      `json:"origQty"`
+	ExecutedQuantity        string                      `json:"executedQty"`
+	CumQuote                string                      `json:"cumQuote"`
+	ReduceOnly              bool                        `json:"reduceOnly"`
+	Status                  OrderStatusType             `json:"status"`
+	StopPrice               string                      `json:"stopPrice"`
+	TimeInForce             TimeInForceType             `json:"timeInForce"`
+	Type                    OrderType                   `json:"type"`
+	Side                    SideType                    `json:"side"`
+	UpdateTime              int64                       `json:"updateTime"`
+	WorkingType             WorkingType                 `json:"workingType"`
+	ActivatePrice           string                      `json:"activatePrice"`
+	PriceRate               string                      `json:"priceRate"`
+	AvgPrice                string                      `json:"avgPrice"`
+	PositionSide            PositionSideType            `json:"positionSide"`
+	ClosePosition           bool                        `json:"closePosition"`
+	PriceProtect            bool                        `json:"priceProtect"`
+	SelfTradePreventionMode SelfTradePreventionModeType `json:"selfTradePreventionMode"`
+	RateLimitOrder10s       string                      `json:"rateLimitOrder10s,omitempty"`
+	RateLimitOrder1m        string                      `json:"rateLimitOrder1m,omitempty"`
+}
+
+// PriceDecimal parse Price as an exact decimal
+func (c *CreateOrderResponse) PriceDecimal() (common.Decimal, error) {
+	return common.ParseDecimal(c.Price)
+}
+
+// OrigQuantityDecimal parse OrigQuantity as an exact decimal
+func (c *CreateOrderResponse) OrigQuantityDecimal() (common.Decimal, error) {
+	return common.ParseDecimal(c.OrigQuantity)
+}
+
+// ExecutedQuantityDecimal parse ExecutedQuantity as an exact decimal
+func (c *CreateOrderResponse) ExecutedQuantityDecimal() (common.Decimal, error) {
+	return common.ParseDecimal(c.ExecutedQuantity)
+}
+
+// CumQuoteDecimal parse CumQuote as an exact decimal
+func (c *CreateOrderResponse) CumQuoteDecimal() (common.Decimal, error) {
+	return common.ParseDecimal(c.CumQuote)
+}
+
+// AvgPriceDecimal parse AvgPrice as an exact decimal
+func (c *CreateOrderResponse) AvgPriceDecimal() (common.Decimal, error) {
+	return common.ParseDecimal(c.AvgPrice)
 }
 
 type ModifyOrderService struct {
@@ -279,7 +323,15 @@ func (s *ModifyOrderService) OrderID(orderID int64) *ModifyOrderService {
 }
 
 func (s *ModifyOrderService) modifyOrder(ctx context.Context, endpoint string, opts ...RequestOption) (data []byte, header *http.Header, err error) {
+	data, header, err = s.c.callAPI(ctx, s.buildRequest(endpoint), opts...)
+	if err != nil {
+		return []byte{}, &http.Header{}, err
+	}
+	return data, header, nil
+}
 
+// buildRequest build the request, shared with the websocket API
+func (s *ModifyOrderService) buildRequest(endpoint string) *request {
 	r := &request{
 		method:   http.MethodPut,
 		endpoint: endpoint,
@@ -298,11 +350,7 @@ func (s *ModifyOrderService) modifyOrder(ctx context.Context, endpoint string, o
 		m["orderId"] = *s.orderID
 	}
 	r.setFormParams(m)
-	data, header, err = s.c.callAPI(ctx, r, opts...)
-	if err != nil {
-		return []byte{}, &http.Header{}, err
-	}
-	return data, header, nil
+	return r
 }
 
 func (s *ModifyOrderService) Do(ctx context.Context, opts ...RequestOption) (res *ModifyOrderResponse, err error) {
@@ -457,6 +505,19 @@ func (s *GetOrderService) OrigClientOrderID(origClientOrderID string) *GetOrderS
 
 // Do send request
 func (s *GetOrderService) Do(ctx context.Context, opts ...RequestOption) (res *Order, err error) {
+	data, _, err := s.c.callAPI(ctx, s.buildRequest(), opts...)
+	if err != nil {
+		return nil, err
+	}
+	res = new(Order)
+	err = json.Unmarshal(data, res)
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func (s *GetOrderService) buildRequest() *request {
 	r := &request{
 		method:   http.MethodGet,
 		endpoint: "/fapi/v1/order",
@@ -469,44 +530,66 @@ func (s *GetOrderService) Do(ctx context.Context, opts ...RequestOption) (res *O
 	if s.origClientOrderID != nil {
 		r.setParam("origClientOrderId", *s.origClientOrderID)
 	}
-	data, _, err := s.c.callAPI(ctx, r, opts...)
-	if err != nil {
-		return nil, err
-	}
-	res = new(Order)
-	err = json.Unmarshal(data, res)
-	if err != nil {
-		return nil, err
-	}
-	return res, nil
+	return r
 }
 
 // Order define order info
 type Order struct {
-	Symbol           string           `json:"symbol"`
-	OrderID          int64            `json:"orderId"`
-	ClientOrderID    string           `json:"clientOrderId"`
-	Price            string           `json:"price"`
-	ReduceOnly       bool             `json:"reduceOnly"`
-	OrigQuantity     string           `json:"origQty"`
-	ExecutedQuantity string           `json:"executedQty"`
-	CumQuantity      string           `json:"cumQty"`
-	CumQuote         string           `json:"cumQuote"`
-	Status           OrderStatusType  `json:"status"`
-	TimeInForce      TimeInForceType  `json:"timeInForce"`
-	Type             OrderType        `json:"type"`
-	Side             SideType         `json:"side"`
-	StopPrice        string           `json:"stopPrice"`
-	Time             int64            `json:"time"`
-	UpdateTime       int64            `json:"updateTime"`
-	WorkingType      WorkingType      `json:"workingType"`
-	ActivatePrice    string           `json:"activatePrice"`
-	PriceRate        string           `json:"priceRate"`
-	AvgPrice         string           `json:"avgPrice"`
-	OrigType         string           `json:"origType"`
-	PositionSide     PositionSideType `json:"positionSide"`
-	PriceProtect     bool             `json:"priceProtect"`
-	ClosePosition    bool             `json:"closePosition"`
+	Symbol                  string                      `json:"symbol"`
+	OrderID                 int64                       `json:"orderId"`
+	ClientOrderID           string                      `json:"clientOrderId"`
+	Price                   string                      `json:"price"`
+	ReduceOnly              bool                        `json:"reduceOnly"`
+	OrigQuantity            string                      `json:"origQty"`
+	ExecutedQuantity        string                      `json:"executedQty"`
+	CumQuantity             string                      `json:"cumQty"`
+	CumQuote                string                      `json:"cumQuote"`
+	Status                  OrderStatusType             `json:"status"`
+	TimeInForce             TimeInForceType             `json:"timeInForce"`
+	Type                    OrderType                   `json:"type"`
+	Side                    SideType                    `json:"side"`
+	StopPrice               string                      `json:"stopPrice"`
+	Time                    int64                       `json:"time"`
+	UpdateTime              int64                       `json:"updateTime"`
+	WorkingType             WorkingType                 `json:"workingType"`
+	ActivatePrice           string                      `json:"activatePrice"`
+	PriceRate               string                      `json:"priceRate"`
+	AvgPrice                string                      `json:"avgPrice"`
+	OrigType                string                      `json:"origType"`
+	PositionSide            PositionSideType            `json:"positionSide"`
+	PriceProtect            bool                        `json:"priceProtect"`
+	ClosePosition           bool                        `json:"closePosition"`
+	SelfTradePreventionMode SelfTradePreventionModeType `json:"selfTradePreventionMode"`
+}
+
+// PriceDecimal parse Price as an exact decimal
+func (o *Order) PriceDecimal() (common.Decimal, error) {
+	return common.ParseDecimal(o.Price)
+}
+
+// OrigQuantityDecimal parse OrigQuantity as an exact decimal
+func (o *Order) OrigQuantityDecimal() (common.Decimal, error) {
+	return common.ParseDecimal(o.OrigQuantity)
+}
+
+// ExecutedQuantityDecimal parse ExecutedQuantity as an exact decimal
+func (o *Order) ExecutedQuantityDecimal() (common.Decimal, error) {
+	return common.ParseDecimal(o.ExecutedQuantity)
+}
+
+// CumQuoteDecimal parse CumQuote as an exact decimal
+func (o *Order) CumQuoteDecimal() (common.Decimal, error) {
+	return common.ParseDecimal(o.CumQuote)
+}
+
+// StopPriceDecimal parse StopPrice as an exact decimal
+func (o *Order) StopPriceDecimal() (common.Decimal, error) {
+	return common.ParseDecimal(o.StopPrice)
+}
+
+// AvgPriceDecimal parse AvgPrice as an exact decimal
+func (o *Order) AvgPriceDecimal() (common.Decimal, error) {
+	return common.ParseDecimal(o.AvgPrice)
 }
 
 // ListOrdersService all account orders; active, canceled, or filled
@@ -581,6 +664,37 @@ func (s *ListOrdersService) Do(ctx context.Context, opts ...RequestOption) (res
 	return res, nil
 }
 
+// Iterate walk the orders of the symbol from StartTime, or from OrderID, up to
+// EndTime with as many requests as needed, Limit sets the page size
+func (s *ListOrdersService) Iterate(opts ...RequestOption) *common.Iterator[*Order] {
+	p := common.Pager[*Order]{
+		Fetch: func(ctx context.Context, q common.PageQuery) ([]*Order, error) {
+			page := &ListOrdersService{c: s.c, symbol: s.symbol, limit: &q.Limit}
+			if q.FromID != nil {
+				page.orderID = q.FromID
+			} else {
+				page.startTime, page.endTime = &q.StartTime, &q.EndTime
+			}
+			return page.Do(ctx, opts...)
+		},
+		Limit:     1000,
+		MaxWindow: 7 * 24 * time.Hour,
+		Time:      func(order *Order) int64 { return order.Time },
+		ID:        func(order *Order) int64 { return order.OrderID },
+		FromID:    s.orderID,
+	}
+	if s.limit != nil {
+		p.Limit = *s.limit
+	}
+	if s.startTime != nil {
+		p.StartTime = *s.startTime
+	}
+	if s.endTime != nil {
+		p.EndTime = *s.endTime
+	}
+	return p.Iterator()
+}
+
 // CancelOrderService cancel an order
 type CancelOrderService struct {
 	c                 *Client
@@ -609,6 +723,19 @@ func (s *CancelOrderService) OrigClientOrderID(origClientOrderID string) *Cancel
 
 // Do send request
 func (s *CancelOrderService) Do(ctx context.Context, opts ...RequestOption) (res *CancelOrderResponse, err error) {
+	data, _, err := s.c.callAPI(ctx, s.buildRequest(), opts...)
+	if err != nil {
+		return nil, err
+	}
+	res = new(CancelOrderResponse)
+	err = json.Unmarshal(data, res)
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func (s *CancelOrderService) buildRequest() *request {
 	r := &request{
 		method:   http.MethodDelete,
 		endpoint: "/fapi/v1/order",
@@ -621,16 +748,7 @@ func (s *CancelOrderService) Do(ctx context.Context, opts ...RequestOption) (res
 	if s.origClientOrderID != nil {
 		r.setFormParam("origClientOrderId", *s.origClientOrderID)
 	}
-	data, _, err := s.c.callAPI(ctx, r, opts...)
-	if err != nil {
-		return nil, err
-	}
-	res = new(CancelOrderResponse)
-	err = json.Unmarshal(data, res)
-	if err != nil {
-		return nil, err
-	}
-	return res, nil
+	return r
 }
 
 // CancelOrderResponse define response of canceling order
@@ -685,6 +803,55 @@ func (s *CancelAllOpenOrdersService) Do(ctx context.Context, opts ...RequestOpti
 	return nil
 }
 
+// CountdownCancelAllService arm a countdown cancelling all the open orders
+// of a symbol once it ends, unless armed again before
+type CountdownCancelAllService struct {
+	c             *Client
+	symbol        string
+	countdownTime int64
+}
+
+// Symbol set symbol
+func (s *CountdownCancelAllService) Symbol(symbol string) *CountdownCancelAllService {
+	s.symbol = symbol
+	return s
+}
+
+// CountdownTime set countdownTime in milliseconds, 0 cancels the countdown
+func (s *CountdownCancelAllService) CountdownTime(countdownTime int64) *CountdownCancelAllService {
+	s.countdownTime = countdownTime
+	return s
+}
+
+// Do send request
+func (s *CountdownCancelAllService) Do(ctx context.Context, opts ...RequestOption) (res *CountdownCancelAllResponse, err error) {
+	r := &request{
+		method:   http.MethodPost,
+		endpoint: "/fapi/v1/countdownCancelAll",
+		secType:  secTypeSigned,
+	}
+	r.setFormParams(params{
+		"symbol":        s.symbol,
+		"countdownTime": s.countdownTime,
+	})
+	data, _, err := s.c.callAPI(ctx, r, opts...)
+	if err != nil {
+		return nil, err
+	}
+	res = new(CountdownCancelAllResponse)
+	err = json.Unmarshal(data, res)
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// CountdownCancelAllResponse define countdown cancel all response
+type CountdownCancelAllResponse struct {
+	Symbol        string `json:"symbol"`
+	CountdownTime string `json:"countdownTime"`
+}
+
 // CancelMultiplesOrdersService cancel a list of orders
 type CancelMultiplesOrdersService struct {
 	c                     *Client
@@ -978,6 +1145,9 @@ func (s *CreateBatchOrdersService) Do(ctx context.Context, opts ...RequestOption
 		if order.closePosition != nil {
 			m["closePosition"] = *order.closePosition
 		}
+		if order.stpMode != nil {
+			m["selfTradePreventionMode"] = *order.stpMode
+		}
 		orders = append(orders, m)
 	}
 	b, err := json.Marshal(orders)
@@ -63,7 +63,8 @@ func (s *orderServiceTestSuite) TestCreateOrder() {
 		"priceRate": "0.1",
 		"positionSide": "BOTH",
 		"closePosition": false,
-		"priceProtect": true
+		"priceProtect": true,
+		"selfTradePreventionMode": "EXPIRE_TAKER"
 	}`)
 	s.mockDo(data, nil)
 	defer s.assertDo()
@@ -83,6 +84,7 @@ func (s *orderServiceTestSuite) TestCreateOrder() {
 	priceProtect := true
 	newOrderResponseType := NewOrderRespTypeRESULT
 	closePosition := false
+	stpMode := SelfTradePreventionModeExpireTaker
 	s.assertReq(func(r *request) {
 		e := newSignedRequest().setFormParams(params{
 			"symbol":           symbol,
@@ -101,6 +103,8 @@ func (s *orderServiceTestSuite) TestCreateOrder() {
 			"priceProtect":     priceProtect,
 			"newOrderRespType": newOrderResponseType,
 			"closePosition":    closePosition,
+
+			"selfTradePreventionMode": stpMode,
 		})
 		s.assertRequestEqual(e, r)
 	})
@@ -110,7 +114,7 @@ func (s *orderServiceTestSuite) TestCreateOrder() {
 		StopPrice(stopPrice).WorkingType(workingType).ActivationPrice(activationPrice).
 		CallbackRate(callbackRate).PositionSide(positionSide).
 		PriceProtect(priceProtect).NewOrderResponseType(newOrderResponseType).
-		Do(newContext())
+		SelfTradePreventionMode(stpMode).Do(newContext())
 	s.r().NoError(err)
 	e := &CreateOrderResponse{
 		ClientOrderID:    newClientOrderID,
@@ -133,6 +137,8 @@ func (s *orderServiceTestSuite) TestCreateOrder() {
 		PriceRate:        callbackRate,
 		ClosePosition:    false,
 		PriceProtect:     priceProtect,
+
+		SelfTradePreventionMode: stpMode,
 	}
 	s.assertCreateOrderResponseEqual(e, res)
 }
@@ -159,6 +165,7 @@ func (s *baseOrderTestSuite) assertCreateOrderResponseEqual(e, a *CreateOrderRes
 	r.Equal(e.ActivatePrice, a.ActivatePrice, "ActivatePrice")
 	r.Equal(e.PriceRate, a.PriceRate, "PriceRate")
 	r.Equal(e.ClosePosition, a.ClosePosition, "ClosePosition")
+	r.Equal(e.SelfTradePreventionMode, a.SelfTradePreventionMode, "SelfTradePreventionMode")
 }
 
 func (s *orderServiceTestSuite) TestListOpenOrders() {
@@ -570,6 +577,28 @@ func (s *orderServiceTestSuite) TestCancelAllOpenOrders() {
 	s.r().NoError(err)
 }
 
+func (s *orderServiceTestSuite) TestCountdownCancelAll() {
+	data := []byte(`{
+		"symbol": "BTCUSDT",
+		"countdownTime": "100000"
+	}`)
+	s.mockDo(data, nil)
+	defer s.assertDo()
+
+	s.assertReq(func(r *request) {
+		e := newSignedRequest().setFormParams(params{
+			"symbol":        "BTCUSDT",
+			"countdownTime": 100000,
+		})
+		s.assertRequestEqual(e, r)
+	})
+
+	res, err := s.client.NewCountdownCancelAllService().Symbol("BTCUSDT").
+		CountdownTime(100000).Do(newContext())
+	s.r().NoError(err)
+	s.r().Equal(&CountdownCancelAllResponse{Symbol: "BTCUSDT", CountdownTime: "100000"}, res)
+}
+
 func (s *orderServiceTestSuite) TestListLiquidationOrders() {
 	data := []byte(`[
 		{
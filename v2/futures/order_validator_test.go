@@ -0,0 +1,86 @@
+package futures
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/vv1zard/go-binance/v2/common"
+)
+
+type orderValidatorTestSuite struct {
+	baseTestSuite
+	validator *OrderValidator
+}
+
+func TestOrderValidator(t *testing.T) {
+	suite.Run(t, new(orderValidatorTestSuite))
+}
+
+func (s *orderValidatorTestSuite) SetupTest() {
+	s.baseTestSuite.SetupTest()
+	s.validator = NewOrderValidator(&ExchangeInfo{
+		Symbols: []Symbol{
+			{
+				Symbol: "BTCUSDT",
+				Filters: []map[string]interface{}{
+					{"filterType": "PRICE_FILTER", "minPrice": "556.80", "maxPrice": "4529764", "tickSize": "0.10"},
+					{"filterType": "LOT_SIZE", "minQty": "0.001", "maxQty": "1000", "stepSize": "0.001"},
+					{"filterType": "MARKET_LOT_SIZE", "minQty": "0.001", "maxQty": "120", "stepSize": "0.001"},
+					{"filterType": "MAX_NUM_ALGO_ORDERS", "limit": float64(10)},
+					{"filterType": "MIN_NOTIONAL", "notional": "100"},
+				},
+			},
+		},
+	})
+}
+
+func (s *orderValidatorTestSuite) assertFilterError(err error, filter SymbolFilterType, param string) {
+	r := s.r()
+	var filterErr *common.FilterError
+	r.True(errors.As(err, &filterErr), "unexpected error %v", err)
+	r.Equal(string(filter), filterErr.Filter)
+	r.Equal(param, filterErr.Param)
+}
+
+func (s *orderValidatorTestSuite) newOrder() *CreateOrderService {
+	return s.client.NewCreateOrderService().Symbol("BTCUSDT").Side(SideTypeBuy).
+		Type(OrderTypeLimit).TimeInForce(TimeInForceTypeGTC)
+}
+
+func (s *orderValidatorTestSuite) TestValidate() {
+	s.r().NoError(s.validator.ValidateOrder(s.newOrder().Price("43187.1").Quantity("0.003")))
+
+	err := s.validator.ValidateOrder(s.newOrder().Price("43187.15").Quantity("0.003"))
+	s.assertFilterError(err, SymbolFilterTypePrice, "price")
+
+	err = s.validator.ValidateOrder(s.newOrder().Type(OrderTypeStopMarket).StopPrice("43187.15").Quantity("0.003"))
+	s.assertFilterError(err, SymbolFilterTypePrice, "stopPrice")
+
+	err = s.validator.ValidateOrder(s.newOrder().Type(OrderTypeMarket).Quantity("121"))
+	s.assertFilterError(err, SymbolFilterTypeMarketLotSize, "quantity")
+
+	err = s.validator.ValidateOrder(s.newOrder().Price("43187.1").Quantity("0.002"))
+	s.assertFilterError(err, SymbolFilterTypeMinNotional, "notional")
+	s.r().NoError(s.validator.ValidateOrder(s.newOrder().Price("43187.1").Quantity("0.002").ReduceOnly(true)))
+
+	// A close position order has no quantity
+	s.r().NoError(s.validator.ValidateOrder(s.newOrder().Type(OrderTypeStopMarket).StopPrice("43187.1").ClosePosition(true)))
+}
+
+func (s *orderValidatorTestSuite) TestRound() {
+	s.validator.Round = true
+	order := s.newOrder().Side(SideTypeSell).Price("43187.11").Quantity("0.0039")
+	s.r().NoError(s.validator.ValidateOrder(order))
+	s.r().Equal("43187.2", *order.price)
+	s.r().Equal("0.003", order.quantity)
+}
+
+func (s *orderValidatorTestSuite) TestMaxNumAlgoOrders() {
+	s.validator.OpenAlgoOrders = func(symbol string) int {
+		return 10
+	}
+	err := s.validator.ValidateOrder(s.newOrder().Type(OrderTypeStopMarket).StopPrice("43187.1").Quantity("0.003"))
+	s.assertFilterError(err, SymbolFilterTypeMaxNumAlgoOrders, "openAlgoOrders")
+	s.r().NoError(s.validator.ValidateOrder(s.newOrder().Price("43187.1").Quantity("0.003")))
+}
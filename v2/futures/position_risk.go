@@ -4,6 +4,8 @@ import (
 	"context"
 	"encoding/json"
 	"net/http"
+
+	"github.com/vv1zard/go-binance/v2/common"
 )
 
 // GetPositionRiskService get account balance
@@ -20,15 +22,7 @@ func (s *GetPositionRiskService) Symbol(symbol string) *GetPositionRiskService {
 
 // Do send request
 func (s *GetPositionRiskService) Do(ctx context.Context, opts ...RequestOption) (res []*PositionRisk, err error) {
-	r := &request{
-		method:   http.MethodGet,
-		endpoint: "/fapi/v2/positionRisk",
-		secType:  secTypeSigned,
-	}
-	if s.symbol != "" {
-		r.setParam("symbol", s.symbol)
-	}
-	data, _, err := s.c.callAPI(ctx, r, opts...)
+	data, _, err := s.c.callAPI(ctx, s.buildRequest(), opts...)
 	if err != nil {
 		return []*PositionRisk{}, err
 	}
@@ -40,6 +34,18 @@ func (s *GetPositionRiskService) Do(ctx context.Context, opts ...RequestOption)
 	return res, nil
 }
 
+func (s *GetPositionRiskService) buildRequest() *request {
+	r := &request{
+		method:   http.MethodGet,
+		endpoint: "/fapi/v2/positionRisk",
+		secType:  secTypeSigned,
+	}
+	if s.symbol != "" {
+		r.setParam("symbol", s.symbol)
+	}
+	return r
+}
+
 // PositionRisk define position risk info
 type PositionRisk struct {
 	EntryPrice       string `json:"entryPrice"`
@@ -57,3 +63,33 @@ type PositionRisk struct {
 	Notional         string `json:"notional"`
 	IsolatedWallet   string `json:"isolatedWallet"`
 }
+
+// EntryPriceDecimal parse EntryPrice as an exact decimal
+func (p *PositionRisk) EntryPriceDecimal() (common.Decimal, error) {
+	return common.ParseDecimal(p.EntryPrice)
+}
+
+// MarkPriceDecimal parse MarkPrice as an exact decimal
+func (p *PositionRisk) MarkPriceDecimal() (common.Decimal, error) {
+	return common.ParseDecimal(p.MarkPrice)
+}
+
+// PositionAmtDecimal parse PositionAmt as an exact decimal
+func (p *PositionRisk) PositionAmtDecimal() (common.Decimal, error) {
+	return common.ParseDecimal(p.PositionAmt)
+}
+
+// UnRealizedProfitDecimal parse UnRealizedProfit as an exact decimal
+func (p *PositionRisk) UnRealizedProfitDecimal() (common.Decimal, error) {
+	return common.ParseDecimal(p.UnRealizedProfit)
+}
+
+// LiquidationPriceDecimal parse LiquidationPrice as an exact decimal
+func (p *PositionRisk) LiquidationPriceDecimal() (common.Decimal, error) {
+	return common.ParseDecimal(p.LiquidationPrice)
+}
+
+// NotionalDecimal parse Notional as an exact decimal
+func (p *PositionRisk) NotionalDecimal() (common.Decimal, error) {
+	return common.ParseDecimal(p.Notional)
+}
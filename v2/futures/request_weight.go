@@ -0,0 +1,109 @@
+package futures
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+)
+
+// requestWeights is the weight of each /fapi endpoint, endpoints missing
+// from the table weigh 1. Weights depending on the parameters are computed
+// by requestWeight.
+var requestWeights = map[string]int64{
+	"GET /fapi/v1/trades":                 5,
+	"GET /fapi/v1/historicalTrades":       20,
+	"GET /fapi/v1/aggTrades":              20,
+	"POST /fapi/v1/order":                 0,
+	"POST /fapi/v1/batchOrders":           5,
+	"GET /fapi/v1/allOrders":              5,
+	"GET /fapi/v1/forceOrders":            20,
+	"GET /fapi/v1/allForceOrders":         20,
+	"GET /fapi/v2/account":                5,
+	"GET /fapi/v2/balance":                5,
+	"GET /fapi/v2/positionRisk":           5,
+	"GET /fapi/v1/userTrades":             5,
+	"GET /fapi/v1/income":                 30,
+	"GET /fapi/v1/commissionRate":         20,
+	"POST /fapi/v1/countdownCancelAll":    10,
+	"GET /fapi/v1/positionSide/dual":      30,
+	"GET /fapi/v1/apiReferral/ifNewUser":  1,
+	"GET /fapi/v1/positionMargin/history": 1,
+}
+
+// orderCounts is the number of orders placed by each endpoint
+var orderCounts = map[string]int64{
+	"POST /fapi/v1/order": 1,
+	"PUT /fapi/v1/order":  1,
+}
+
+// requestWeight return the weight and order count of r, tracked is false for
+// endpoints outside of the /fapi rate limits such as /futures/data
+func requestWeight(r *request) (weight, orders int64, tracked bool) {
+	if !strings.HasPrefix(r.endpoint, "/fapi/") {
+		return 0, 0, false
+	}
+	key := r.method + " " + r.endpoint
+	orders = orderCounts[key]
+	switch key {
+	case "GET /fapi/v1/depth":
+		return depthWeight(r.query.Get("limit")), orders, true
+	case "GET /fapi/v1/klines", "GET /fapi/v1/continuousKlines", "GET /fapi/v1/indexPriceKlines", "GET /fapi/v1/markPriceKlines":
+		return klinesWeight(r.query.Get("limit")), orders, true
+	case "GET /fapi/v1/ticker/24hr", "GET /fapi/v1/openOrders":
+		return symbolWeight(r, 1, 40), orders, true
+	case "GET /fapi/v1/ticker/price":
+		return symbolWeight(r, 1, 2), orders, true
+	case "GET /fapi/v1/ticker/bookTicker":
+		return symbolWeight(r, 2, 5), orders, true
+	case "POST /fapi/v1/batchOrders":
+		var batch []json.RawMessage
+		if json.Unmarshal([]byte(r.form.Get("batchOrders")), &batch) == nil {
+			orders = int64(len(batch))
+		}
+	}
+	weight, ok := requestWeights[key]
+	if !ok {
+		weight = 1
+	}
+	return weight, orders, true
+}
+
+func depthWeight(limit string) int64 {
+	n, err := strconv.Atoi(limit)
+	if err != nil {
+		n = 500
+	}
+	switch {
+	case n <= 50:
+		return 2
+	case n <= 100:
+		return 5
+	case n <= 500:
+		return 10
+	}
+	return 20
+}
+
+func klinesWeight(limit string) int64 {
+	n, err := strconv.Atoi(limit)
+	if err != nil {
+		n = 500
+	}
+	switch {
+	case n < 100:
+		return 1
+	case n < 500:
+		return 2
+	case n <= 1000:
+		return 5
+	}
+	return 10
+}
+
+// symbolWeight return single when the request is for one symbol and all otherwise
+func symbolWeight(r *request, single, all int64) int64 {
+	if r.query.Get("symbol") != "" {
+		return single
+	}
+	return all
+}
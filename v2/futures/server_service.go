@@ -3,6 +3,8 @@ package futures
 import (
 	"context"
 	"net/http"
+
+	"github.com/vv1zard/go-binance/v2/common"
 )
 
 // PingService ping server
@@ -58,3 +60,11 @@ func (s *SetServerTimeService) Do(ctx context.Context, opts ...RequestOption) (t
 	s.c.TimeOffset = timeOffset
 	return timeOffset, nil
 }
+
+// NewClockSync init a clock sync measuring the offset with ServerTimeService,
+// assign it to Client.ClockSync and start it to keep signed requests in the recvWindow
+func (c *Client) NewClockSync() *common.ClockSync {
+	return common.NewClockSync(func(ctx context.Context) (int64, error) {
+		return c.NewServerTimeService().Do(ctx)
+	})
+}
@@ -4,6 +4,9 @@ import (
 	"context"
 	"encoding/json"
 	"net/http"
+	"time"
+
+	"github.com/vv1zard/go-binance/v2/common"
 )
 
 // HistoricalTradesService trades
@@ -156,6 +159,38 @@ func (s *AggTradesService) Do(ctx context.Context, opts ...RequestOption) (res [
 	return res, nil
 }
 
+// Iterate walk the aggregate trades of the symbol from StartTime, or from
+// FromID, up to EndTime with as many requests as needed, Limit sets the page
+// size
+func (s *AggTradesService) Iterate(opts ...RequestOption) *common.Iterator[*AggTrade] {
+	p := common.Pager[*AggTrade]{
+		Fetch: func(ctx context.Context, q common.PageQuery) ([]*AggTrade, error) {
+			page := &AggTradesService{c: s.c, symbol: s.symbol, limit: &q.Limit}
+			if q.FromID != nil {
+				page.fromID = q.FromID
+			} else {
+				page.startTime, page.endTime = &q.StartTime, &q.EndTime
+			}
+			return page.Do(ctx, opts...)
+		},
+		Limit:     1000,
+		MaxWindow: time.Hour,
+		Time:      func(trade *AggTrade) int64 { return trade.Timestamp },
+		ID:        func(trade *AggTrade) int64 { return trade.AggTradeID },
+		FromID:    s.fromID,
+	}
+	if s.limit != nil {
+		p.Limit = *s.limit
+	}
+	if s.startTime != nil {
+		p.StartTime = *s.startTime
+	}
+	if s.endTime != nil {
+		p.EndTime = *s.endTime
+	}
+	return p.Iterator()
+}
+
 // AggTrade define aggregate trade info
 type AggTrade struct {
 	AggTradeID   int64  `json:"a"`
@@ -280,6 +315,37 @@ func (s *ListAccountTradeService) Do(ctx context.Context, opts ...RequestOption)
 	return res, nil
 }
 
+// Iterate walk the trades of the symbol from StartTime, or from FromID, up to
+// EndTime with as many requests as needed, Limit sets the page size
+func (s *ListAccountTradeService) Iterate(opts ...RequestOption) *common.Iterator[*AccountTrade] {
+	p := common.Pager[*AccountTrade]{
+		Fetch: func(ctx context.Context, q common.PageQuery) ([]*AccountTrade, error) {
+			page := &ListAccountTradeService{c: s.c, symbol: s.symbol, limit: &q.Limit}
+			if q.FromID != nil {
+				page.fromID = q.FromID
+			} else {
+				page.startTime, page.endTime = &q.StartTime, &q.EndTime
+			}
+			return page.Do(ctx, opts...)
+		},
+		Limit:     1000,
+		MaxWindow: 7 * 24 * time.Hour,
+		Time:      func(trade *AccountTrade) int64 { return trade.Time },
+		ID:        func(trade *AccountTrade) int64 { return trade.ID },
+		FromID:    s.fromID,
+	}
+	if s.limit != nil {
+		p.Limit = *s.limit
+	}
+	if s.startTime != nil {
+		p.StartTime = *s.startTime
+	}
+	if s.endTime != nil {
+		p.EndTime = *s.endTime
+	}
+	return p.Iterator()
+}
+
 // AccountTrade define account trade
 type AccountTrade struct {
 	Buyer           bool             `json:"buyer"`
@@ -297,3 +363,28 @@ type AccountTrade struct {
 	Symbol          string           `json:"symbol"`
 	Time            int64            `json:"time"`
 }
+
+// PriceDecimal parse Price as an exact decimal
+func (a *AccountTrade) PriceDecimal() (common.Decimal, error) {
+	return common.ParseDecimal(a.Price)
+}
+
+// QuantityDecimal parse Quantity as an exact decimal
+func (a *AccountTrade) QuantityDecimal() (common.Decimal, error) {
+	return common.ParseDecimal(a.Quantity)
+}
+
+// QuoteQuantityDecimal parse QuoteQuantity as an exact decimal
+func (a *AccountTrade) QuoteQuantityDecimal() (common.Decimal, error) {
+	return common.ParseDecimal(a.QuoteQuantity)
+}
+
+// CommissionDecimal parse Commission as an exact decimal
+func (a *AccountTrade) CommissionDecimal() (common.Decimal, error) {
+	return common.ParseDecimal(a.Commission)
+}
+
+// RealizedPnlDecimal parse RealizedPnl as an exact decimal
+func (a *AccountTrade) RealizedPnlDecimal() (common.Decimal, error) {
+	return common.ParseDecimal(a.RealizedPnl)
+}
@@ -1,9 +1,13 @@
 package futures
 
 import (
+	"errors"
+	"log/slog"
+	"sync/atomic"
 	"time"
 
 	"github.com/gorilla/websocket"
+	"github.com/vv1zard/go-binance/v2/common"
 )
 
 // WsHandler handle raw websocket message
@@ -12,27 +16,106 @@ type WsHandler func(message []byte)
 // ErrHandler handles errors
 type ErrHandler func(err error)
 
+// ErrWsConnectionExpired is reported when a connection is recycled because it
+// reached WsReconnectConfig.MaxConnectionAge, ahead of Binance's 24h forced close
+var ErrWsConnectionExpired = errors.New("websocket connection reached its maximum age")
+
+// ErrWsReconnectExhausted is reported when a stream gives up after
+// WsReconnectConfig.MaxRetries failed reconnection attempts
+var ErrWsReconnectExhausted = errors.New("websocket reconnection attempts exhausted")
+
+// WsReconnectConfig define how a stream is re-established after it drops.
+// The stream set is encoded in the endpoint, so every reconnection subscribes
+// to exactly the same streams as the original connection.
+type WsReconnectConfig struct {
+	// MinBackoff is the delay before the first reconnection attempt
+	MinBackoff time.Duration
+	// MaxBackoff caps the exponentially growing delay between attempts
+	MaxBackoff time.Duration
+	// MaxRetries is the number of consecutive failed attempts before giving up, 0 means forever
+	MaxRetries int
+	// MaxConnectionAge recycles a connection before Binance closes it after 24h, 0 disables it
+	MaxConnectionAge time.Duration
+	// OnConnected is called after the initial connection is established
+	OnConnected func(endpoint string)
+	// OnDisconnected is called every time a connection is lost or recycled
+	OnDisconnected func(endpoint string, err error)
+	// OnReconnected is called after a connection is re-established
+	OnReconnected func(endpoint string, attempts int)
+}
+
+// NewWsReconnectConfig create a reconnect configuration with sensible defaults
+func NewWsReconnectConfig() *WsReconnectConfig {
+	return &WsReconnectConfig{
+		MinBackoff:       time.Second,
+		MaxBackoff:       time.Minute,
+		MaxConnectionAge: 23*time.Hour + 50*time.Minute,
+	}
+}
+
+func (rc *WsReconnectConfig) connected(endpoint string) {
+	if rc.OnConnected != nil {
+		rc.OnConnected(endpoint)
+	}
+}
+
+func (rc *WsReconnectConfig) disconnected(endpoint string, err error) {
+	if rc.OnDisconnected != nil {
+		rc.OnDisconnected(endpoint, err)
+	}
+}
+
+func (rc *WsReconnectConfig) reconnected(endpoint string, attempts int) {
+	if rc.OnReconnected != nil {
+		rc.OnReconnected(endpoint, attempts)
+	}
+}
+
 // WsConfig webservice configuration
 type WsConfig struct {
 	Endpoint string
+	// Reconnect enables automatic reconnection when not nil
+	Reconnect *WsReconnectConfig
+	// LogHandler receives the connection events of the stream, with listen
+	// keys redacted
+	LogHandler slog.Handler
+	// Metrics receives the message count, event lag and reconnections of the
+	// stream
+	Metrics common.Metrics
 }
 
 func newWsConfig(endpoint string) *WsConfig {
 	return &WsConfig{
-		Endpoint: endpoint,
+		Endpoint:   endpoint,
+		Reconnect:  WebsocketReconnect,
+		LogHandler: WebsocketLogHandler,
+		Metrics:    WebsocketMetrics,
 	}
 }
 
-var wsServe = func(cfg *WsConfig, handler WsHandler, errHandler ErrHandler) (doneC, stopC chan struct{}, err error) {
-
+func wsDial(endpoint string) (*websocket.Conn, error) {
 	defaultDialer := websocket.DefaultDialer
 	defaultDialer.EnableCompression = false
-	c, _, err := defaultDialer.Dial(cfg.Endpoint, nil)
+	c, _, err := defaultDialer.Dial(endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	c.SetReadLimit(655350)
+	return c, nil
+}
 
+var wsServe = func(cfg *WsConfig, handler WsHandler, errHandler ErrHandler) (doneC, stopC chan struct{}, err error) {
+	handler = observeWsHandler(cfg, handler)
+	if cfg.Reconnect != nil {
+		return wsServeWithReconnect(cfg, handler, errHandler)
+	}
+	logger := common.NewLogger(cfg.LogHandler).With("stream", cfg.Endpoint)
+	c, err := wsDial(cfg.Endpoint)
 	if err != nil {
+		logger.Error("websocket dial failed", "error", err)
 		return nil, nil, err
 	}
-	c.SetReadLimit(655350)
+	logger.Info("websocket connected")
 	doneC = make(chan struct{})
 	stopC = make(chan struct{})
 	go func() {
@@ -40,54 +123,174 @@ var wsServe = func(cfg *WsConfig, handler WsHandler, errHandler ErrHandler) (don
 		// websocket.Conn.ReadMessage or when the stopC channel is
 		// closed by the client.
 		defer close(doneC)
-		if WebsocketKeepalive {
-			keepAlive(c, WebsocketTimeout)
+		err := wsServeConn(c, 0, handler, stopC)
+		if err != nil {
+			logger.Warn("websocket disconnected", "error", err)
+			errHandler(err)
+			return
 		}
-		// Wait for the stopC channel to be closed.  We do that in a
-		// separate goroutine because ReadMessage is a blocking
-		// operation.
-		silent := false
-		go func() {
-			select {
-			case <-stopC:
-				silent = true
-			case <-doneC:
-			}
-			c.Close()
-		}()
+		logger.Info("websocket stopped")
+	}()
+	return
+}
+
+// observeWsHandler report the messages of the stream to cfg.Metrics when set
+func observeWsHandler(cfg *WsConfig, handler WsHandler) WsHandler {
+	if cfg.Metrics == nil {
+		return handler
+	}
+	stream := common.StreamName(cfg.Endpoint)
+	return func(message []byte) {
+		common.RecordMessage(cfg.Metrics, stream, message)
+		handler(message)
+	}
+}
+
+// wsServeWithReconnect behaves like wsServe but redials the endpoint with
+// exponential backoff whenever the connection drops. Every disconnection is
+// reported to errHandler; doneC is only closed when stopC is closed or when
+// the retries are exhausted.
+func wsServeWithReconnect(cfg *WsConfig, handler WsHandler, errHandler ErrHandler) (doneC, stopC chan struct{}, err error) {
+	rc := cfg.Reconnect
+	logger := common.NewLogger(cfg.LogHandler).With("stream", cfg.Endpoint)
+	c, err := wsDial(cfg.Endpoint)
+	if err != nil {
+		logger.Error("websocket dial failed", "error", err)
+		return nil, nil, err
+	}
+	logger.Info("websocket connected")
+	rc.connected(cfg.Endpoint)
+	doneC = make(chan struct{})
+	stopC = make(chan struct{})
+	go func() {
+		defer close(doneC)
 		for {
-			_, message, err := c.ReadMessage()
-			if err != nil {
-				if !silent {
-					errHandler(err)
-				}
+			err := wsServeConn(c, rc.MaxConnectionAge, handler, stopC)
+			if err == nil {
+				logger.Info("websocket stopped")
 				return
 			}
-			handler(message)
+			if errors.Is(err, ErrWsConnectionExpired) {
+				logger.Info("websocket recycled", "error", err)
+			} else {
+				logger.Warn("websocket disconnected", "error", err)
+			}
+			rc.disconnected(cfg.Endpoint, err)
+			errHandler(err)
+			var attempts int
+			c, attempts = wsRedial(cfg, errors.Is(err, ErrWsConnectionExpired), logger, errHandler, stopC)
+			if c == nil {
+				return
+			}
+			logger.Info("websocket reconnected", "attempts", attempts)
+			common.RecordReconnect(cfg.Metrics, common.StreamName(cfg.Endpoint))
+			rc.reconnected(cfg.Endpoint, attempts)
 		}
 	}()
 	return
 }
 
+// wsRedial dials cfg.Endpoint until it succeeds, backing off between failed
+// attempts. A recycled connection is replaced right away, a dropped one waits
+// for the backoff before the first attempt. It returns a nil connection when
+// stopC is closed or the retries are exhausted.
+func wsRedial(cfg *WsConfig, immediate bool, logger *slog.Logger, errHandler ErrHandler, stopC chan struct{}) (*websocket.Conn, int) {
+	rc := cfg.Reconnect
+	attempts := 0
+	for {
+		if !immediate {
+			attempts++
+			if rc.MaxRetries > 0 && attempts > rc.MaxRetries {
+				logger.Error("websocket reconnection exhausted", "attempts", attempts-1)
+				errHandler(ErrWsReconnectExhausted)
+				return nil, attempts
+			}
+			select {
+			case <-stopC:
+				return nil, attempts
+			case <-time.After(common.Backoff(attempts, rc.MinBackoff, rc.MaxBackoff)):
+			}
+		}
+		immediate = false
+		c, err := wsDial(cfg.Endpoint)
+		if err == nil {
+			return c, attempts
+		}
+		logger.Warn("websocket reconnection failed", "attempts", attempts, "error", err)
+		errHandler(err)
+	}
+}
+
+// wsServeConn reads messages from c until it fails, maxAge elapses or stopC
+// is closed, in which case it returns nil.
+func wsServeConn(c *websocket.Conn, maxAge time.Duration, handler WsHandler, stopC chan struct{}) error {
+	if WebsocketKeepalive {
+		keepAlive(c, WebsocketTimeout)
+	}
+	return wsReadConn(c, maxAge, handler, stopC)
+}
+
+// wsReadConn is wsServeConn without the keepalive, for the connections
+// pinged by their owner
+func wsReadConn(c *websocket.Conn, maxAge time.Duration, handler WsHandler, stopC chan struct{}) error {
+	// Wait for the stopC channel to be closed.  We do that in a
+	// separate goroutine because ReadMessage is a blocking
+	// operation.
+	connDoneC := make(chan struct{})
+	defer close(connDoneC)
+	reasonC := make(chan error, 1)
+	go func() {
+		var expireC <-chan time.Time
+		if maxAge > 0 {
+			timer := time.NewTimer(maxAge)
+			defer timer.Stop()
+			expireC = timer.C
+		}
+		select {
+		case <-stopC:
+			reasonC <- nil
+		case <-expireC:
+			reasonC <- ErrWsConnectionExpired
+		case <-connDoneC:
+		}
+		c.Close()
+	}()
+	for {
+		_, message, err := c.ReadMessage()
+		if err != nil {
+			select {
+			case reason := <-reasonC:
+				return reason
+			default:
+				return err
+			}
+		}
+		handler(message)
+	}
+}
+
 func keepAlive(c *websocket.Conn, timeout time.Duration) {
 	ticker := time.NewTicker(timeout)
 
-	lastResponse := time.Now()
+	// The pong handler runs on the reading goroutine
+	var lastResponse atomic.Int64
 	c.SetPongHandler(func(msg string) error {
-		lastResponse = time.Now()
+		lastResponse.Store(time.Now().UnixNano())
 		return nil
 	})
 
 	go func() {
 		defer ticker.Stop()
 		for {
+			sent := time.Now().UnixNano()
 			deadline := time.Now().Add(10 * time.Second)
 			err := c.WriteControl(websocket.PingMessage, []byte{}, deadline)
 			if err != nil {
 				return
 			}
 			<-ticker.C
-			if time.Since(lastResponse) > timeout {
+			// The last ping was not answered within timeout
+			if lastResponse.Load() < sent {
 				c.Close()
 				return
 			}
@@ -0,0 +1,235 @@
+package futures
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/suite"
+	"github.com/vv1zard/go-binance/v2/common"
+)
+
+type wsAPIClientTestSuite struct {
+	suite.Suite
+	server   *httptest.Server
+	mu       sync.Mutex
+	requests []*wsAPIRequest
+	conns    []*websocket.Conn
+	results  map[string]string
+	client   *Client
+	ws       *WsAPIClient
+}
+
+func TestWsAPIClient(t *testing.T) {
+	suite.Run(t, new(wsAPIClientTestSuite))
+}
+
+// SetupTest starts a server answering each method with the result set in
+// s.results, or with an error for unknown methods. A "slow" method is never
+// answered.
+func (s *wsAPIClientTestSuite) SetupTest() {
+	s.requests = nil
+	s.conns = nil
+	s.results = map[string]string{}
+	upgrader := websocket.Upgrader{}
+	s.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer c.Close()
+		s.mu.Lock()
+		s.conns = append(s.conns, c)
+		s.mu.Unlock()
+		for {
+			req := new(wsAPIRequest)
+			err := c.ReadJSON(req)
+			if err != nil {
+				return
+			}
+			s.mu.Lock()
+			s.requests = append(s.requests, req)
+			result, ok := s.results[req.Method]
+			s.mu.Unlock()
+			switch {
+			case req.Method == "slow":
+			case ok:
+				c.WriteMessage(websocket.TextMessage, []byte(fmt.Sprintf(`{"id":%q,"status":200,"result":%s}`, req.ID, result)))
+			default:
+				c.WriteMessage(websocket.TextMessage, []byte(fmt.Sprintf(`{"id":%q,"status":400,"error":{"code":-1102,"msg":"Mandatory parameter was not sent."}}`, req.ID)))
+			}
+		}
+	}))
+	s.client = NewClient("dummyAPIKey", "dummySecretKey")
+	s.ws = s.client.NewWsAPIClient()
+	s.ws.Endpoint = "ws" + strings.TrimPrefix(s.server.URL, "http")
+}
+
+func (s *wsAPIClientTestSuite) TearDownTest() {
+	s.ws.Close()
+	s.server.Close()
+}
+
+func (s *wsAPIClientTestSuite) lastRequest() *wsAPIRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requests[len(s.requests)-1]
+}
+
+func (s *wsAPIClientTestSuite) TestPlaceOrder() {
+	s.results["order.place"] = `{
+		"orderId": 325078477,
+		"symbol": "BTCUSDT",
+		"status": "NEW",
+		"clientOrderId": "iCXL1BywlBaf2sesNUrVl3",
+		"price": "43187.00",
+		"avgPrice": "0.00",
+		"origQty": "0.100",
+		"executedQty": "0.000",
+		"cumQuote": "0.00000",
+		"timeInForce": "GTC",
+		"type": "LIMIT",
+		"reduceOnly": false,
+		"side": "BUY",
+		"positionSide": "BOTH",
+		"updateTime": 1702555534435
+	}`
+	order := s.client.NewCreateOrderService().Symbol("BTCUSDT").Side(SideTypeBuy).
+		Type(OrderTypeLimit).TimeInForce(TimeInForceTypeGTC).Quantity("0.100").Price("43187.00")
+	res, err := s.ws.PlaceOrder(context.Background(), order, WithRecvWindow(5000))
+	s.Require().NoError(err)
+	s.Equal(int64(325078477), res.OrderID)
+	s.Equal(OrderStatusTypeNew, res.Status)
+	s.Equal(PositionSideTypeBoth, res.PositionSide)
+
+	req := s.lastRequest()
+	s.Equal("order.place", req.Method)
+	s.Equal("dummyAPIKey", req.Params["apiKey"])
+	s.Equal("BTCUSDT", req.Params["symbol"])
+	s.Equal(float64(5000), req.Params["recvWindow"])
+
+	// The signature covers every other parameter sorted by name
+	keys := []string{}
+	for key := range req.Params {
+		if key != signatureKey {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	pairs := []string{}
+	for _, key := range keys {
+		value := req.Params[key]
+		if n, ok := value.(float64); ok {
+			value = strconv.FormatFloat(n, 'f', -1, 64)
+		}
+		pairs = append(pairs, fmt.Sprintf("%s=%v", key, value))
+	}
+	signature, err := common.NewHMACSigner("dummySecretKey").Sign([]byte(strings.Join(pairs, "&")))
+	s.Require().NoError(err)
+	s.Equal(signature, req.Params[signatureKey])
+}
+
+func (s *wsAPIClientTestSuite) TestQueries() {
+	s.results["order.modify"] = `{"orderId":328971409,"symbol":"BTCUSDT","status":"NEW","price":"43769.10","origQty":"0.002","side":"BUY"}`
+	s.results["order.status"] = `{"symbol":"BTCUSDT","orderId":328971409,"status":"FILLED"}`
+	s.results["order.cancel"] = `{"symbol":"BTCUSDT","orderId":328971409,"status":"CANCELED"}`
+	s.results["account.position"] = `[{"symbol":"BTCUSDT","positionAmt":"0.002","entryPrice":"43769.1","positionSide":"BOTH"}]`
+	s.results["account.balance"] = `[{"accountAlias":"SgsR","asset":"USDT","balance":"122607.35137903","availableBalance":"122617.35137903"}]`
+	ctx := context.Background()
+
+	modified, err := s.ws.ModifyOrder(ctx, s.client.NewModifyOrderService().Symbol("BTCUSDT").
+		OrderID(328971409).Side(SideTypeBuy).Price("43769.10").Quantity("0.002"))
+	s.Require().NoError(err)
+	s.Equal("43769.10", modified.Price)
+	s.Equal("order.modify", s.lastRequest().Method)
+	s.Equal(float64(328971409), s.lastRequest().Params["orderId"])
+
+	order, err := s.ws.GetOrder(ctx, s.client.NewGetOrderService().Symbol("BTCUSDT").OrderID(328971409))
+	s.Require().NoError(err)
+	s.Equal(OrderStatusTypeFilled, order.Status)
+
+	canceled, err := s.ws.CancelOrder(ctx, s.client.NewCancelOrderService().Symbol("BTCUSDT").OrderID(328971409))
+	s.Require().NoError(err)
+	s.Equal(OrderStatusTypeCanceled, canceled.Status)
+
+	positions, err := s.ws.AccountPosition(ctx, s.client.NewGetPositionRiskService().Symbol("BTCUSDT"))
+	s.Require().NoError(err)
+	s.Len(positions, 1)
+	s.Equal("0.002", positions[0].PositionAmt)
+	s.Equal("BTCUSDT", s.lastRequest().Params["symbol"])
+
+	balances, err := s.ws.AccountBalance(ctx)
+	s.Require().NoError(err)
+	s.Equal("USDT", balances[0].Asset)
+	s.Equal("122617.35137903", balances[0].AvailableBalance)
+
+	s.mu.Lock()
+	s.Len(s.conns, 1)
+	s.mu.Unlock()
+}
+
+func (s *wsAPIClientTestSuite) TestError() {
+	_, err := s.ws.GetOrder(context.Background(), s.client.NewGetOrderService().Symbol("BTCUSDT"))
+	s.Require().Error(err)
+	s.True(common.IsAPIError(err))
+	s.Equal(common.CodeMandatoryParamEmpty, err.(*common.APIError).Code)
+}
+
+func (s *wsAPIClientTestSuite) TestTimeout() {
+	s.ws.Timeout = 50 * time.Millisecond
+	_, err := s.ws.Call(context.Background(), "slow", nil)
+	s.Equal(context.DeadlineExceeded, err)
+}
+
+func (s *wsAPIClientTestSuite) TestSessionLogon() {
+	pub, key, err := ed25519.GenerateKey(rand.Reader)
+	s.Require().NoError(err)
+	s.client.Signer = &common.Ed25519Signer{PrivateKey: key}
+	s.results["session.logon"] = `{"apiKey":"dummyAPIKey","authorizedSince":1649729878532,"connectedSince":1649729873021,"serverTime":1649729878630}`
+	s.results["account.balance"] = `[]`
+
+	status, err := s.ws.SessionLogon(context.Background())
+	s.Require().NoError(err)
+	s.Equal(int64(1649729878532), status.AuthorizedSince)
+	req := s.lastRequest()
+	s.Len(req.Params, 3)
+	signature, err := base64.StdEncoding.DecodeString(req.Params[signatureKey].(string))
+	s.Require().NoError(err)
+	payload := fmt.Sprintf("apiKey=dummyAPIKey&timestamp=%.0f", req.Params[timestampKey])
+	s.True(ed25519.Verify(pub, []byte(payload), signature))
+
+	_, err = s.ws.AccountBalance(context.Background())
+	s.Require().NoError(err)
+	req = s.lastRequest()
+	s.Nil(req.Params["apiKey"])
+	s.Nil(req.Params[signatureKey])
+	s.NotNil(req.Params[timestampKey])
+
+	// A new connection logs on again before the request
+	s.mu.Lock()
+	s.conns[0].Close()
+	s.mu.Unlock()
+	s.Eventually(func() bool {
+		return s.ws.conn.isDone()
+	}, time.Second, 10*time.Millisecond)
+	_, err = s.ws.AccountBalance(context.Background())
+	s.Require().NoError(err)
+	s.mu.Lock()
+	methods := []string{}
+	for _, req := range s.requests {
+		methods = append(methods, req.Method)
+	}
+	s.mu.Unlock()
+	s.Equal([]string{"session.logon", "account.balance", "session.logon", "account.balance"}, methods)
+}
@@ -0,0 +1,204 @@
+package futures
+
+import (
+	"context"
+	"time"
+
+	"github.com/vv1zard/go-binance/v2/common"
+)
+
+// WsAggTradeStream is WsAggTradeServe delivering events on a channel until ctx is done
+func WsAggTradeStream(ctx context.Context, symbol string, opts ...common.StreamOption) (*common.Stream[*WsAggTradeEvent], error) {
+	return common.NewStream[*WsAggTradeEvent](ctx, func(handler func(*WsAggTradeEvent), errHandler func(error)) (doneC, stopC chan struct{}, err error) {
+		return WsAggTradeServe(symbol, handler, errHandler)
+	}, opts...)
+}
+
+// WsCombinedAggTradeStream is WsCombinedAggTradeServe delivering events on a channel until ctx is done
+func WsCombinedAggTradeStream(ctx context.Context, symbols []string, opts ...common.StreamOption) (*common.Stream[*WsAggTradeEvent], error) {
+	return common.NewStream[*WsAggTradeEvent](ctx, func(handler func(*WsAggTradeEvent), errHandler func(error)) (doneC, stopC chan struct{}, err error) {
+		return WsCombinedAggTradeServe(symbols, handler, errHandler)
+	}, opts...)
+}
+
+// WsTradeStream is WsTradeServe delivering events on a channel until ctx is done
+func WsTradeStream(ctx context.Context, symbol string, opts ...common.StreamOption) (*common.Stream[*WsTradeEvent], error) {
+	return common.NewStream[*WsTradeEvent](ctx, func(handler func(*WsTradeEvent), errHandler func(error)) (doneC, stopC chan struct{}, err error) {
+		return WsTradeServe(symbol, handler, errHandler)
+	}, opts...)
+}
+
+// WsCombinedTradeStream is WsCombinedTradeServe delivering events on a channel until ctx is done
+func WsCombinedTradeStream(ctx context.Context, symbols []string, opts ...common.StreamOption) (*common.Stream[*WsTradeEvent], error) {
+	return common.NewStream[*WsTradeEvent](ctx, func(handler func(*WsTradeEvent), errHandler func(error)) (doneC, stopC chan struct{}, err error) {
+		return WsCombinedTradeServe(symbols, handler, errHandler)
+	}, opts...)
+}
+
+// WsMarkPriceStream is WsMarkPriceServe delivering events on a channel until ctx is done
+func WsMarkPriceStream(ctx context.Context, symbol string, opts ...common.StreamOption) (*common.Stream[*WsMarkPriceEvent], error) {
+	return common.NewStream[*WsMarkPriceEvent](ctx, func(handler func(*WsMarkPriceEvent), errHandler func(error)) (doneC, stopC chan struct{}, err error) {
+		return WsMarkPriceServe(symbol, handler, errHandler)
+	}, opts...)
+}
+
+// WsMarkPriceStreamWithRate is WsMarkPriceServeWithRate delivering events on a channel until ctx is done
+func WsMarkPriceStreamWithRate(ctx context.Context, symbol string, rate time.Duration, opts ...common.StreamOption) (*common.Stream[*WsMarkPriceEvent], error) {
+	return common.NewStream[*WsMarkPriceEvent](ctx, func(handler func(*WsMarkPriceEvent), errHandler func(error)) (doneC, stopC chan struct{}, err error) {
+		return WsMarkPriceServeWithRate(symbol, rate, handler, errHandler)
+	}, opts...)
+}
+
+// WsAllMarkPriceStream is WsAllMarkPriceServe delivering events on a channel until ctx is done
+func WsAllMarkPriceStream(ctx context.Context, opts ...common.StreamOption) (*common.Stream[WsAllMarkPriceEvent], error) {
+	return common.NewStream[WsAllMarkPriceEvent](ctx, func(handler func(WsAllMarkPriceEvent), errHandler func(error)) (doneC, stopC chan struct{}, err error) {
+		return WsAllMarkPriceServe(handler, errHandler)
+	}, opts...)
+}
+
+// WsAllMarkPriceStreamWithRate is WsAllMarkPriceServeWithRate delivering events on a channel until ctx is done
+func WsAllMarkPriceStreamWithRate(ctx context.Context, rate time.Duration, opts ...common.StreamOption) (*common.Stream[WsAllMarkPriceEvent], error) {
+	return common.NewStream[WsAllMarkPriceEvent](ctx, func(handler func(WsAllMarkPriceEvent), errHandler func(error)) (doneC, stopC chan struct{}, err error) {
+		return WsAllMarkPriceServeWithRate(rate, handler, errHandler)
+	}, opts...)
+}
+
+// WsKlineStream is WsKlineServe delivering events on a channel until ctx is done
+func WsKlineStream(ctx context.Context, symbol string, interval string, opts ...common.StreamOption) (*common.Stream[*WsKlineEvent], error) {
+	return common.NewStream[*WsKlineEvent](ctx, func(handler func(*WsKlineEvent), errHandler func(error)) (doneC, stopC chan struct{}, err error) {
+		return WsKlineServe(symbol, interval, handler, errHandler)
+	}, opts...)
+}
+
+// WsCombinedKlineStream is WsCombinedKlineServe delivering events on a channel until ctx is done
+func WsCombinedKlineStream(ctx context.Context, symbolIntervalPair map[string]string, opts ...common.StreamOption) (*common.Stream[*WsKlineEvent], error) {
+	return common.NewStream[*WsKlineEvent](ctx, func(handler func(*WsKlineEvent), errHandler func(error)) (doneC, stopC chan struct{}, err error) {
+		return WsCombinedKlineServe(symbolIntervalPair, handler, errHandler)
+	}, opts...)
+}
+
+// WsMiniMarketTickerStream is WsMiniMarketTickerServe delivering events on a channel until ctx is done
+func WsMiniMarketTickerStream(ctx context.Context, symbol string, opts ...common.StreamOption) (*common.Stream[*WsMiniMarketTickerEvent], error) {
+	return common.NewStream[*WsMiniMarketTickerEvent](ctx, func(handler func(*WsMiniMarketTickerEvent), errHandler func(error)) (doneC, stopC chan struct{}, err error) {
+		return WsMiniMarketTickerServe(symbol, handler, errHandler)
+	}, opts...)
+}
+
+// WsAllMiniMarketTickerStream is WsAllMiniMarketTickerServe delivering events on a channel until ctx is done
+func WsAllMiniMarketTickerStream(ctx context.Context, opts ...common.StreamOption) (*common.Stream[WsAllMiniMarketTickerEvent], error) {
+	return common.NewStream[WsAllMiniMarketTickerEvent](ctx, func(handler func(WsAllMiniMarketTickerEvent), errHandler func(error)) (doneC, stopC chan struct{}, err error) {
+		return WsAllMiniMarketTickerServe(handler, errHandler)
+	}, opts...)
+}
+
+// WsMarketTickerStream is WsMarketTickerServe delivering events on a channel until ctx is done
+func WsMarketTickerStream(ctx context.Context, symbol string, opts ...common.StreamOption) (*common.Stream[*WsMarketTickerEvent], error) {
+	return common.NewStream[*WsMarketTickerEvent](ctx, func(handler func(*WsMarketTickerEvent), errHandler func(error)) (doneC, stopC chan struct{}, err error) {
+		return WsMarketTickerServe(symbol, handler, errHandler)
+	}, opts...)
+}
+
+// WsAllMarketTickerStream is WsAllMarketTickerServe delivering events on a channel until ctx is done
+func WsAllMarketTickerStream(ctx context.Context, opts ...common.StreamOption) (*common.Stream[WsAllMarketTickerEvent], error) {
+	return common.NewStream[WsAllMarketTickerEvent](ctx, func(handler func(WsAllMarketTickerEvent), errHandler func(error)) (doneC, stopC chan struct{}, err error) {
+		return WsAllMarketTickerServe(handler, errHandler)
+	}, opts...)
+}
+
+// WsBookTickerStream is WsBookTickerServe delivering events on a channel until ctx is done
+func WsBookTickerStream(ctx context.Context, symbol string, opts ...common.StreamOption) (*common.Stream[*WsBookTickerEvent], error) {
+	return common.NewStream[*WsBookTickerEvent](ctx, func(handler func(*WsBookTickerEvent), errHandler func(error)) (doneC, stopC chan struct{}, err error) {
+		return WsBookTickerServe(symbol, handler, errHandler)
+	}, opts...)
+}
+
+// WsAllBookTickerStream is WsAllBookTickerServe delivering events on a channel until ctx is done
+func WsAllBookTickerStream(ctx context.Context, opts ...common.StreamOption) (*common.Stream[*WsBookTickerEvent], error) {
+	return common.NewStream[*WsBookTickerEvent](ctx, func(handler func(*WsBookTickerEvent), errHandler func(error)) (doneC, stopC chan struct{}, err error) {
+		return WsAllBookTickerServe(handler, errHandler)
+	}, opts...)
+}
+
+// WsLiquidationOrderStream is WsLiquidationOrderServe delivering events on a channel until ctx is done
+func WsLiquidationOrderStream(ctx context.Context, symbol string, opts ...common.StreamOption) (*common.Stream[*WsLiquidationOrderEvent], error) {
+	return common.NewStream[*WsLiquidationOrderEvent](ctx, func(handler func(*WsLiquidationOrderEvent), errHandler func(error)) (doneC, stopC chan struct{}, err error) {
+		return WsLiquidationOrderServe(symbol, handler, errHandler)
+	}, opts...)
+}
+
+// WsAllLiquidationOrderStream is WsAllLiquidationOrderServe delivering events on a channel until ctx is done
+func WsAllLiquidationOrderStream(ctx context.Context, opts ...common.StreamOption) (*common.Stream[*WsLiquidationOrderEvent], error) {
+	return common.NewStream[*WsLiquidationOrderEvent](ctx, func(handler func(*WsLiquidationOrderEvent), errHandler func(error)) (doneC, stopC chan struct{}, err error) {
+		return WsAllLiquidationOrderServe(handler, errHandler)
+	}, opts...)
+}
+
+// WsPartialDepthStream is WsPartialDepthServe delivering events on a channel until ctx is done
+func WsPartialDepthStream(ctx context.Context, symbol string, levels int, opts ...common.StreamOption) (*common.Stream[*WsDepthEvent], error) {
+	return common.NewStream[*WsDepthEvent](ctx, func(handler func(*WsDepthEvent), errHandler func(error)) (doneC, stopC chan struct{}, err error) {
+		return WsPartialDepthServe(symbol, levels, handler, errHandler)
+	}, opts...)
+}
+
+// WsPartialDepthStreamWithRate is WsPartialDepthServeWithRate delivering events on a channel until ctx is done
+func WsPartialDepthStreamWithRate(ctx context.Context, symbol string, levels int, rate time.Duration, opts ...common.StreamOption) (*common.Stream[*WsDepthEvent], error) {
+	return common.NewStream[*WsDepthEvent](ctx, func(handler func(*WsDepthEvent), errHandler func(error)) (doneC, stopC chan struct{}, err error) {
+		return WsPartialDepthServeWithRate(symbol, levels, rate, handler, errHandler)
+	}, opts...)
+}
+
+// WsDiffDepthStream is WsDiffDepthServe delivering events on a channel until ctx is done
+func WsDiffDepthStream(ctx context.Context, symbol string, opts ...common.StreamOption) (*common.Stream[*WsDepthEvent], error) {
+	return common.NewStream[*WsDepthEvent](ctx, func(handler func(*WsDepthEvent), errHandler func(error)) (doneC, stopC chan struct{}, err error) {
+		return WsDiffDepthServe(symbol, handler, errHandler)
+	}, opts...)
+}
+
+// WsCombinedDepthStream is WsCombinedDepthServe delivering events on a channel until ctx is done
+func WsCombinedDepthStream(ctx context.Context, symbolLevels map[string]string, opts ...common.StreamOption) (*common.Stream[*WsDepthEvent], error) {
+	return common.NewStream[*WsDepthEvent](ctx, func(handler func(*WsDepthEvent), errHandler func(error)) (doneC, stopC chan struct{}, err error) {
+		return WsCombinedDepthServe(symbolLevels, handler, errHandler)
+	}, opts...)
+}
+
+// WsCombinedDiffDepthStream is WsCombinedDiffDepthServe delivering events on a channel until ctx is done
+func WsCombinedDiffDepthStream(ctx context.Context, symbols []string, opts ...common.StreamOption) (*common.Stream[*WsDepthEvent], error) {
+	return common.NewStream[*WsDepthEvent](ctx, func(handler func(*WsDepthEvent), errHandler func(error)) (doneC, stopC chan struct{}, err error) {
+		return WsCombinedDiffDepthServe(symbols, handler, errHandler)
+	}, opts...)
+}
+
+// WsDiffDepthStreamWithRate is WsDiffDepthServeWithRate delivering events on a channel until ctx is done
+func WsDiffDepthStreamWithRate(ctx context.Context, symbol string, rate time.Duration, opts ...common.StreamOption) (*common.Stream[*WsDepthEvent], error) {
+	return common.NewStream[*WsDepthEvent](ctx, func(handler func(*WsDepthEvent), errHandler func(error)) (doneC, stopC chan struct{}, err error) {
+		return WsDiffDepthServeWithRate(symbol, rate, handler, errHandler)
+	}, opts...)
+}
+
+// WsBLVTInfoStream is WsBLVTInfoServe delivering events on a channel until ctx is done
+func WsBLVTInfoStream(ctx context.Context, name string, opts ...common.StreamOption) (*common.Stream[*WsBLVTInfoEvent], error) {
+	return common.NewStream[*WsBLVTInfoEvent](ctx, func(handler func(*WsBLVTInfoEvent), errHandler func(error)) (doneC, stopC chan struct{}, err error) {
+		return WsBLVTInfoServe(name, handler, errHandler)
+	}, opts...)
+}
+
+// WsBLVTKlineStream is WsBLVTKlineServe delivering events on a channel until ctx is done
+func WsBLVTKlineStream(ctx context.Context, name string, interval string, opts ...common.StreamOption) (*common.Stream[*WsBLVTKlineEvent], error) {
+	return common.NewStream[*WsBLVTKlineEvent](ctx, func(handler func(*WsBLVTKlineEvent), errHandler func(error)) (doneC, stopC chan struct{}, err error) {
+		return WsBLVTKlineServe(name, interval, handler, errHandler)
+	}, opts...)
+}
+
+// WsCompositiveIndexStream is WsCompositiveIndexServe delivering events on a channel until ctx is done
+func WsCompositiveIndexStream(ctx context.Context, symbol string, opts ...common.StreamOption) (*common.Stream[*WsCompositeIndexEvent], error) {
+	return common.NewStream[*WsCompositeIndexEvent](ctx, func(handler func(*WsCompositeIndexEvent), errHandler func(error)) (doneC, stopC chan struct{}, err error) {
+		return WsCompositiveIndexServe(symbol, handler, errHandler)
+	}, opts...)
+}
+
+// WsUserDataStream is WsUserDataServe delivering events on a channel until ctx is done
+func WsUserDataStream(ctx context.Context, listenKey string, opts ...common.StreamOption) (*common.Stream[*WsUserDataEvent], error) {
+	return common.NewStream[*WsUserDataEvent](ctx, func(handler func(*WsUserDataEvent), errHandler func(error)) (doneC, stopC chan struct{}, err error) {
+		return WsUserDataServe(listenKey, handler, errHandler)
+	}, opts...)
+}
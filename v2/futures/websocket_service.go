@@ -4,11 +4,13 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"log/slog"
 	"strings"
 	"time"
 
 	"github.com/bitly/go-simplejson"
 	easyjson "github.com/mailru/easyjson"
+	"github.com/vv1zard/go-binance/v2/common"
 )
 
 // Endpoints
@@ -24,6 +26,13 @@ var (
 	WebsocketTimeout = time.Second * 60
 	// WebsocketKeepalive enables sending ping/pong messages to check the connection stability
 	WebsocketKeepalive = true
+	// WebsocketReconnect makes every stream reconnect automatically when set, nil disables it
+	WebsocketReconnect *WsReconnectConfig
+	// WebsocketLogHandler receives the connection events of every stream, nil disables them
+	WebsocketLogHandler slog.Handler
+	// WebsocketMetrics receives the message count, event lag and reconnections of
+	// every stream, nil disables them
+	WebsocketMetrics common.Metrics
 	// UseTestnet switch all the WS streams from production to the testnet
 	UseTestnet      = false
 	UseTestnetOrder = false
@@ -609,6 +618,37 @@ type WsDepthEvent struct {
 	Asks             []Ask  `json:"a"`
 }
 
+// parseWsDepthEvent build a WsDepthEvent from the payload of a depth stream
+func parseWsDepthEvent(j *simplejson.Json) *WsDepthEvent {
+	event := new(WsDepthEvent)
+	event.Event = j.Get("e").MustString()
+	event.Time = j.Get("E").MustInt64()
+	event.TransactionTime = j.Get("T").MustInt64()
+	event.Symbol = j.Get("s").MustString()
+	event.FirstUpdateID = j.Get("U").MustInt64()
+	event.LastUpdateID = j.Get("u").MustInt64()
+	event.PrevLastUpdateID = j.Get("pu").MustInt64()
+	bidsLen := len(j.Get("b").MustArray())
+	event.Bids = make([]Bid, bidsLen)
+	for i := 0; i < bidsLen; i++ {
+		item := j.Get("b").GetIndex(i)
+		event.Bids[i] = Bid{
+			Price:    item.GetIndex(0).MustString(),
+			Quantity: item.GetIndex(1).MustString(),
+		}
+	}
+	asksLen := len(j.Get("a").MustArray())
+	event.Asks = make([]Ask, asksLen)
+	for i := 0; i < asksLen; i++ {
+		item := j.Get("a").GetIndex(i)
+		event.Asks[i] = Ask{
+			Price:    item.GetIndex(0).MustString(),
+			Quantity: item.GetIndex(1).MustString(),
+		}
+	}
+	return event
+}
+
 // WsDepthHandler handle websocket depth event
 type WsDepthHandler func(event *WsDepthEvent)
 
@@ -754,33 +794,7 @@ func wsDepthServe(symbol string, levels string, rate *time.Duration, handler WsD
 			errHandler(err)
 			return
 		}
-		event := new(WsDepthEvent)
-		event.Event = j.Get("e").MustString()
-		event.Time = j.Get("E").MustInt64()
-		event.TransactionTime = j.Get("T").MustInt64()
-		event.Symbol = j.Get("s").MustString()
-		event.FirstUpdateID = j.Get("U").MustInt64()
-		event.LastUpdateID = j.Get("u").MustInt64()
-		event.PrevLastUpdateID = j.Get("pu").MustInt64()
-		bidsLen := len(j.Get("b").MustArray())
-		event.Bids = make([]Bid, bidsLen)
-		for i := 0; i < bidsLen; i++ {
-			item := j.Get("b").GetIndex(i)
-			event.Bids[i] = Bid{
-				Price:    item.GetIndex(0).MustString(),
-				Quantity: item.GetIndex(1).MustString(),
-			}
-		}
-		asksLen := len(j.Get("a").MustArray())
-		event.Asks = make([]Ask, asksLen)
-		for i := 0; i < asksLen; i++ {
-			item := j.Get("a").GetIndex(i)
-			event.Asks[i] = Ask{
-				Price:    item.GetIndex(0).MustString(),
-				Quantity: item.GetIndex(1).MustString(),
-			}
-		}
-		handler(event)
+		handler(parseWsDepthEvent(j))
 	}
 	return wsServe(cfg, wsHandler, errHandler)
 }
@@ -1075,6 +1089,51 @@ type WsOrderTradeUpdate struct {
 	GTD                  int64              `json:"gtd"` // TIF GTD order auto cancel time
 }
 
+// OriginalQtyDecimal parse OriginalQty as an exact decimal
+func (w *WsOrderTradeUpdate) OriginalQtyDecimal() (common.Decimal, error) {
+	return common.ParseDecimal(w.OriginalQty)
+}
+
+// OriginalPriceDecimal parse OriginalPrice as an exact decimal
+func (w *WsOrderTradeUpdate) OriginalPriceDecimal() (common.Decimal, error) {
+	return common.ParseDecimal(w.OriginalPrice)
+}
+
+// AveragePriceDecimal parse AveragePrice as an exact decimal
+func (w *WsOrderTradeUpdate) AveragePriceDecimal() (common.Decimal, error) {
+	return common.ParseDecimal(w.AveragePrice)
+}
+
+// StopPriceDecimal parse StopPrice as an exact decimal
+func (w *WsOrderTradeUpdate) StopPriceDecimal() (common.Decimal, error) {
+	return common.ParseDecimal(w.StopPrice)
+}
+
+// LastFilledQtyDecimal parse LastFilledQty as an exact decimal
+func (w *WsOrderTradeUpdate) LastFilledQtyDecimal() (common.Decimal, error) {
+	return common.ParseDecimal(w.LastFilledQty)
+}
+
+// AccumulatedFilledQtyDecimal parse AccumulatedFilledQty as an exact decimal
+func (w *WsOrderTradeUpdate) AccumulatedFilledQtyDecimal() (common.Decimal, error) {
+	return common.ParseDecimal(w.AccumulatedFilledQty)
+}
+
+// LastFilledPriceDecimal parse LastFilledPrice as an exact decimal
+func (w *WsOrderTradeUpdate) LastFilledPriceDecimal() (common.Decimal, error) {
+	return common.ParseDecimal(w.LastFilledPrice)
+}
+
+// CommissionDecimal parse Commission as an exact decimal
+func (w *WsOrderTradeUpdate) CommissionDecimal() (common.Decimal, error) {
+	return common.ParseDecimal(w.Commission)
+}
+
+// RealizedPnLDecimal parse RealizedPnL as an exact decimal
+func (w *WsOrderTradeUpdate) RealizedPnLDecimal() (common.Decimal, error) {
+	return common.ParseDecimal(w.RealizedPnL)
+}
+
 // WsAccountConfigUpdate define account config update
 type WsAccountConfigUpdate struct {
 	Symbol   string `json:"s"`
@@ -1086,8 +1145,14 @@ type WsUserDataHandler func(event *WsUserDataEvent)
 
 // WsUserDataServe serve user data handler with listen key
 func WsUserDataServe(listenKey string, handler WsUserDataHandler, errHandler ErrHandler) (doneC, stopC chan struct{}, err error) {
-	endpoint := fmt.Sprintf("%s/%s", getWsEndpointOrder(), listenKey)
-	cfg := newWsConfig(endpoint)
+	return wsUserDataServe(newWsConfig(getUserDataEndpoint(listenKey)), handler, errHandler)
+}
+
+func getUserDataEndpoint(listenKey string) string {
+	return fmt.Sprintf("%s/%s", getWsEndpointOrder(), listenKey)
+}
+
+func wsUserDataServe(cfg *WsConfig, handler WsUserDataHandler, errHandler ErrHandler) (doneC, stopC chan struct{}, err error) {
 	wsHandler := func(message []byte) {
 		event := new(WsUserDataEvent)
 		err := json.Unmarshal(message, event)
@@ -0,0 +1,216 @@
+package futures
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/gorilla/websocket"
+	easyjson "github.com/mailru/easyjson"
+	"github.com/vv1zard/go-binance/v2/common"
+)
+
+const (
+	// wsMaxStreamsPerConnection is the number of streams Binance accepts on a single connection
+	wsMaxStreamsPerConnection = 1024
+	// wsMaxMessagesPerSecond is the number of messages Binance accepts from a client per second
+	wsMaxMessagesPerSecond = 10
+)
+
+// ErrWsStreamClientClosed is returned when using a WsStreamClient after Close
+var ErrWsStreamClientClosed = common.ErrStreamClientClosed
+
+// getStreamEndpoint return the base endpoint of the combined stream without any stream in it
+func getStreamEndpoint() string {
+	return strings.TrimSuffix(getCombinedEndpoint(), "?streams=")
+}
+
+// WsStreamClient subscribes to the USDⓈ-M futures streams over live combined stream
+// connections, see common.StreamClient. A connection whose redials are
+// exhausted is reported to errHandler with a common.StreamsDroppedError.
+type WsStreamClient struct {
+	*common.StreamClient
+	Reconnect *WsReconnectConfig
+
+	errHandler ErrHandler
+}
+
+// NewWsStreamClient init a stream client, connections are opened on the first subscription.
+// Errors that do not belong to a call, like dropped connections, are reported to errHandler.
+func NewWsStreamClient(errHandler ErrHandler) *WsStreamClient {
+	rc := WebsocketReconnect
+	if rc == nil {
+		rc = NewWsReconnectConfig()
+	}
+	c := &WsStreamClient{
+		Reconnect:  rc,
+		errHandler: errHandler,
+	}
+	c.StreamClient = common.NewStreamClient(getStreamEndpoint(), common.StreamTransport{
+		Dial:  c.dial,
+		Serve: c.serve,
+	}, errHandler)
+	c.MaxStreamsPerConnection = wsMaxStreamsPerConnection
+	c.MaxMessagesPerSecond = wsMaxMessagesPerSecond
+	c.Metrics = WebsocketMetrics
+	if WebsocketKeepalive {
+		c.Keepalive = WebsocketTimeout
+	}
+	return c
+}
+
+func (c *WsStreamClient) dial(endpoint string) (*websocket.Conn, error) {
+	logger := common.NewLogger(WebsocketLogHandler).With("stream", endpoint)
+	conn, err := wsDial(endpoint)
+	if err != nil {
+		logger.Error("websocket dial failed", "error", err)
+		return nil, err
+	}
+	logger.Info("websocket connected")
+	c.Reconnect.connected(endpoint)
+	return conn, nil
+}
+
+// serve read conn and redial it like wsServeWithReconnect, see common.StreamTransport
+func (c *WsStreamClient) serve(endpoint string, conn *websocket.Conn, hooks *common.StreamConnHooks, stopC chan struct{}) error {
+	cfg := &WsConfig{Endpoint: endpoint, Reconnect: c.Reconnect, LogHandler: WebsocketLogHandler, Metrics: WebsocketMetrics}
+	rc := cfg.Reconnect
+	logger := common.NewLogger(cfg.LogHandler).With("stream", endpoint)
+	for {
+		err := wsReadConn(conn, rc.MaxConnectionAge, hooks.Message, stopC)
+		if err == nil {
+			logger.Info("websocket stopped")
+			return nil
+		}
+		if errors.Is(err, ErrWsConnectionExpired) {
+			logger.Info("websocket recycled", "error", err)
+		} else {
+			logger.Warn("websocket disconnected", "error", err)
+		}
+		rc.disconnected(endpoint, err)
+		hooks.Disconnected(err)
+		c.errHandler(err)
+		var attempts int
+		conn, attempts = wsRedial(cfg, errors.Is(err, ErrWsConnectionExpired), logger, c.errHandler, stopC)
+		if conn == nil {
+			select {
+			case <-stopC:
+				return nil
+			default:
+				return ErrWsReconnectExhausted
+			}
+		}
+		logger.Info("websocket reconnected", "attempts", attempts)
+		common.RecordReconnect(cfg.Metrics, common.StreamName(endpoint))
+		rc.reconnected(endpoint, attempts)
+		hooks.Reconnected(conn)
+	}
+}
+
+// SubscribeDiffDepth subscribe to the diff depth streams of symbols, using 250msec updates
+func (c *WsStreamClient) SubscribeDiffDepth(ctx context.Context, symbols []string, handler WsDepthHandler) error {
+	return c.subscribeDepth(ctx, symbols, "@depth", handler)
+}
+
+// SubscribeDiffDepth100Ms subscribe to the diff depth streams of symbols, using 100msec updates
+func (c *WsStreamClient) SubscribeDiffDepth100Ms(ctx context.Context, symbols []string, handler WsDepthHandler) error {
+	return c.subscribeDepth(ctx, symbols, "@depth@100ms", handler)
+}
+
+// SubscribePartialDepth subscribe to the partial depth streams of symbols with their levels
+func (c *WsStreamClient) SubscribePartialDepth(ctx context.Context, symbolLevels map[string]string, handler WsDepthHandler) error {
+	streams := make([]string, 0, len(symbolLevels))
+	for symbol, levels := range symbolLevels {
+		streams = append(streams, fmt.Sprintf("%s@depth%s", strings.ToLower(symbol), levels))
+	}
+	return c.Subscribe(ctx, streams, c.depthHandler(handler))
+}
+
+func (c *WsStreamClient) subscribeDepth(ctx context.Context, symbols []string, suffix string, handler WsDepthHandler) error {
+	return c.Subscribe(ctx, symbolStreams(symbols, suffix), c.depthHandler(handler))
+}
+
+func (c *WsStreamClient) depthHandler(handler WsDepthHandler) WsHandler {
+	return func(data []byte) {
+		j, err := newJSON(data)
+		if err != nil {
+			c.errHandler(err)
+			return
+		}
+		handler(parseWsDepthEvent(j))
+	}
+}
+
+// SubscribeKline subscribe to the kline streams of symbols with their interval
+func (c *WsStreamClient) SubscribeKline(ctx context.Context, symbolIntervalPair map[string]string, handler WsKlineHandler) error {
+	streams := make([]string, 0, len(symbolIntervalPair))
+	for symbol, interval := range symbolIntervalPair {
+		streams = append(streams, fmt.Sprintf("%s@kline_%s", strings.ToLower(symbol), interval))
+	}
+	return c.Subscribe(ctx, streams, func(data []byte) {
+		event := new(WsKlineEvent)
+		err := easyjson.Unmarshal(data, event)
+		if err != nil {
+			c.errHandler(err)
+			return
+		}
+		handler(event)
+	})
+}
+
+// SubscribeAggTrade subscribe to the aggregate trade streams of symbols
+func (c *WsStreamClient) SubscribeAggTrade(ctx context.Context, symbols []string, handler WsAggTradeHandler) error {
+	return c.Subscribe(ctx, symbolStreams(symbols, "@aggTrade"), func(data []byte) {
+		event := new(WsAggTradeEvent)
+		err := easyjson.Unmarshal(data, event)
+		if err != nil {
+			c.errHandler(err)
+			return
+		}
+		handler(event)
+	})
+}
+
+// SubscribeMarkPrice subscribe to the mark price streams of symbols, using 3sec updates
+func (c *WsStreamClient) SubscribeMarkPrice(ctx context.Context, symbols []string, handler WsMarkPriceHandler) error {
+	return c.subscribeMarkPrice(ctx, symbols, "@markPrice", handler)
+}
+
+// SubscribeMarkPrice1s subscribe to the mark price streams of symbols, using 1sec updates
+func (c *WsStreamClient) SubscribeMarkPrice1s(ctx context.Context, symbols []string, handler WsMarkPriceHandler) error {
+	return c.subscribeMarkPrice(ctx, symbols, "@markPrice@1s", handler)
+}
+
+func (c *WsStreamClient) subscribeMarkPrice(ctx context.Context, symbols []string, suffix string, handler WsMarkPriceHandler) error {
+	return c.Subscribe(ctx, symbolStreams(symbols, suffix), func(data []byte) {
+		event := new(WsMarkPriceEvent)
+		err := easyjson.Unmarshal(data, event)
+		if err != nil {
+			c.errHandler(err)
+			return
+		}
+		handler(event)
+	})
+}
+
+// SubscribeBookTicker subscribe to the best book ticker streams of symbols
+func (c *WsStreamClient) SubscribeBookTicker(ctx context.Context, symbols []string, handler WsBookTickerHandler) error {
+	return c.Subscribe(ctx, symbolStreams(symbols, "@bookTicker"), func(data []byte) {
+		event := new(WsBookTickerEvent)
+		err := easyjson.Unmarshal(data, event)
+		if err != nil {
+			c.errHandler(err)
+			return
+		}
+		handler(event)
+	})
+}
+
+func symbolStreams(symbols []string, suffix string) []string {
+	streams := make([]string, len(symbols))
+	for i, symbol := range symbols {
+		streams[i] = strings.ToLower(symbol) + suffix
+	}
+	return streams
+}
@@ -0,0 +1,34 @@
+package binance
+
+import (
+	"context"
+
+	"github.com/vv1zard/go-binance/v2/common"
+)
+
+// NewKlineDownloader init a downloader of the spot klines caching them under
+// dir, an empty dir disables the cache
+func (c *Client) NewKlineDownloader(dir string) *common.KlineDownloader {
+	d := &common.KlineDownloader{
+		Market: "spot",
+		Limit:  1000,
+		Fetch: func(ctx context.Context, symbol, interval string, startTime, endTime int64, limit int) ([]*common.Kline, error) {
+			klines, err := c.NewKlinesService().Symbol(symbol).Interval(interval).
+				StartTime(startTime).EndTime(endTime).Limit(limit).Do(ctx)
+			return commonKlines(klines), err
+		},
+	}
+	if dir != "" {
+		d.Cache = common.NewKlineCache(dir)
+	}
+	return d
+}
+
+func commonKlines(klines []*Kline) []*common.Kline {
+	res := make([]*common.Kline, len(klines))
+	for i, kline := range klines {
+		k := common.Kline(*kline)
+		res[i] = &k
+	}
+	return res
+}
@@ -0,0 +1,36 @@
+package binance
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestKlineDownloader(t *testing.T) {
+	r := require.New(t)
+	c := NewClient("", "")
+	c.do = func(req *http.Request) (*http.Response, error) {
+		query := req.URL.Query()
+		r.Equal("/api/v3/klines", req.URL.Path)
+		r.Equal("BTCUSDT", query.Get("symbol"))
+		r.Equal("1h", query.Get("interval"))
+		r.Equal("1704067200000", query.Get("startTime"))
+		r.Equal("1704074399999", query.Get("endTime"))
+		r.Equal("2", query.Get("limit"))
+		return newHTTPResponse([]byte(`[
+			[1704067200000, "42283.58", "42554.57", "42261.02", "42475.23", "1271.68108", 1704070799999, "53957249.92", 47134, "682.57581", "28958973.40", "0"],
+			[1704070800000, "42475.23", "42775.00", "42431.65", "42613.56", "1196.37856", 1704074399999, "50984948.67", 45612, "652.55356", "27808046.64", "0"]
+		]`), http.StatusOK), nil
+	}
+	d := c.NewKlineDownloader(t.TempDir())
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	series, err := d.Download(context.Background(), "BTCUSDT", "1h", start, start.Add(2*time.Hour-time.Millisecond))
+	r.NoError(err)
+	r.Len(series.Klines, 2)
+	r.Empty(series.Gaps)
+	r.Equal("42613.56", series.Klines[1].Close)
+	r.Equal(int64(45612), series.Klines[1].TradeNum)
+}
@@ -96,6 +96,12 @@ func (s *klineServiceTestSuite) TestKlines() {
 	}
 	s.assertKlineEqual(kline1, klines[0])
 	s.assertKlineEqual(kline2, klines[1])
+
+	open, err := klines[1].OpenDecimal()
+	s.r().NoError(err)
+	closePrice, err := klines[1].CloseDecimal()
+	s.r().NoError(err)
+	s.r().Equal("-0.00057689", closePrice.Sub(open).String())
 }
 
 func (s *klineServiceTestSuite) assertKlineEqual(e, a *Kline) {
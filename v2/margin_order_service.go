@@ -21,6 +21,7 @@ type CreateMarginOrderService struct {
 	sideEffectType   *SideEffectType
 	timeInForce      *TimeInForceType
 	isIsolated       *bool
+	stpMode          *SelfTradePreventionModeType
 }
 
 // Symbol set symbol
@@ -101,6 +102,12 @@ func (s *CreateMarginOrderService) SideEffectType(sideEffectType SideEffectType)
 	return s
 }
 
+// SelfTradePreventionMode set selfTradePreventionMode
+func (s *CreateMarginOrderService) SelfTradePreventionMode(mode SelfTradePreventionModeType) *CreateMarginOrderService {
+	s.stpMode = &mode
+	return s
+}
+
 // Do send request
 func (s *CreateMarginOrderService) Do(ctx context.Context, opts ...RequestOption) (res *CreateOrderResponse, err error) {
 	r := &request{
@@ -147,6 +154,9 @@ func (s *CreateMarginOrderService) Do(ctx context.Context, opts ...RequestOption
 	if s.sideEffectType != nil {
 		m["sideEffectType"] = *s.sideEffectType
 	}
+	if s.stpMode != nil {
+		m["selfTradePreventionMode"] = *s.stpMode
+	}
 	r.setFormParams(m)
 	res = new(CreateOrderResponse)
 	data, err := s.c.callAPI(ctx, r, opts...)
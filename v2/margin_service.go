@@ -4,6 +4,9 @@ import (
 	"context"
 	"net/http"
 	"strings"
+	"time"
+
+	"github.com/vv1zard/go-binance/v2/common"
 )
 
 // MarginTransferService transfer between spot account and margin account
@@ -272,6 +275,39 @@ func (s *ListMarginLoansService) Do(ctx context.Context, opts ...RequestOption)
 	return res, nil
 }
 
+// Iterate walk the loans of the asset from StartTime up to EndTime in windows
+// of 30 days with as many requests as needed, Size sets the page size. The
+// loans are returned oldest first.
+func (s *ListMarginLoansService) Iterate(opts ...RequestOption) *common.Iterator[MarginLoan] {
+	p := common.Pager[MarginLoan]{
+		Fetch: func(ctx context.Context, q common.PageQuery) ([]MarginLoan, error) {
+			current, size := int64(q.Offset/q.Limit+1), int64(q.Limit)
+			page := &ListMarginLoansService{c: s.c, asset: s.asset,
+				startTime: &q.StartTime, endTime: &q.EndTime, current: &current, size: &size}
+			res, err := page.Do(ctx, opts...)
+			if err != nil {
+				return nil, err
+			}
+			return res.Rows, nil
+		},
+		Limit:     100,
+		MaxWindow: 30 * 24 * time.Hour,
+		Time:      func(loan MarginLoan) int64 { return loan.Timestamp },
+		Key:       func(loan MarginLoan) interface{} { return loan.TxID },
+		Offset:    true,
+	}
+	if s.size != nil {
+		p.Limit = int(*s.size)
+	}
+	if s.startTime != nil {
+		p.StartTime = *s.startTime
+	}
+	if s.endTime != nil {
+		p.EndTime = *s.endTime
+	}
+	return p.Iterator()
+}
+
 // MarginLoanResponse define margin loan response
 type MarginLoanResponse struct {
 	Rows  []MarginLoan `json:"rows"`
@@ -284,6 +320,7 @@ type MarginLoan struct {
 	Principal string               `json:"principal"`
 	Timestamp int64                `json:"timestamp"`
 	Status    MarginLoanStatusType `json:"status"`
+	TxID      int64                `json:"txId"`
 }
 
 // ListMarginRepaysService list repay record
@@ -368,6 +405,39 @@ func (s *ListMarginRepaysService) Do(ctx context.Context, opts ...RequestOption)
 	return res, nil
 }
 
+// Iterate walk the repays of the asset from StartTime up to EndTime in windows
+// of 30 days with as many requests as needed, Size sets the page size. The
+// repays are returned oldest first.
+func (s *ListMarginRepaysService) Iterate(opts ...RequestOption) *common.Iterator[MarginRepay] {
+	p := common.Pager[MarginRepay]{
+		Fetch: func(ctx context.Context, q common.PageQuery) ([]MarginRepay, error) {
+			current, size := int64(q.Offset/q.Limit+1), int64(q.Limit)
+			page := &ListMarginRepaysService{c: s.c, asset: s.asset,
+				startTime: &q.StartTime, endTime: &q.EndTime, current: &current, size: &size}
+			res, err := page.Do(ctx, opts...)
+			if err != nil {
+				return nil, err
+			}
+			return res.Rows, nil
+		},
+		Limit:     100,
+		MaxWindow: 30 * 24 * time.Hour,
+		Time:      func(repay MarginRepay) int64 { return repay.Timestamp },
+		Key:       func(repay MarginRepay) interface{} { return repay.TxID },
+		Offset:    true,
+	}
+	if s.size != nil {
+		p.Limit = int(*s.size)
+	}
+	if s.startTime != nil {
+		p.StartTime = *s.startTime
+	}
+	if s.endTime != nil {
+		p.EndTime = *s.endTime
+	}
+	return p.Iterator()
+}
+
 // MarginRepayResponse define margin repay response
 type MarginRepayResponse struct {
 	Rows  []MarginRepay `json:"rows"`
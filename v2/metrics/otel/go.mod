@@ -0,0 +1,27 @@
+module github.com/vv1zard/go-binance/v2/metrics/otel
+
+go 1.21
+
+require (
+	github.com/stretchr/testify v1.8.4
+	github.com/vv1zard/go-binance/v2 v2.1.0
+	go.opentelemetry.io/otel v1.24.0
+	go.opentelemetry.io/otel/metric v1.24.0
+	go.opentelemetry.io/otel/sdk/metric v1.24.0
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/go-logr/logr v1.4.1 // indirect
+	github.com/go-logr/stdr v1.2.2 // indirect
+	github.com/gorilla/websocket v1.5.0 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	go.opentelemetry.io/otel/sdk v1.24.0 // indirect
+	go.opentelemetry.io/otel/trace v1.24.0 // indirect
+	golang.org/x/sys v0.17.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
+
+// Build against the core module of this checkout during development, the
+// importers of this module resolve the version required above
+replace github.com/vv1zard/go-binance/v2 => ../..
@@ -0,0 +1,119 @@
+// Package otel implements common.Metrics with OpenTelemetry instruments.
+//
+//	m, err := otel.New(provider.Meter("binance/spot"))
+//	client.Metrics = m
+//	binance.WebsocketMetrics = m
+package otel
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// Metrics record the activity of a client with the instruments of a meter,
+// use one meter per client so that the metrics of spot and futures do not clash
+type Metrics struct {
+	requests   metric.Int64Counter
+	latency    metric.Float64Histogram
+	messages   metric.Int64Counter
+	lag        metric.Float64Histogram
+	reconnects metric.Int64Counter
+
+	// usedWeight hold the last used weight by interval, the gauge reports it
+	// when collected
+	mu         sync.Mutex
+	usedWeight map[string]int64
+}
+
+// New create the instruments on meter
+func New(meter metric.Meter) (*Metrics, error) {
+	m := &Metrics{usedWeight: make(map[string]int64)}
+	var err error
+	m.requests, err = meter.Int64Counter("binance.requests",
+		metric.WithDescription("Number of REST requests by endpoint, HTTP status and Binance error code."),
+		metric.WithUnit("{request}"))
+	if err != nil {
+		return nil, err
+	}
+	m.latency, err = meter.Float64Histogram("binance.request.duration",
+		metric.WithDescription("Latency of the REST requests by endpoint."),
+		metric.WithUnit("s"))
+	if err != nil {
+		return nil, err
+	}
+	_, err = meter.Int64ObservableGauge("binance.used_weight",
+		metric.WithDescription("Request weight used in the interval, as reported by the X-MBX-USED-WEIGHT headers."),
+		metric.WithInt64Callback(m.observeUsedWeight))
+	if err != nil {
+		return nil, err
+	}
+	m.messages, err = meter.Int64Counter("binance.ws.messages",
+		metric.WithDescription("Number of messages received by stream."),
+		metric.WithUnit("{message}"))
+	if err != nil {
+		return nil, err
+	}
+	m.lag, err = meter.Float64Histogram("binance.ws.event_lag",
+		metric.WithDescription("Delay between the event time of a message and its reception."),
+		metric.WithUnit("s"))
+	if err != nil {
+		return nil, err
+	}
+	m.reconnects, err = meter.Int64Counter("binance.ws.reconnects",
+		metric.WithDescription("Number of reconnections by stream."),
+		metric.WithUnit("{reconnect}"))
+	if err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (m *Metrics) observeUsedWeight(_ context.Context, o metric.Int64Observer) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for interval, weight := range m.usedWeight {
+		o.Observe(weight, metric.WithAttributes(attribute.String("interval", interval)))
+	}
+	return nil
+}
+
+// ObserveRequest implements common.Metrics
+func (m *Metrics) ObserveRequest(method, endpoint string, status int, code int64, latency time.Duration) {
+	ctx := context.Background()
+	m.requests.Add(ctx, 1, metric.WithAttributes(
+		attribute.String("method", method),
+		attribute.String("endpoint", endpoint),
+		attribute.Int("status", status),
+		attribute.Int64("code", code),
+	))
+	m.latency.Record(ctx, latency.Seconds(), metric.WithAttributes(
+		attribute.String("method", method),
+		attribute.String("endpoint", endpoint),
+	))
+}
+
+// SetUsedWeight implements common.Metrics
+func (m *Metrics) SetUsedWeight(interval string, weight int64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.usedWeight[interval] = weight
+}
+
+// ObserveMessage implements common.Metrics
+func (m *Metrics) ObserveMessage(stream string) {
+	m.messages.Add(context.Background(), 1, metric.WithAttributes(attribute.String("stream", stream)))
+}
+
+// ObserveLag implements common.Metrics
+func (m *Metrics) ObserveLag(stream string, lag time.Duration) {
+	m.lag.Record(context.Background(), lag.Seconds(), metric.WithAttributes(attribute.String("stream", stream)))
+}
+
+// ObserveReconnect implements common.Metrics
+func (m *Metrics) ObserveReconnect(stream string) {
+	m.reconnects.Add(context.Background(), 1, metric.WithAttributes(attribute.String("stream", stream)))
+}
@@ -0,0 +1,73 @@
+package otel
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vv1zard/go-binance/v2/common"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+var _ common.Metrics = (*Metrics)(nil)
+
+func TestMetrics(t *testing.T) {
+	assert := assert.New(t)
+	r := require.New(t)
+	reader := sdkmetric.NewManualReader()
+	provider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	m, err := New(provider.Meter("binance/spot"))
+	r.NoError(err)
+
+	m.ObserveRequest("GET", "/api/v3/account", 200, 0, 15*time.Millisecond)
+	m.ObserveRequest("POST", "/api/v3/order", 400, -2010, time.Millisecond)
+	m.ObserveRequest("POST", "/api/v3/order", 400, -2010, time.Millisecond)
+	m.SetUsedWeight("1m", 20)
+	m.SetUsedWeight("1m", 41)
+	m.ObserveMessage("btcusdt@kline_1m")
+	m.ObserveLag("btcusdt@kline_1m", 30*time.Millisecond)
+	m.ObserveReconnect("btcusdt@kline_1m")
+
+	var data metricdata.ResourceMetrics
+	r.NoError(reader.Collect(context.Background(), &data))
+	r.Len(data.ScopeMetrics, 1)
+	got := make(map[string]metricdata.Aggregation)
+	for _, metric := range data.ScopeMetrics[0].Metrics {
+		got[metric.Name] = metric.Data
+	}
+	r.Len(got, 6)
+
+	requests := got["binance.requests"].(metricdata.Sum[int64])
+	r.Len(requests.DataPoints, 2)
+	for _, point := range requests.DataPoints {
+		code, _ := point.Attributes.Value("code")
+		if code.AsInt64() == -2010 {
+			assert.Equal(int64(2), point.Value)
+			status, _ := point.Attributes.Value("status")
+			assert.Equal(int64(400), status.AsInt64())
+		} else {
+			assert.Equal(int64(1), point.Value)
+		}
+	}
+	latency := got["binance.request.duration"].(metricdata.Histogram[float64])
+	assert.Len(latency.DataPoints, 2)
+
+	weight := got["binance.used_weight"].(metricdata.Gauge[int64])
+	r.Len(weight.DataPoints, 1)
+	assert.Equal(int64(41), weight.DataPoints[0].Value)
+	assert.Equal(attribute.NewSet(attribute.String("interval", "1m")), weight.DataPoints[0].Attributes)
+
+	messages := got["binance.ws.messages"].(metricdata.Sum[int64])
+	r.Len(messages.DataPoints, 1)
+	assert.Equal(int64(1), messages.DataPoints[0].Value)
+	lag := got["binance.ws.event_lag"].(metricdata.Histogram[float64])
+	r.Len(lag.DataPoints, 1)
+	assert.Equal(uint64(1), lag.DataPoints[0].Count)
+	reconnects := got["binance.ws.reconnects"].(metricdata.Sum[int64])
+	r.Len(reconnects.DataPoints, 1)
+	assert.Equal(int64(1), reconnects.DataPoints[0].Value)
+}
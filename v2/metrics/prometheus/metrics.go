@@ -0,0 +1,114 @@
+// Package prometheus implements common.Metrics with Prometheus collectors.
+//
+//	m := prometheus.New("binance", "spot")
+//	promclient.MustRegister(m)
+//	client.Metrics = m
+//	binance.WebsocketMetrics = m
+package prometheus
+
+import (
+	"strconv"
+	"time"
+
+	prom "github.com/prometheus/client_golang/prometheus"
+)
+
+// Metrics collect the activity of a client, it is a prometheus.Collector to
+// register on a registry
+type Metrics struct {
+	requests   *prom.CounterVec
+	latency    *prom.HistogramVec
+	usedWeight *prom.GaugeVec
+	messages   *prom.CounterVec
+	lag        *prom.HistogramVec
+	reconnects *prom.CounterVec
+}
+
+// New create the collectors, named after namespace and subsystem so that the
+// metrics of several clients, such as spot and futures, do not clash
+func New(namespace, subsystem string) *Metrics {
+	return &Metrics{
+		requests: prom.NewCounterVec(prom.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "requests_total",
+			Help:      "Number of REST requests by endpoint, HTTP status and Binance error code.",
+		}, []string{"method", "endpoint", "status", "code"}),
+		latency: prom.NewHistogramVec(prom.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "request_duration_seconds",
+			Help:      "Latency of the REST requests by endpoint.",
+			Buckets:   prom.DefBuckets,
+		}, []string{"method", "endpoint"}),
+		usedWeight: prom.NewGaugeVec(prom.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "used_weight",
+			Help:      "Request weight used in the interval, as reported by the X-MBX-USED-WEIGHT headers.",
+		}, []string{"interval"}),
+		messages: prom.NewCounterVec(prom.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "ws_messages_total",
+			Help:      "Number of messages received by stream.",
+		}, []string{"stream"}),
+		lag: prom.NewHistogramVec(prom.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "ws_event_lag_seconds",
+			Help:      "Delay between the event time of a message and its reception.",
+			Buckets:   prom.DefBuckets,
+		}, []string{"stream"}),
+		reconnects: prom.NewCounterVec(prom.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "ws_reconnects_total",
+			Help:      "Number of reconnections by stream.",
+		}, []string{"stream"}),
+	}
+}
+
+func (m *Metrics) collectors() []prom.Collector {
+	return []prom.Collector{m.requests, m.latency, m.usedWeight, m.messages, m.lag, m.reconnects}
+}
+
+// Describe implements prometheus.Collector
+func (m *Metrics) Describe(ch chan<- *prom.Desc) {
+	for _, c := range m.collectors() {
+		c.Describe(ch)
+	}
+}
+
+// Collect implements prometheus.Collector
+func (m *Metrics) Collect(ch chan<- prom.Metric) {
+	for _, c := range m.collectors() {
+		c.Collect(ch)
+	}
+}
+
+// ObserveRequest implements common.Metrics
+func (m *Metrics) ObserveRequest(method, endpoint string, status int, code int64, latency time.Duration) {
+	m.requests.WithLabelValues(method, endpoint, strconv.Itoa(status), strconv.FormatInt(code, 10)).Inc()
+	m.latency.WithLabelValues(method, endpoint).Observe(latency.Seconds())
+}
+
+// SetUsedWeight implements common.Metrics
+func (m *Metrics) SetUsedWeight(interval string, weight int64) {
+	m.usedWeight.WithLabelValues(interval).Set(float64(weight))
+}
+
+// ObserveMessage implements common.Metrics
+func (m *Metrics) ObserveMessage(stream string) {
+	m.messages.WithLabelValues(stream).Inc()
+}
+
+// ObserveLag implements common.Metrics
+func (m *Metrics) ObserveLag(stream string, lag time.Duration) {
+	m.lag.WithLabelValues(stream).Observe(lag.Seconds())
+}
+
+// ObserveReconnect implements common.Metrics
+func (m *Metrics) ObserveReconnect(stream string) {
+	m.reconnects.WithLabelValues(stream).Inc()
+}
@@ -0,0 +1,47 @@
+package prometheus
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	prom "github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vv1zard/go-binance/v2/common"
+)
+
+var _ common.Metrics = (*Metrics)(nil)
+
+func TestMetrics(t *testing.T) {
+	assert := assert.New(t)
+	m := New("binance", "spot")
+	registry := prom.NewRegistry()
+	require.NoError(t, registry.Register(m))
+
+	m.ObserveRequest("GET", "/api/v3/account", 200, 0, 15*time.Millisecond)
+	m.ObserveRequest("POST", "/api/v3/order", 400, -2010, time.Millisecond)
+	m.ObserveRequest("POST", "/api/v3/order", 400, -2010, time.Millisecond)
+	m.SetUsedWeight("1m", 20)
+	m.SetUsedWeight("1m", 41)
+	m.ObserveMessage("btcusdt@kline_1m")
+	m.ObserveLag("btcusdt@kline_1m", 30*time.Millisecond)
+	m.ObserveReconnect("btcusdt@kline_1m")
+
+	assert.Equal(2.0, testutil.ToFloat64(m.requests.WithLabelValues("POST", "/api/v3/order", "400", "-2010")))
+	assert.Equal(41.0, testutil.ToFloat64(m.usedWeight.WithLabelValues("1m")))
+	assert.Equal(1.0, testutil.ToFloat64(m.messages.WithLabelValues("btcusdt@kline_1m")))
+	assert.Equal(1.0, testutil.ToFloat64(m.reconnects.WithLabelValues("btcusdt@kline_1m")))
+
+	err := testutil.GatherAndCompare(registry, strings.NewReader(`
+# HELP binance_spot_requests_total Number of REST requests by endpoint, HTTP status and Binance error code.
+# TYPE binance_spot_requests_total counter
+binance_spot_requests_total{code="-2010",endpoint="/api/v3/order",method="POST",status="400"} 2
+binance_spot_requests_total{code="0",endpoint="/api/v3/account",method="GET",status="200"} 1
+`), "binance_spot_requests_total")
+	assert.NoError(err)
+	count, err := testutil.GatherAndCount(registry, "binance_spot_request_duration_seconds", "binance_spot_ws_event_lag_seconds")
+	assert.NoError(err)
+	assert.Equal(3, count)
+}
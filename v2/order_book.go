@@ -0,0 +1,76 @@
+package binance
+
+import (
+	"context"
+
+	"github.com/vv1zard/go-binance/v2/common"
+)
+
+// OrderBook keeps a local order book of a symbol in sync with
+// the diff depth stream, see common.OrderBookSync. Snapshots are fetched with
+// DepthService.
+type OrderBook struct {
+	*common.OrderBookSync
+	Symbol string
+	// SnapshotLimit is the number of levels requested for each snapshot
+	SnapshotLimit int
+	// Use100Ms subscribes to the 100msec diff depth stream instead of the 1sec one
+	Use100Ms bool
+	// OnUpdate is called after every snapshot or event applied to the book
+	OnUpdate func(book *OrderBook)
+
+	c *Client
+}
+
+// NewOrderBook init an order book for symbol, call Serve to start syncing it
+func (c *Client) NewOrderBook(symbol string) *OrderBook {
+	b := &OrderBook{
+		Symbol:        symbol,
+		SnapshotLimit: 1000,
+		c:             c,
+	}
+	b.OrderBookSync = common.NewOrderBookSync(b.snapshot, false)
+	return b
+}
+
+// Serve subscribe to the diff depth stream and keep the book in sync until stopC is closed
+func (b *OrderBook) Serve(errHandler ErrHandler) (doneC, stopC chan struct{}, err error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	sync := b.Handler(ctx, b.notify, errHandler)
+	handler := func(event *WsDepthEvent) {
+		sync(&common.DepthUpdate{
+			FirstUpdateID: event.FirstUpdateID,
+			LastUpdateID:  event.LastUpdateID,
+			Bids:          event.Bids,
+			Asks:          event.Asks,
+		})
+	}
+	if b.Use100Ms {
+		doneC, stopC, err = WsDepthServe100Ms(b.Symbol, handler, errHandler)
+	} else {
+		doneC, stopC, err = WsDepthServe(b.Symbol, handler, errHandler)
+	}
+	if err != nil {
+		cancel()
+		return nil, nil, err
+	}
+	go func() {
+		<-doneC
+		cancel()
+	}()
+	return doneC, stopC, nil
+}
+
+func (b *OrderBook) snapshot(ctx context.Context) (*common.DepthSnapshot, error) {
+	res, err := b.c.NewDepthService().Symbol(b.Symbol).Limit(b.SnapshotLimit).Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &common.DepthSnapshot{LastUpdateID: res.LastUpdateID, Bids: res.Bids, Asks: res.Asks}, nil
+}
+
+func (b *OrderBook) notify() {
+	if b.OnUpdate != nil {
+		b.OnUpdate(b)
+	}
+}
@@ -0,0 +1,92 @@
+package binance
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type orderBookTestSuite struct {
+	baseTestSuite
+	origWsServe func(*WsConfig, WsHandler, ErrHandler) (chan struct{}, chan struct{}, error)
+	wsHandler   WsHandler
+	updates     chan struct{}
+	errs        chan error
+}
+
+func TestOrderBook(t *testing.T) {
+	suite.Run(t, new(orderBookTestSuite))
+}
+
+func (s *orderBookTestSuite) SetupTest() {
+	s.baseTestSuite.SetupTest()
+	s.origWsServe = wsServe
+	wsServe = func(cfg *WsConfig, handler WsHandler, errHandler ErrHandler) (doneC, stopC chan struct{}, err error) {
+		s.wsHandler = handler
+		doneC = make(chan struct{})
+		stopC = make(chan struct{})
+		go func() {
+			<-stopC
+			close(doneC)
+		}()
+		return doneC, stopC, nil
+	}
+	s.updates = make(chan struct{}, 10)
+	s.errs = make(chan error, 10)
+}
+
+func (s *orderBookTestSuite) TearDownTest() {
+	wsServe = s.origWsServe
+}
+
+func (s *orderBookTestSuite) serve() (*OrderBook, chan struct{}) {
+	updates, errs := s.updates, s.errs
+	book := s.client.NewOrderBook("BNBBTC")
+	book.OnUpdate = func(book *OrderBook) {
+		updates <- struct{}{}
+	}
+	_, stopC, err := book.Serve(func(err error) {
+		select {
+		case errs <- err:
+		default:
+		}
+	})
+	s.r().NoError(err)
+	return book, stopC
+}
+
+func (s *orderBookTestSuite) waitUpdate() {
+	select {
+	case <-s.updates:
+	case <-time.After(time.Second):
+		s.FailNow("timed out waiting for book update")
+	}
+}
+
+func (s *orderBookTestSuite) TestSync() {
+	data := []byte(`{
+		"lastUpdateId": 160,
+		"bids": [["0.0024", "10"], ["0.0023", "5"]],
+		"asks": [["0.0026", "100"]]
+	}`)
+	s.mockDo(data, nil)
+	defer s.assertDo()
+	book, stopC := s.serve()
+	defer close(stopC)
+
+	// Stale event dropped, second one bridges the snapshot.
+	s.wsHandler([]byte(`{"e":"depthUpdate","s":"BNBBTC","U":150,"u":155,"b":[["0.0024","1"]],"a":[]}`))
+	s.wsHandler([]byte(`{"e":"depthUpdate","s":"BNBBTC","U":157,"u":161,"b":[["0.0025","2"]],"a":[]}`))
+	s.waitUpdate()
+	s.r().True(book.Synced())
+	s.r().Equal(int64(161), book.LastUpdateID())
+	bid, _ := book.BestBid()
+	s.r().Equal(Bid{Price: "0.0025", Quantity: "2"}, bid)
+
+	s.wsHandler([]byte(`{"e":"depthUpdate","s":"BNBBTC","U":162,"u":163,"b":[["0.0025","0"]],"a":[["0.0026","0"],["0.0027","3"]]}`))
+	s.waitUpdate()
+	bids, asks := book.Depth(5)
+	s.r().Equal([]Bid{{Price: "0.0024", Quantity: "10"}, {Price: "0.0023", Quantity: "5"}}, bids)
+	s.r().Equal([]Ask{{Price: "0.0027", Quantity: "3"}}, asks)
+}
@@ -0,0 +1,407 @@
+package binance
+
+import (
+	"context"
+	"net/http"
+)
+
+// OrderListLeg define an order of an order list, the empty fields are not
+// sent. The side and quantity of the legs of an OCO are set on the list.
+type OrderListLeg struct {
+	Type            OrderType
+	Side            SideType
+	ClientOrderID   string
+	Price           string
+	StopPrice       string
+	TrailingDelta   string
+	Quantity        string
+	IcebergQuantity string
+	TimeInForce     TimeInForceType
+	StrategyID      int64
+	StrategyType    int64
+}
+
+// setParams set the parameters of the leg, named prefix followed by the
+// field, such as aboveType or pendingBelowPrice
+func (l *OrderListLeg) setParams(prefix string, m params) {
+	if l == nil {
+		return
+	}
+	for _, p := range []struct {
+		key   string
+		value string
+	}{
+		{"Type", string(l.Type)},
+		{"Side", string(l.Side)},
+		{"ClientOrderId", l.ClientOrderID},
+		{"Price", l.Price},
+		{"StopPrice", l.StopPrice},
+		{"TrailingDelta", l.TrailingDelta},
+		{"Quantity", l.Quantity},
+		{"IcebergQty", l.IcebergQuantity},
+		{"TimeInForce", string(l.TimeInForce)},
+	} {
+		if p.value != "" {
+			m[prefix+p.key] = p.value
+		}
+	}
+	if l.StrategyID != 0 {
+		m[prefix+"StrategyId"] = l.StrategyID
+	}
+	if l.StrategyType != 0 {
+		m[prefix+"StrategyType"] = l.StrategyType
+	}
+}
+
+// createOrderList send the request of a new order list
+func createOrderList(ctx context.Context, c *Client, endpoint string, m params, opts ...RequestOption) (res *OrderList, err error) {
+	r := &request{
+		method:   http.MethodPost,
+		endpoint: endpoint,
+		secType:  secTypeSigned,
+	}
+	r.setFormParams(m)
+	data, err := c.callAPI(ctx, r, opts...)
+	if err != nil {
+		return nil, err
+	}
+	res = new(OrderList)
+	err = json.Unmarshal(data, res)
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// CreateOrderListOCOService create an OCO, one leg above and one leg below
+// the last price, the fill of one leg cancels the other
+type CreateOrderListOCOService struct {
+	c                 *Client
+	symbol            string
+	side              SideType
+	quantity          string
+	listClientOrderID *string
+	newOrderRespType  *NewOrderRespType
+	above             *OrderListLeg
+	below             *OrderListLeg
+}
+
+// Symbol set symbol
+func (s *CreateOrderListOCOService) Symbol(symbol string) *CreateOrderListOCOService {
+	s.symbol = symbol
+	return s
+}
+
+// Side set side
+func (s *CreateOrderListOCOService) Side(side SideType) *CreateOrderListOCOService {
+	s.side = side
+	return s
+}
+
+// Quantity set quantity of both legs
+func (s *CreateOrderListOCOService) Quantity(quantity string) *CreateOrderListOCOService {
+	s.quantity = quantity
+	return s
+}
+
+// ListClientOrderID set listClientOrderId
+func (s *CreateOrderListOCOService) ListClientOrderID(listClientOrderID string) *CreateOrderListOCOService {
+	s.listClientOrderID = &listClientOrderID
+	return s
+}
+
+// NewOrderRespType set newOrderRespType
+func (s *CreateOrderListOCOService) NewOrderRespType(newOrderRespType NewOrderRespType) *CreateOrderListOCOService {
+	s.newOrderRespType = &newOrderRespType
+	return s
+}
+
+// Above set the leg above the last price
+func (s *CreateOrderListOCOService) Above(leg OrderListLeg) *CreateOrderListOCOService {
+	s.above = &leg
+	return s
+}
+
+// Below set the leg below the last price
+func (s *CreateOrderListOCOService) Below(leg OrderListLeg) *CreateOrderListOCOService {
+	s.below = &leg
+	return s
+}
+
+// Do send request
+func (s *CreateOrderListOCOService) Do(ctx context.Context, opts ...RequestOption) (res *OrderList, err error) {
+	m := params{
+		"symbol":   s.symbol,
+		"side":     s.side,
+		"quantity": s.quantity,
+	}
+	if s.listClientOrderID != nil {
+		m["listClientOrderId"] = *s.listClientOrderID
+	}
+	if s.newOrderRespType != nil {
+		m["newOrderRespType"] = *s.newOrderRespType
+	}
+	s.above.setParams("above", m)
+	s.below.setParams("below", m)
+	return createOrderList(ctx, s.c, "/api/v3/orderList/oco", m, opts...)
+}
+
+// CreateOrderListOTOService create an OTO, the pending order is placed once
+// the working order is filled
+type CreateOrderListOTOService struct {
+	c                 *Client
+	symbol            string
+	listClientOrderID *string
+	newOrderRespType  *NewOrderRespType
+	working           *OrderListLeg
+	pending           *OrderListLeg
+}
+
+// Symbol set symbol
+func (s *CreateOrderListOTOService) Symbol(symbol string) *CreateOrderListOTOService {
+	s.symbol = symbol
+	return s
+}
+
+// ListClientOrderID set listClientOrderId
+func (s *CreateOrderListOTOService) ListClientOrderID(listClientOrderID string) *CreateOrderListOTOService {
+	s.listClientOrderID = &listClientOrderID
+	return s
+}
+
+// NewOrderRespType set newOrderRespType
+func (s *CreateOrderListOTOService) NewOrderRespType(newOrderRespType NewOrderRespType) *CreateOrderListOTOService {
+	s.newOrderRespType = &newOrderRespType
+	return s
+}
+
+// Working set the working order, a LIMIT or LIMIT_MAKER order
+func (s *CreateOrderListOTOService) Working(leg OrderListLeg) *CreateOrderListOTOService {
+	s.working = &leg
+	return s
+}
+
+// Pending set the pending order
+func (s *CreateOrderListOTOService) Pending(leg OrderListLeg) *CreateOrderListOTOService {
+	s.pending = &leg
+	return s
+}
+
+// Do send request
+func (s *CreateOrderListOTOService) Do(ctx context.Context, opts ...RequestOption) (res *OrderList, err error) {
+	m := params{
+		"symbol": s.symbol,
+	}
+	if s.listClientOrderID != nil {
+		m["listClientOrderId"] = *s.listClientOrderID
+	}
+	if s.newOrderRespType != nil {
+		m["newOrderRespType"] = *s.newOrderRespType
+	}
+	s.working.setParams("working", m)
+	s.pending.setParams("pending", m)
+	return createOrderList(ctx, s.c, "/api/v3/orderList/oto", m, opts...)
+}
+
+// CreateOrderListOTOCOService create an OTOCO, the pending OCO is placed
+// once the working order is filled
+type CreateOrderListOTOCOService struct {
+	c                 *Client
+	symbol            string
+	listClientOrderID *string
+	newOrderRespType  *NewOrderRespType
+	working           *OrderListLeg
+	pendingSide       SideType
+	pendingQuantity   string
+	pendingAbove      *OrderListLeg
+	pendingBelow      *OrderListLeg
+}
+
+// Symbol set symbol
+func (s *CreateOrderListOTOCOService) Symbol(symbol string) *CreateOrderListOTOCOService {
+	s.symbol = symbol
+	return s
+}
+
+// ListClientOrderID set listClientOrderId
+func (s *CreateOrderListOTOCOService) ListClientOrderID(listClientOrderID string) *CreateOrderListOTOCOService {
+	s.listClientOrderID = &listClientOrderID
+	return s
+}
+
+// NewOrderRespType set newOrderRespType
+func (s *CreateOrderListOTOCOService) NewOrderRespType(newOrderRespType NewOrderRespType) *CreateOrderListOTOCOService {
+	s.newOrderRespType = &newOrderRespType
+	return s
+}
+
+// Working set the working order, a LIMIT or LIMIT_MAKER order
+func (s *CreateOrderListOTOCOService) Working(leg OrderListLeg) *CreateOrderListOTOCOService {
+	s.working = &leg
+	return s
+}
+
+// PendingSide set pendingSide, the side of both pending legs
+func (s *CreateOrderListOTOCOService) PendingSide(pendingSide SideType) *CreateOrderListOTOCOService {
+	s.pendingSide = pendingSide
+	return s
+}
+
+// PendingQuantity set pendingQuantity, the quantity of both pending legs
+func (s *CreateOrderListOTOCOService) PendingQuantity(pendingQuantity string) *CreateOrderListOTOCOService {
+	s.pendingQuantity = pendingQuantity
+	return s
+}
+
+// PendingAbove set the pending leg above the last price
+func (s *CreateOrderListOTOCOService) PendingAbove(leg OrderListLeg) *CreateOrderListOTOCOService {
+	s.pendingAbove = &leg
+	return s
+}
+
+// PendingBelow set the pending leg below the last price
+func (s *CreateOrderListOTOCOService) PendingBelow(leg OrderListLeg) *CreateOrderListOTOCOService {
+	s.pendingBelow = &leg
+	return s
+}
+
+// Do send request
+func (s *CreateOrderListOTOCOService) Do(ctx context.Context, opts ...RequestOption) (res *OrderList, err error) {
+	m := params{
+		"symbol":          s.symbol,
+		"pendingSide":     s.pendingSide,
+		"pendingQuantity": s.pendingQuantity,
+	}
+	if s.listClientOrderID != nil {
+		m["listClientOrderId"] = *s.listClientOrderID
+	}
+	if s.newOrderRespType != nil {
+		m["newOrderRespType"] = *s.newOrderRespType
+	}
+	s.working.setParams("working", m)
+	s.pendingAbove.setParams("pendingAbove", m)
+	s.pendingBelow.setParams("pendingBelow", m)
+	return createOrderList(ctx, s.c, "/api/v3/orderList/otoco", m, opts...)
+}
+
+// GetOrderListService get an order list
+type GetOrderListService struct {
+	c                 *Client
+	orderListID       *int64
+	origClientOrderID *string
+}
+
+// OrderListID set orderListId
+func (s *GetOrderListService) OrderListID(orderListID int64) *GetOrderListService {
+	s.orderListID = &orderListID
+	return s
+}
+
+// OrigClientOrderID set origClientOrderId, the listClientOrderId of the list
+func (s *GetOrderListService) OrigClientOrderID(origClientOrderID string) *GetOrderListService {
+	s.origClientOrderID = &origClientOrderID
+	return s
+}
+
+// Do send request
+func (s *GetOrderListService) Do(ctx context.Context, opts ...RequestOption) (res *OrderList, err error) {
+	r := &request{
+		method:   http.MethodGet,
+		endpoint: "/api/v3/orderList",
+		secType:  secTypeSigned,
+	}
+	if s.orderListID != nil {
+		r.setParam("orderListId", *s.orderListID)
+	}
+	if s.origClientOrderID != nil {
+		r.setParam("origClientOrderId", *s.origClientOrderID)
+	}
+	data, err := s.c.callAPI(ctx, r, opts...)
+	if err != nil {
+		return nil, err
+	}
+	res = new(OrderList)
+	err = json.Unmarshal(data, res)
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// ListOrderListsService list all the order lists, open or not
+type ListOrderListsService struct {
+	c         *Client
+	fromID    *int64
+	startTime *int64
+	endTime   *int64
+	limit     *int
+}
+
+// FromID set fromId, it cannot be combined with startTime or endTime
+func (s *ListOrderListsService) FromID(fromID int64) *ListOrderListsService {
+	s.fromID = &fromID
+	return s
+}
+
+// StartTime set startTime
+func (s *ListOrderListsService) StartTime(startTime int64) *ListOrderListsService {
+	s.startTime = &startTime
+	return s
+}
+
+// EndTime set endTime
+func (s *ListOrderListsService) EndTime(endTime int64) *ListOrderListsService {
+	s.endTime = &endTime
+	return s
+}
+
+// Limit set limit
+func (s *ListOrderListsService) Limit(limit int) *ListOrderListsService {
+	s.limit = &limit
+	return s
+}
+
+// Do send request
+func (s *ListOrderListsService) Do(ctx context.Context, opts ...RequestOption) (res []*OrderList, err error) {
+	r := &request{
+		method:   http.MethodGet,
+		endpoint: "/api/v3/allOrderList",
+		secType:  secTypeSigned,
+	}
+	if s.fromID != nil {
+		r.setParam("fromId", *s.fromID)
+	}
+	if s.startTime != nil {
+		r.setParam("startTime", *s.startTime)
+	}
+	if s.endTime != nil {
+		r.setParam("endTime", *s.endTime)
+	}
+	if s.limit != nil {
+		r.setParam("limit", *s.limit)
+	}
+	data, err := s.c.callAPI(ctx, r, opts...)
+	if err != nil {
+		return []*OrderList{}, err
+	}
+	res = make([]*OrderList, 0)
+	err = json.Unmarshal(data, &res)
+	if err != nil {
+		return []*OrderList{}, err
+	}
+	return res, nil
+}
+
+// OrderList define an order list, OrderReports are only returned on creation
+// and cancellation
+type OrderList struct {
+	OrderListID       int64               `json:"orderListId"`
+	ContingencyType   ContingencyType     `json:"contingencyType"`
+	ListStatusType    ListStatusType      `json:"listStatusType"`
+	ListOrderStatus   ListOrderStatusType `json:"listOrderStatus"`
+	ListClientOrderID string              `json:"listClientOrderId"`
+	TransactionTime   int64               `json:"transactionTime"`
+	Symbol            string              `json:"symbol"`
+	Orders            []*OCOOrder         `json:"orders"`
+	OrderReports      []*OCOOrderReport   `json:"orderReports"`
+}
@@ -0,0 +1,280 @@
+package binance
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type orderListServiceTestSuite struct {
+	baseOrderTestSuite
+}
+
+func TestOrderListService(t *testing.T) {
+	suite.Run(t, new(orderListServiceTestSuite))
+}
+
+func (s *orderListServiceTestSuite) TestCreateOCO() {
+	data := []byte(`{
+		"orderListId": 1,
+		"contingencyType": "OCO",
+		"listStatusType": "EXEC_STARTED",
+		"listOrderStatus": "EXECUTING",
+		"listClientOrderId": "lH1YDkuQKWiXVXHPSKYEIp",
+		"transactionTime": 1710485608839,
+		"symbol": "LTCBTC",
+		"orders": [
+			{"symbol": "LTCBTC", "orderId": 10, "clientOrderId": "44nZvqpemY7sVYgPYbvPih"},
+			{"symbol": "LTCBTC", "orderId": 11, "clientOrderId": "NuMp0nVYnciDiFmVqfpBqK"}
+		],
+		"orderReports": [
+			{
+				"symbol": "LTCBTC",
+				"orderId": 10,
+				"orderListId": 1,
+				"clientOrderId": "44nZvqpemY7sVYgPYbvPih",
+				"transactTime": 1710485608839,
+				"price": "1.00000000",
+				"origQty": "5.00000000",
+				"executedQty": "0.00000000",
+				"cummulativeQuoteQty": "0.00000000",
+				"status": "NEW",
+				"timeInForce": "GTC",
+				"type": "STOP_LOSS_LIMIT",
+				"side": "SELL",
+				"stopPrice": "1.00000000"
+			},
+			{
+				"symbol": "LTCBTC",
+				"orderId": 11,
+				"orderListId": 1,
+				"clientOrderId": "NuMp0nVYnciDiFmVqfpBqK",
+				"transactTime": 1710485608839,
+				"price": "3.00000000",
+				"origQty": "5.00000000",
+				"executedQty": "0.00000000",
+				"cummulativeQuoteQty": "0.00000000",
+				"status": "NEW",
+				"timeInForce": "GTC",
+				"type": "LIMIT_MAKER",
+				"side": "SELL"
+			}
+		]
+	}`)
+	s.mockDo(data, nil)
+	defer s.assertDo()
+
+	s.assertReq(func(r *request) {
+		e := newSignedRequest().setFormParams(params{
+			"symbol":             "LTCBTC",
+			"side":               SideTypeSell,
+			"quantity":           "5",
+			"listClientOrderId":  "lH1YDkuQKWiXVXHPSKYEIp",
+			"aboveType":          OrderTypeLimitMaker,
+			"abovePrice":         "3",
+			"belowType":          OrderTypeStopLossLimit,
+			"belowPrice":         "1",
+			"belowStopPrice":     "1",
+			"belowTimeInForce":   TimeInForceTypeGTC,
+			"belowClientOrderId": "44nZvqpemY7sVYgPYbvPih",
+			"belowStrategyId":    int64(7),
+			"belowStrategyType":  int64(1000000),
+			"newOrderRespType":   NewOrderRespTypeRESULT,
+			"aboveIcebergQty":    "1",
+			"aboveTrailingDelta": "100",
+			"belowIcebergQty":    "1",
+			"belowTrailingDelta": "100",
+		})
+		s.assertRequestEqual(e, r)
+	})
+
+	res, err := s.client.NewCreateOrderListOCOService().Symbol("LTCBTC").Side(SideTypeSell).
+		Quantity("5").ListClientOrderID("lH1YDkuQKWiXVXHPSKYEIp").NewOrderRespType(NewOrderRespTypeRESULT).
+		Above(OrderListLeg{Type: OrderTypeLimitMaker, Price: "3", IcebergQuantity: "1", TrailingDelta: "100"}).
+		Below(OrderListLeg{
+			Type:            OrderTypeStopLossLimit,
+			Price:           "1",
+			StopPrice:       "1",
+			TimeInForce:     TimeInForceTypeGTC,
+			ClientOrderID:   "44nZvqpemY7sVYgPYbvPih",
+			StrategyID:      7,
+			StrategyType:    1000000,
+			IcebergQuantity: "1",
+			TrailingDelta:   "100",
+		}).
+		Do(newContext())
+	r := s.r()
+	r.NoError(err)
+	r.Equal(int64(1), res.OrderListID)
+	r.Equal(ContingencyTypeOCO, res.ContingencyType)
+	r.Equal(ListStatusTypeExecStarted, res.ListStatusType)
+	r.Equal(ListOrderStatusTypeExecuting, res.ListOrderStatus)
+	r.Len(res.Orders, 2)
+	r.Len(res.OrderReports, 2)
+	s.assertOCOOrderEqual(&OCOOrder{Symbol: "LTCBTC", OrderID: 11, ClientOrderID: "NuMp0nVYnciDiFmVqfpBqK"}, res.Orders[1])
+	r.Equal(OrderTypeStopLossLimit, res.OrderReports[0].Type)
+	r.Equal("1.00000000", res.OrderReports[0].StopPrice)
+}
+
+func (s *orderListServiceTestSuite) TestCreateOTO() {
+	data := []byte(`{
+		"orderListId": 2,
+		"contingencyType": "OTO",
+		"listStatusType": "EXEC_STARTED",
+		"listOrderStatus": "EXECUTING",
+		"listClientOrderId": "uTQ5dUf8AFZyvRDJkXa1Ui",
+		"transactionTime": 1712289389158,
+		"symbol": "LTCBTC",
+		"orders": [
+			{"symbol": "LTCBTC", "orderId": 12, "clientOrderId": "working"},
+			{"symbol": "LTCBTC", "orderId": 13, "clientOrderId": "pending"}
+		]
+	}`)
+	s.mockDo(data, nil)
+	defer s.assertDo()
+
+	s.assertReq(func(r *request) {
+		e := newSignedRequest().setFormParams(params{
+			"symbol":               "LTCBTC",
+			"workingType":          OrderTypeLimit,
+			"workingSide":          SideTypeBuy,
+			"workingPrice":         "1",
+			"workingQuantity":      "1",
+			"workingTimeInForce":   TimeInForceTypeGTC,
+			"workingClientOrderId": "working",
+			"pendingType":          OrderTypeMarket,
+			"pendingSide":          SideTypeSell,
+			"pendingQuantity":      "1",
+			"pendingClientOrderId": "pending",
+		})
+		s.assertRequestEqual(e, r)
+	})
+
+	res, err := s.client.NewCreateOrderListOTOService().Symbol("LTCBTC").
+		Working(OrderListLeg{Type: OrderTypeLimit, Side: SideTypeBuy, Price: "1", Quantity: "1", TimeInForce: TimeInForceTypeGTC, ClientOrderID: "working"}).
+		Pending(OrderListLeg{Type: OrderTypeMarket, Side: SideTypeSell, Quantity: "1", ClientOrderID: "pending"}).
+		Do(newContext())
+	r := s.r()
+	r.NoError(err)
+	r.Equal(ContingencyTypeOTO, res.ContingencyType)
+	r.Equal(int64(13), res.Orders[1].OrderID)
+}
+
+func (s *orderListServiceTestSuite) TestCreateOTOCO() {
+	data := []byte(`{
+		"orderListId": 3,
+		"contingencyType": "OTO",
+		"listStatusType": "EXEC_STARTED",
+		"listOrderStatus": "EXECUTING",
+		"listClientOrderId": "list",
+		"transactionTime": 1712291372842,
+		"symbol": "LTCBTC",
+		"orders": [
+			{"symbol": "LTCBTC", "orderId": 14, "clientOrderId": "a"},
+			{"symbol": "LTCBTC", "orderId": 15, "clientOrderId": "b"},
+			{"symbol": "LTCBTC", "orderId": 16, "clientOrderId": "c"}
+		]
+	}`)
+	s.mockDo(data, nil)
+	defer s.assertDo()
+
+	s.assertReq(func(r *request) {
+		e := newSignedRequest().setFormParams(params{
+			"symbol":                "LTCBTC",
+			"listClientOrderId":     "list",
+			"workingType":           OrderTypeLimit,
+			"workingSide":           SideTypeBuy,
+			"workingPrice":          "1.5",
+			"workingQuantity":       "1",
+			"workingTimeInForce":    TimeInForceTypeGTC,
+			"pendingSide":           SideTypeSell,
+			"pendingQuantity":       "1",
+			"pendingAboveType":      OrderTypeLimitMaker,
+			"pendingAbovePrice":     "2",
+			"pendingBelowType":      OrderTypeStopLoss,
+			"pendingBelowStopPrice": "1",
+		})
+		s.assertRequestEqual(e, r)
+	})
+
+	res, err := s.client.NewCreateOrderListOTOCOService().Symbol("LTCBTC").ListClientOrderID("list").
+		Working(OrderListLeg{Type: OrderTypeLimit, Side: SideTypeBuy, Price: "1.5", Quantity: "1", TimeInForce: TimeInForceTypeGTC}).
+		PendingSide(SideTypeSell).PendingQuantity("1").
+		PendingAbove(OrderListLeg{Type: OrderTypeLimitMaker, Price: "2"}).
+		PendingBelow(OrderListLeg{Type: OrderTypeStopLoss, StopPrice: "1"}).
+		Do(newContext())
+	r := s.r()
+	r.NoError(err)
+	r.Len(res.Orders, 3)
+	r.Equal("list", res.ListClientOrderID)
+}
+
+func (s *orderListServiceTestSuite) TestGetOrderList() {
+	data := []byte(`{
+		"orderListId": 27,
+		"contingencyType": "OCO",
+		"listStatusType": "EXEC_STARTED",
+		"listOrderStatus": "EXECUTING",
+		"listClientOrderId": "h2USkA5YQpaXHPIrkd96xE",
+		"transactionTime": 1565245656253,
+		"symbol": "LTCBTC",
+		"orders": [
+			{"symbol": "LTCBTC", "orderId": 4, "clientOrderId": "qD1gy3kc3Gx0rihm9Y3xwS"},
+			{"symbol": "LTCBTC", "orderId": 5, "clientOrderId": "ARzZ9I00CPM8i3NhmU9Ega"}
+		]
+	}`)
+	s.mockDo(data, nil)
+	defer s.assertDo()
+
+	s.assertReq(func(r *request) {
+		e := newSignedRequest().setParams(params{
+			"orderListId": int64(27),
+		})
+		s.assertRequestEqual(e, r)
+	})
+
+	res, err := s.client.NewGetOrderListService().OrderListID(27).Do(newContext())
+	r := s.r()
+	r.NoError(err)
+	r.Equal(int64(27), res.OrderListID)
+	r.Equal(int64(1565245656253), res.TransactionTime)
+	r.Len(res.Orders, 2)
+	r.Empty(res.OrderReports)
+}
+
+func (s *orderListServiceTestSuite) TestListOrderLists() {
+	data := []byte(`[
+		{
+			"orderListId": 29,
+			"contingencyType": "OCO",
+			"listStatusType": "ALL_DONE",
+			"listOrderStatus": "ALL_DONE",
+			"listClientOrderId": "amEEAXryFzFwYF1FeRpUoZ",
+			"transactionTime": 1565245913483,
+			"symbol": "LTCBTC",
+			"orders": [
+				{"symbol": "LTCBTC", "orderId": 4, "clientOrderId": "oD7aesZqjEGlZrbtRpy5zB"},
+				{"symbol": "LTCBTC", "orderId": 5, "clientOrderId": "Jr1h6xirOxgeJOUuYQS7V3"}
+			]
+		}
+	]`)
+	s.mockDo(data, nil)
+	defer s.assertDo()
+
+	s.assertReq(func(r *request) {
+		e := newSignedRequest().setParams(params{
+			"startTime": int64(1565245000000),
+			"endTime":   int64(1565246000000),
+			"limit":     10,
+		})
+		s.assertRequestEqual(e, r)
+	})
+
+	res, err := s.client.NewListOrderListsService().StartTime(1565245000000).
+		EndTime(1565246000000).Limit(10).Do(newContext())
+	r := s.r()
+	r.NoError(err)
+	r.Len(res, 1)
+	r.Equal(ListStatusTypeAllDone, res[0].ListStatusType)
+	r.Equal(ListOrderStatusTypeAllDone, res[0].ListOrderStatus)
+}
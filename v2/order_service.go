@@ -3,7 +3,11 @@ package binance
 import (
 	"context"
 	stdjson "encoding/json"
+	"fmt"
 	"net/http"
+	"time"
+
+	"github.com/vv1zard/go-binance/v2/common"
 )
 
 // CreateOrderService create order
@@ -21,6 +25,9 @@ type CreateOrderService struct {
 	stopPrice        *string
 	trailingDelta    *string
 	icebergQuantity  *string
+	strategyID       *int64
+	strategyType     *int64
+	stpMode          *SelfTradePreventionModeType
 }
 
 // Symbol set symbol
@@ -95,7 +102,34 @@ func (s *CreateOrderService) NewOrderRespType(newOrderRespType NewOrderRespType)
 	return s
 }
 
+// StrategyID set strategyId
+func (s *CreateOrderService) StrategyID(strategyID int64) *CreateOrderService {
+	s.strategyID = &strategyID
+	return s
+}
+
+// StrategyType set strategyType, values below 1000000 are reserved
+func (s *CreateOrderService) StrategyType(strategyType int64) *CreateOrderService {
+	s.strategyType = &strategyType
+	return s
+}
+
+// SelfTradePreventionMode set selfTradePreventionMode
+func (s *CreateOrderService) SelfTradePreventionMode(mode SelfTradePreventionModeType) *CreateOrderService {
+	s.stpMode = &mode
+	return s
+}
+
 func (s *CreateOrderService) createOrder(ctx context.Context, endpoint string, opts ...RequestOption) (data []byte, err error) {
+	data, err = s.c.callAPI(ctx, s.buildRequest(endpoint), opts...)
+	if err != nil {
+		return []byte{}, err
+	}
+	return data, nil
+}
+
+// buildRequest build the request, shared with the websocket API
+func (s *CreateOrderService) buildRequest(endpoint string) *request {
 	r := &request{
 		method:   http.MethodPost,
 		endpoint: endpoint,
@@ -133,12 +167,17 @@ func (s *CreateOrderService) createOrder(ctx context.Context, endpoint string, o
 	if s.newOrderRespType != nil {
 		m["newOrderRespType"] = *s.newOrderRespType
 	}
-	r.setFormParams(m)
-	data, err = s.c.callAPI(ctx, r, opts...)
-	if err != nil {
-		return []byte{}, err
+	if s.strategyID != nil {
+		m["strategyId"] = *s.strategyID
 	}
-	return data, nil
+	if s.strategyType != nil {
+		m["strategyType"] = *s.strategyType
+	}
+	if s.stpMode != nil {
+		m["selfTradePreventionMode"] = *s.stpMode
+	}
+	r.setFormParams(m)
+	return r
 }
 
 // Do send request
@@ -161,6 +200,23 @@ func (s *CreateOrderService) Test(ctx context.Context, opts ...RequestOption) (e
 	return err
 }
 
+// TestCommissionRates is like Test but also return the commission rates the
+// order would pay
+func (s *CreateOrderService) TestCommissionRates(ctx context.Context, opts ...RequestOption) (res *OrderTestResponse, err error) {
+	r := s.buildRequest("/api/v3/order/test")
+	r.setFormParam("computeCommissionRates", true)
+	data, err := s.c.callAPI(ctx, r, opts...)
+	if err != nil {
+		return nil, err
+	}
+	res = new(OrderTestResponse)
+	err = json.Unmarshal(data, res)
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
 // CreateOrderResponse define create order response
 type CreateOrderResponse struct {
 	Symbol                   string `json:"symbol"`
@@ -172,6 +228,9 @@ type CreateOrderResponse struct {
 	ExecutedQuantity         string `json:"executedQty"`
 	CummulativeQuoteQuantity string `json:"cummulativeQuoteQty"`
 	IsIsolated               bool   `json:"isIsolated"` // for isolated margin
+	WorkingTime              int64  `json:"workingTime"`
+
+	SelfTradePreventionMode SelfTradePreventionModeType `json:"selfTradePreventionMode"`
 
 	Status      OrderStatusType `json:"status"`
 	TimeInForce TimeInForceType `json:"timeInForce"`
@@ -184,6 +243,26 @@ type CreateOrderResponse struct {
 	MarginBuyBorrowAsset  string  `json:"marginBuyBorrowAsset"`
 }
 
+// PriceDecimal parse Price as an exact decimal
+func (c *CreateOrderResponse) PriceDecimal() (common.Decimal, error) {
+	return common.ParseDecimal(c.Price)
+}
+
+// OrigQuantityDecimal parse OrigQuantity as an exact decimal
+func (c *CreateOrderResponse) OrigQuantityDecimal() (common.Decimal, error) {
+	return common.ParseDecimal(c.OrigQuantity)
+}
+
+// ExecutedQuantityDecimal parse ExecutedQuantity as an exact decimal
+func (c *CreateOrderResponse) ExecutedQuantityDecimal() (common.Decimal, error) {
+	return common.ParseDecimal(c.ExecutedQuantity)
+}
+
+// CummulativeQuoteQuantityDecimal parse CummulativeQuoteQuantity as an exact decimal
+func (c *CreateOrderResponse) CummulativeQuoteQuantityDecimal() (common.Decimal, error) {
+	return common.ParseDecimal(c.CummulativeQuoteQuantity)
+}
+
 // Fill may be returned in an array of fills in a CreateOrderResponse.
 type Fill struct {
 	TradeID         int    `json:"tradeId"`
@@ -191,9 +270,56 @@ type Fill struct {
 	Quantity        string `json:"qty"`
 	Commission      string `json:"commission"`
 	CommissionAsset string `json:"commissionAsset"`
+	// for SOR orders
+	MatchType string `json:"matchType"`
+	AllocID   int64  `json:"allocId"`
+}
+
+// PriceDecimal parse Price as an exact decimal
+func (f *Fill) PriceDecimal() (common.Decimal, error) {
+	return common.ParseDecimal(f.Price)
+}
+
+// QuantityDecimal parse Quantity as an exact decimal
+func (f *Fill) QuantityDecimal() (common.Decimal, error) {
+	return common.ParseDecimal(f.Quantity)
+}
+
+// CommissionDecimal parse Commission as an exact decimal
+func (f *Fill) CommissionDecimal() (common.Decimal, error) {
+	return common.ParseDecimal(f.Commission)
+}
+
+// OrderTestResponse define the commission rates an order would pay, returned
+// by the order tests
+type OrderTestResponse struct {
+	StandardCommissionForOrder *CommissionRates    `json:"standardCommissionForOrder"`
+	SpecialCommissionForOrder  *CommissionRates    `json:"specialCommissionForOrder"`
+	TaxCommissionForOrder      *CommissionRates    `json:"taxCommissionForOrder"`
+	Discount                   *CommissionDiscount `json:"discount"`
+}
+
+// CommissionRates define commission rates, Buyer and Seller are only set for
+// the account commission
+type CommissionRates struct {
+	Maker  string `json:"maker"`
+	Taker  string `json:"taker"`
+	Buyer  string `json:"buyer,omitempty"`
+	Seller string `json:"seller,omitempty"`
+}
+
+// CommissionDiscount define the discount on the standard commission when
+// paying it with DiscountAsset
+type CommissionDiscount struct {
+	EnabledForAccount bool   `json:"enabledForAccount"`
+	EnabledForSymbol  bool   `json:"enabledForSymbol"`
+	DiscountAsset     string `json:"discountAsset"`
+	Discount          string `json:"discount"`
 }
 
 // CreateOCOService create order
+//
+// Deprecated: use CreateOrderListOCOService, Binance deprecated /api/v3/order/oco
 type CreateOCOService struct {
 	c                    *Client
 	symbol               string
@@ -410,7 +536,7 @@ type Oco struct {
 func (s *ListOpenOcoService) Do(ctx context.Context, opts ...RequestOption) (res []*Oco, err error) {
 	r := &request{
 		method:   http.MethodGet,
-		endpoint: "/api/v3/openOrderList ",
+		endpoint: "/api/v3/openOrderList",
 		secType:  secTypeSigned,
 	}
 	data, err := s.c.callAPI(ctx, r, opts...)
@@ -487,6 +613,19 @@ func (s *GetOrderService) OrigClientOrderID(origClientOrderID string) *GetOrderS
 
 // Do send request
 func (s *GetOrderService) Do(ctx context.Context, opts ...RequestOption) (res *Order, err error) {
+	data, err := s.c.callAPI(ctx, s.buildRequest(), opts...)
+	if err != nil {
+		return nil, err
+	}
+	res = new(Order)
+	err = json.Unmarshal(data, res)
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func (s *GetOrderService) buildRequest() *request {
 	r := &request{
 		method:   http.MethodGet,
 		endpoint: "/api/v3/order",
@@ -499,16 +638,7 @@ func (s *GetOrderService) Do(ctx context.Context, opts ...RequestOption) (res *O
 	if s.origClientOrderID != nil {
 		r.setParam("origClientOrderId", *s.origClientOrderID)
 	}
-	data, err := s.c.callAPI(ctx, r, opts...)
-	if err != nil {
-		return nil, err
-	}
-	res = new(Order)
-	err = json.Unmarshal(data, res)
-	if err != nil {
-		return nil, err
-	}
-	return res, nil
+	return r
 }
 
 // Order define order info
@@ -532,6 +662,39 @@ type Order struct {
 	IsWorking                bool            `json:"isWorking"`
 	IsIsolated               bool            `json:"isIsolated"`
 	OrigQuoteOrderQuantity   string          `json:"origQuoteOrderQty"`
+	WorkingTime              int64           `json:"workingTime"`
+	StrategyID               int64           `json:"strategyId"`
+	StrategyType             int64           `json:"strategyType"`
+
+	SelfTradePreventionMode SelfTradePreventionModeType `json:"selfTradePreventionMode"`
+	// only set for orders expired by self-trade prevention
+	PreventedMatchID  int64  `json:"preventedMatchId"`
+	PreventedQuantity string `json:"preventedQuantity"`
+}
+
+// PriceDecimal parse Price as an exact decimal
+func (o *Order) PriceDecimal() (common.Decimal, error) {
+	return common.ParseDecimal(o.Price)
+}
+
+// OrigQuantityDecimal parse OrigQuantity as an exact decimal
+func (o *Order) OrigQuantityDecimal() (common.Decimal, error) {
+	return common.ParseDecimal(o.OrigQuantity)
+}
+
+// ExecutedQuantityDecimal parse ExecutedQuantity as an exact decimal
+func (o *Order) ExecutedQuantityDecimal() (common.Decimal, error) {
+	return common.ParseDecimal(o.ExecutedQuantity)
+}
+
+// CummulativeQuoteQuantityDecimal parse CummulativeQuoteQuantity as an exact decimal
+func (o *Order) CummulativeQuoteQuantityDecimal() (common.Decimal, error) {
+	return common.ParseDecimal(o.CummulativeQuoteQuantity)
+}
+
+// StopPriceDecimal parse StopPrice as an exact decimal
+func (o *Order) StopPriceDecimal() (common.Decimal, error) {
+	return common.ParseDecimal(o.StopPrice)
 }
 
 // ListOrdersService all account orders; active, canceled, or filled
@@ -606,6 +769,37 @@ func (s *ListOrdersService) Do(ctx context.Context, opts ...RequestOption) (res
 	return res, nil
 }
 
+// Iterate walk the orders of the symbol from StartTime, or from OrderID, up to
+// EndTime with as many requests as needed, Limit sets the page size
+func (s *ListOrdersService) Iterate(opts ...RequestOption) *common.Iterator[*Order] {
+	p := common.Pager[*Order]{
+		Fetch: func(ctx context.Context, q common.PageQuery) ([]*Order, error) {
+			page := &ListOrdersService{c: s.c, symbol: s.symbol, limit: &q.Limit}
+			if q.FromID != nil {
+				page.orderID = q.FromID
+			} else {
+				page.startTime, page.endTime = &q.StartTime, &q.EndTime
+			}
+			return page.Do(ctx, opts...)
+		},
+		Limit:     1000,
+		MaxWindow: 24 * time.Hour,
+		Time:      func(order *Order) int64 { return order.Time },
+		ID:        func(order *Order) int64 { return order.OrderID },
+		FromID:    s.orderID,
+	}
+	if s.limit != nil {
+		p.Limit = *s.limit
+	}
+	if s.startTime != nil {
+		p.StartTime = *s.startTime
+	}
+	if s.endTime != nil {
+		p.EndTime = *s.endTime
+	}
+	return p.Iterator()
+}
+
 // CancelOrderService cancel an order
 type CancelOrderService struct {
 	c                 *Client
@@ -641,6 +835,19 @@ func (s *CancelOrderService) NewClientOrderID(newClientOrderID string) *CancelOr
 
 // Do send request
 func (s *CancelOrderService) Do(ctx context.Context, opts ...RequestOption) (res *CancelOrderResponse, err error) {
+	data, err := s.c.callAPI(ctx, s.buildRequest(), opts...)
+	if err != nil {
+		return nil, err
+	}
+	res = new(CancelOrderResponse)
+	err = json.Unmarshal(data, res)
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func (s *CancelOrderService) buildRequest() *request {
 	r := &request{
 		method:   http.MethodDelete,
 		endpoint: "/api/v3/order",
@@ -656,11 +863,159 @@ func (s *CancelOrderService) Do(ctx context.Context, opts ...RequestOption) (res
 	if s.newClientOrderID != nil {
 		r.setFormParam("newClientOrderId", *s.newClientOrderID)
 	}
-	data, err := s.c.callAPI(ctx, r, opts...)
+	return r
+}
+
+// CancelReplaceOrderService cancel an order and place a new order on the same
+// symbol in a single request
+type CancelReplaceOrderService struct {
+	c                       *Client
+	order                   CreateOrderService
+	cancelReplaceMode       CancelReplaceModeType
+	cancelOrderID           *int64
+	cancelOrigClientOrderID *string
+	cancelNewClientOrderID  *string
+	cancelRestrictions      *CancelRestrictionsType
+}
+
+// Symbol set symbol
+func (s *CancelReplaceOrderService) Symbol(symbol string) *CancelReplaceOrderService {
+	s.order.Symbol(symbol)
+	return s
+}
+
+// CancelReplaceMode set cancelReplaceMode
+func (s *CancelReplaceOrderService) CancelReplaceMode(cancelReplaceMode CancelReplaceModeType) *CancelReplaceOrderService {
+	s.cancelReplaceMode = cancelReplaceMode
+	return s
+}
+
+// CancelOrderID set cancelOrderId
+func (s *CancelReplaceOrderService) CancelOrderID(cancelOrderID int64) *CancelReplaceOrderService {
+	s.cancelOrderID = &cancelOrderID
+	return s
+}
+
+// CancelOrigClientOrderID set cancelOrigClientOrderId
+func (s *CancelReplaceOrderService) CancelOrigClientOrderID(cancelOrigClientOrderID string) *CancelReplaceOrderService {
+	s.cancelOrigClientOrderID = &cancelOrigClientOrderID
+	return s
+}
+
+// CancelNewClientOrderID set cancelNewClientOrderId
+func (s *CancelReplaceOrderService) CancelNewClientOrderID(cancelNewClientOrderID string) *CancelReplaceOrderService {
+	s.cancelNewClientOrderID = &cancelNewClientOrderID
+	return s
+}
+
+// CancelRestrictions set cancelRestrictions
+func (s *CancelReplaceOrderService) CancelRestrictions(cancelRestrictions CancelRestrictionsType) *CancelReplaceOrderService {
+	s.cancelRestrictions = &cancelRestrictions
+	return s
+}
+
+// Side set side
+func (s *CancelReplaceOrderService) Side(side SideType) *CancelReplaceOrderService {
+	s.order.Side(side)
+	return s
+}
+
+// Type set type
+func (s *CancelReplaceOrderService) Type(orderType OrderType) *CancelReplaceOrderService {
+	s.order.Type(orderType)
+	return s
+}
+
+// TimeInForce set timeInForce
+func (s *CancelReplaceOrderService) TimeInForce(timeInForce TimeInForceType) *CancelReplaceOrderService {
+	s.order.TimeInForce(timeInForce)
+	return s
+}
+
+// Quantity set quantity
+func (s *CancelReplaceOrderService) Quantity(quantity string) *CancelReplaceOrderService {
+	s.order.Quantity(quantity)
+	return s
+}
+
+// QuoteOrderQty set quoteOrderQty
+func (s *CancelReplaceOrderService) QuoteOrderQty(quoteOrderQty string) *CancelReplaceOrderService {
+	s.order.QuoteOrderQty(quoteOrderQty)
+	return s
+}
+
+// Price set price
+func (s *CancelReplaceOrderService) Price(price string) *CancelReplaceOrderService {
+	s.order.Price(price)
+	return s
+}
+
+// NewClientOrderID set newClientOrderId
+func (s *CancelReplaceOrderService) NewClientOrderID(newClientOrderID string) *CancelReplaceOrderService {
+	s.order.NewClientOrderID(newClientOrderID)
+	return s
+}
+
+// StopPrice set stopPrice
+func (s *CancelReplaceOrderService) StopPrice(stopPrice string) *CancelReplaceOrderService {
+	s.order.StopPrice(stopPrice)
+	return s
+}
+
+// TrailingDelta set trailingDelta
+func (s *CancelReplaceOrderService) TrailingDelta(trailingDelta string) *CancelReplaceOrderService {
+	s.order.TrailingDelta(trailingDelta)
+	return s
+}
+
+// IcebergQuantity set icebergQty
+func (s *CancelReplaceOrderService) IcebergQuantity(icebergQuantity string) *CancelReplaceOrderService {
+	s.order.IcebergQuantity(icebergQuantity)
+	return s
+}
+
+// NewOrderRespType set newOrderRespType
+func (s *CancelReplaceOrderService) NewOrderRespType(newOrderRespType NewOrderRespType) *CancelReplaceOrderService {
+	s.order.NewOrderRespType(newOrderRespType)
+	return s
+}
+
+// StrategyID set strategyId
+func (s *CancelReplaceOrderService) StrategyID(strategyID int64) *CancelReplaceOrderService {
+	s.order.StrategyID(strategyID)
+	return s
+}
+
+// StrategyType set strategyType
+func (s *CancelReplaceOrderService) StrategyType(strategyType int64) *CancelReplaceOrderService {
+	s.order.StrategyType(strategyType)
+	return s
+}
+
+// SelfTradePreventionMode set selfTradePreventionMode
+func (s *CancelReplaceOrderService) SelfTradePreventionMode(mode SelfTradePreventionModeType) *CancelReplaceOrderService {
+	s.order.SelfTradePreventionMode(mode)
+	return s
+}
+
+// Do send request. When the cancel or the new order fails, the error is a
+// *CancelReplaceError and res still reports the result of both steps.
+func (s *CancelReplaceOrderService) Do(ctx context.Context, opts ...RequestOption) (res *CancelReplaceOrderResponse, err error) {
+	data, err := s.c.callAPI(ctx, s.buildRequest(), opts...)
 	if err != nil {
-		return nil, err
+		apiErr, ok := common.AsAPIError(err)
+		if !ok || len(data) == 0 {
+			return nil, err
+		}
+		failure := new(struct {
+			Data *CancelReplaceOrderResponse `json:"data"`
+		})
+		if json.Unmarshal(data, failure) != nil || failure.Data == nil {
+			return nil, err
+		}
+		return failure.Data, &CancelReplaceError{APIError: apiErr, Response: failure.Data}
 	}
-	res = new(CancelOrderResponse)
+	res = new(CancelReplaceOrderResponse)
 	err = json.Unmarshal(data, res)
 	if err != nil {
 		return nil, err
@@ -668,6 +1023,92 @@ func (s *CancelOrderService) Do(ctx context.Context, opts ...RequestOption) (res
 	return res, nil
 }
 
+// CancelReplaceError is returned when the cancel or the new order of a
+// cancel-replace failed, Response reports the result of both steps. Binance
+// sends -2021 when exactly one of the two steps failed, and -2022 when both
+// failed or when the cancel failed with STOP_ON_FAILURE.
+type CancelReplaceError struct {
+	*common.APIError
+	Response *CancelReplaceOrderResponse
+}
+
+// Error return the result of both steps and the message
+func (e *CancelReplaceError) Error() string {
+	return fmt.Sprintf("<CancelReplaceError> code=%d, cancel=%s, newOrder=%s, msg=%s",
+		e.Code, e.Response.CancelResult, e.Response.NewOrderResult, e.Message)
+}
+
+// Unwrap return the APIError, so the error is classified like any API error
+func (e *CancelReplaceError) Unwrap() error {
+	return e.APIError
+}
+
+func (s *CancelReplaceOrderService) buildRequest() *request {
+	r := s.order.buildRequest("/api/v3/order/cancelReplace")
+	r.keepErrorBody = true
+	r.setFormParam("cancelReplaceMode", s.cancelReplaceMode)
+	if s.cancelOrderID != nil {
+		r.setFormParam("cancelOrderId", *s.cancelOrderID)
+	}
+	if s.cancelOrigClientOrderID != nil {
+		r.setFormParam("cancelOrigClientOrderId", *s.cancelOrigClientOrderID)
+	}
+	if s.cancelNewClientOrderID != nil {
+		r.setFormParam("cancelNewClientOrderId", *s.cancelNewClientOrderID)
+	}
+	if s.cancelRestrictions != nil {
+		r.setFormParam("cancelRestrictions", *s.cancelRestrictions)
+	}
+	return r
+}
+
+// CancelReplaceOrderResponse define cancel replace order response. The
+// response of a failed step is an APIError in CancelError or NewOrderError,
+// the response of a step not attempted is nil.
+type CancelReplaceOrderResponse struct {
+	CancelResult     CancelReplaceResultType `json:"cancelResult"`
+	NewOrderResult   CancelReplaceResultType `json:"newOrderResult"`
+	CancelResponse   *CancelOrderResponse    `json:"-"`
+	CancelError      *common.APIError        `json:"-"`
+	NewOrderResponse *CreateOrderResponse    `json:"-"`
+	NewOrderError    *common.APIError        `json:"-"`
+}
+
+// UnmarshalJSON decode the response of each step according to its result
+func (r *CancelReplaceOrderResponse) UnmarshalJSON(data []byte) error {
+	raw := new(struct {
+		CancelResult     CancelReplaceResultType `json:"cancelResult"`
+		NewOrderResult   CancelReplaceResultType `json:"newOrderResult"`
+		CancelResponse   stdjson.RawMessage      `json:"cancelResponse"`
+		NewOrderResponse stdjson.RawMessage      `json:"newOrderResponse"`
+	})
+	if err := json.Unmarshal(data, raw); err != nil {
+		return err
+	}
+	*r = CancelReplaceOrderResponse{CancelResult: raw.CancelResult, NewOrderResult: raw.NewOrderResult}
+	if err := unmarshalCancelReplaceStep(raw.CancelResult, raw.CancelResponse, &r.CancelResponse, &r.CancelError); err != nil {
+		return err
+	}
+	return unmarshalCancelReplaceStep(raw.NewOrderResult, raw.NewOrderResponse, &r.NewOrderResponse, &r.NewOrderError)
+}
+
+// unmarshalCancelReplaceStep decode the response of a step into res when it
+// succeeded or into apiErr when it failed
+func unmarshalCancelReplaceStep[T any](result CancelReplaceResultType, data []byte, res **T, apiErr **common.APIError) error {
+	if len(data) == 0 || string(data) == "null" {
+		return nil
+	}
+	switch result {
+	case CancelReplaceResultSuccess:
+		*res = new(T)
+		return json.Unmarshal(data, *res)
+	case CancelReplaceResultFailure:
+		*apiErr = new(common.APIError)
+		return json.Unmarshal(data, *apiErr)
+	}
+	return nil
+}
+
 // CancelOCOService cancel all active orders on the list order.
 type CancelOCOService struct {
 	c                 *Client
@@ -1,9 +1,15 @@
 package binance
 
 import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/suite"
+	"github.com/vv1zard/go-binance/v2/common"
 )
 
 type baseOrderTestSuite struct {
@@ -169,6 +175,47 @@ func (s *orderServiceTestSuite) TestCreateOrderFull() {
 	s.r().NoError(err)
 }
 
+func (s *orderServiceTestSuite) TestCreateOrderTestCommissionRates() {
+	data := []byte(`{
+		"standardCommissionForOrder": {"maker": "0.00000112", "taker": "0.00000114"},
+		"taxCommissionForOrder": {"maker": "0.00000112", "taker": "0.00000114"},
+		"specialCommissionForOrder": {"maker": "0.05000000", "taker": "0.06000000"},
+		"discount": {
+			"enabledForAccount": true,
+			"enabledForSymbol": true,
+			"discountAsset": "BNB",
+			"discount": "0.25000000"
+		}
+	}`)
+	s.mockDo(data, nil)
+	defer s.assertDo()
+	s.assertReq(func(r *request) {
+		e := newSignedRequest().setFormParams(params{
+			"symbol":                  "BTCUSDT",
+			"side":                    SideTypeBuy,
+			"type":                    OrderTypeLimit,
+			"timeInForce":             TimeInForceTypeGTC,
+			"quantity":                "0.1",
+			"price":                   "42000",
+			"strategyId":              37,
+			"strategyType":            1000000,
+			"selfTradePreventionMode": SelfTradePreventionModeExpireBoth,
+			"computeCommissionRates":  true,
+		})
+		s.assertRequestEqual(e, r)
+	})
+	res, err := s.client.NewCreateOrderService().Symbol("BTCUSDT").Side(SideTypeBuy).
+		Type(OrderTypeLimit).TimeInForce(TimeInForceTypeGTC).Quantity("0.1").Price("42000").
+		StrategyID(37).StrategyType(1000000).SelfTradePreventionMode(SelfTradePreventionModeExpireBoth).
+		TestCommissionRates(newContext())
+	r := s.r()
+	r.NoError(err)
+	r.Equal(&CommissionRates{Maker: "0.00000112", Taker: "0.00000114"}, res.StandardCommissionForOrder)
+	r.Equal(&CommissionRates{Maker: "0.00000112", Taker: "0.00000114"}, res.TaxCommissionForOrder)
+	r.Equal(&CommissionRates{Maker: "0.05000000", Taker: "0.06000000"}, res.SpecialCommissionForOrder)
+	r.Equal("0.25000000", res.Discount.Discount)
+}
+
 func (s *baseOrderTestSuite) assertCreateOrderResponseEqual(e, a *CreateOrderResponse) {
 	r := s.r()
 	r.Equal(e.Symbol, a.Symbol, "Symbol")
@@ -668,6 +715,39 @@ func (s *orderServiceTestSuite) TestListOrders() {
 	s.assertOrderEqual(e, orders[0])
 }
 
+func (s *orderServiceTestSuite) TestListOrdersIterate() {
+	var queries []string
+	s.client.Client.do = func(req *http.Request) (*http.Response, error) {
+		query := req.URL.Query()
+		queries = append(queries, fmt.Sprintf("orderId=%s startTime=%s endTime=%s limit=%s",
+			query.Get("orderId"), query.Get("startTime"), query.Get("endTime"), query.Get("limit")))
+		// Orders 1 to 5 were placed every second from 1499827319000
+		from := int64(1)
+		if query.Get("orderId") != "" {
+			from, _ = strconv.ParseInt(query.Get("orderId"), 10, 64)
+		}
+		var orders []string
+		for id := from; id <= 5 && len(orders) < 2; id++ {
+			orders = append(orders, fmt.Sprintf(`{"symbol": "LTCBTC", "orderId": %d, "time": %d}`, id, 1499827318000+id*1000))
+		}
+		return newHTTPResponse([]byte("["+strings.Join(orders, ",")+"]"), http.StatusOK), nil
+	}
+	it := s.client.NewListOrdersService().Symbol("LTCBTC").Limit(2).
+		StartTime(1499827318500).EndTime(1499827322500).Iterate()
+	var ids []int64
+	for it.Next(newContext()) {
+		ids = append(ids, it.Item().OrderID)
+	}
+	r := s.r()
+	r.NoError(it.Err())
+	r.Equal([]int64{1, 2, 3, 4}, ids)
+	r.Equal([]string{
+		"orderId= startTime=1499827318500 endTime=1499827322500 limit=2",
+		"orderId=3 startTime= endTime= limit=2",
+		"orderId=5 startTime= endTime= limit=2",
+	}, queries)
+}
+
 func (s *orderServiceTestSuite) TestCancelOCO() {
 	data := []byte(`{
 		"orderListId":1000,
@@ -840,6 +920,150 @@ func (s *orderServiceTestSuite) TestCancelOrder() {
 	s.assertCancelOrderResponseEqual(e, res)
 }
 
+func (s *orderServiceTestSuite) TestCancelReplaceOrder() {
+	data := []byte(`{
+		"cancelResult": "SUCCESS",
+		"newOrderResult": "SUCCESS",
+		"cancelResponse": {
+			"symbol": "BTCUSDT",
+			"origClientOrderId": "DnLo3vTAQcjha43lAZhZ0y",
+			"orderId": 9,
+			"orderListId": -1,
+			"clientOrderId": "osxN3JXAtJvKvCqGeMWMVR",
+			"price": "0.01000000",
+			"origQty": "0.000100",
+			"executedQty": "0.00000000",
+			"cummulativeQuoteQty": "0.00000000",
+			"status": "CANCELED",
+			"timeInForce": "GTC",
+			"type": "LIMIT",
+			"side": "SELL"
+		},
+		"newOrderResponse": {
+			"symbol": "BTCUSDT",
+			"orderId": 10,
+			"clientOrderId": "wOceeeOzNORyLiQfw7jd8S",
+			"transactTime": 1652928801803,
+			"price": "0.02000000",
+			"origQty": "0.040000",
+			"executedQty": "0.00000000",
+			"cummulativeQuoteQty": "0.00000000",
+			"status": "NEW",
+			"timeInForce": "GTC",
+			"type": "LIMIT",
+			"side": "BUY",
+			"fills": []
+		}
+	}`)
+	s.mockDo(data, nil)
+	defer s.assertDo()
+
+	s.assertReq(func(r *request) {
+		e := newSignedRequest().setFormParams(params{
+			"symbol":                  "BTCUSDT",
+			"side":                    SideTypeBuy,
+			"type":                    OrderTypeLimit,
+			"timeInForce":             TimeInForceTypeGTC,
+			"quantity":                "0.04",
+			"price":                   "0.02",
+			"cancelReplaceMode":       CancelReplaceModeStopOnFailure,
+			"cancelOrigClientOrderId": "DnLo3vTAQcjha43lAZhZ0y",
+			"cancelRestrictions":      CancelRestrictionsOnlyNew,
+		})
+		s.assertRequestEqual(e, r)
+	})
+
+	res, err := s.client.NewCancelReplaceOrderService().Symbol("BTCUSDT").
+		CancelReplaceMode(CancelReplaceModeStopOnFailure).
+		CancelOrigClientOrderID("DnLo3vTAQcjha43lAZhZ0y").CancelRestrictions(CancelRestrictionsOnlyNew).
+		Side(SideTypeBuy).Type(OrderTypeLimit).TimeInForce(TimeInForceTypeGTC).
+		Quantity("0.04").Price("0.02").Do(newContext())
+	r := s.r()
+	r.NoError(err)
+	r.Equal(CancelReplaceResultSuccess, res.CancelResult)
+	r.Equal(CancelReplaceResultSuccess, res.NewOrderResult)
+	r.Nil(res.CancelError)
+	r.Nil(res.NewOrderError)
+	s.assertCancelOrderResponseEqual(&CancelOrderResponse{
+		Symbol:                   "BTCUSDT",
+		OrigClientOrderID:        "DnLo3vTAQcjha43lAZhZ0y",
+		OrderID:                  9,
+		OrderListID:              -1,
+		ClientOrderID:            "osxN3JXAtJvKvCqGeMWMVR",
+		Price:                    "0.01000000",
+		OrigQuantity:             "0.000100",
+		ExecutedQuantity:         "0.00000000",
+		CummulativeQuoteQuantity: "0.00000000",
+		Status:                   OrderStatusTypeCanceled,
+		TimeInForce:              TimeInForceTypeGTC,
+		Type:                     OrderTypeLimit,
+		Side:                     SideTypeSell,
+	}, res.CancelResponse)
+	r.Equal(int64(10), res.NewOrderResponse.OrderID)
+	r.Equal(OrderStatusTypeNew, res.NewOrderResponse.Status)
+}
+
+func (s *orderServiceTestSuite) TestCancelReplaceOrderPartialFailure() {
+	data := []byte(`{
+		"code": -2021,
+		"msg": "Order cancel-replace partially failed.",
+		"data": {
+			"cancelResult": "SUCCESS",
+			"newOrderResult": "FAILURE",
+			"cancelResponse": {
+				"symbol": "BTCUSDT",
+				"origClientOrderId": "86M8erehfExV8z2RC8Zo8k",
+				"orderId": 3,
+				"orderListId": -1,
+				"clientOrderId": "G1kLo6aDv2KGNTFcjfTSFq",
+				"price": "0.006123",
+				"origQty": "10000.000000",
+				"executedQty": "0.000000",
+				"cummulativeQuoteQty": "0.000000",
+				"status": "CANCELED",
+				"timeInForce": "GTC",
+				"type": "LIMIT_MAKER",
+				"side": "SELL"
+			},
+			"newOrderResponse": {
+				"code": -2010,
+				"msg": "Order would immediately match and take."
+			}
+		}
+	}`)
+	s.mockDo(data, nil, http.StatusConflict)
+	defer s.assertDo()
+
+	res, err := s.client.NewCancelReplaceOrderService().Symbol("BTCUSDT").
+		CancelReplaceMode(CancelReplaceModeAllowFailure).CancelOrderID(3).
+		Side(SideTypeSell).Type(OrderTypeLimitMaker).Quantity("10000").Price("0.0061").Do(newContext())
+	r := s.r()
+	r.Error(err)
+	var cancelReplaceErr *CancelReplaceError
+	r.True(errors.As(err, &cancelReplaceErr))
+	r.Equal(common.ErrorCode(-2021), cancelReplaceErr.Code)
+	r.Equal(http.StatusConflict, cancelReplaceErr.StatusCode)
+	r.Same(res, cancelReplaceErr.Response)
+	var apiErr *common.APIError
+	r.True(errors.As(err, &apiErr))
+	r.Same(cancelReplaceErr.APIError, apiErr)
+	r.True(common.IsAPIError(err))
+	r.NotNil(res)
+	r.Equal(CancelReplaceResultSuccess, res.CancelResult)
+	r.Equal(CancelReplaceResultFailure, res.NewOrderResult)
+	r.Equal(int64(3), res.CancelResponse.OrderID)
+	r.Nil(res.NewOrderResponse)
+	r.Equal(common.CodeNewOrderRejected, res.NewOrderError.Code)
+	r.Equal("Order would immediately match and take.", res.NewOrderError.Message)
+
+	// Errors without results are returned as is
+	s.SetupTest()
+	s.mockDo([]byte(`{"code": -1102, "msg": "Mandatory parameter 'cancelReplaceMode' was not sent."}`), nil, http.StatusBadRequest)
+	res, err = s.client.NewCancelReplaceOrderService().Symbol("BTCUSDT").Do(newContext())
+	r.Nil(res)
+	r.True(common.IsAPIError(err))
+}
+
 func (s *orderServiceTestSuite) TestCancelOpenOrders() {
 	data := []byte(`[
 		{
@@ -0,0 +1,163 @@
+package binance
+
+import (
+	"fmt"
+
+	"github.com/vv1zard/go-binance/v2/common"
+)
+
+// OrderValidator check orders against the filters of their symbol before
+// they are sent, so orders which would be rejected with -1013 do not cost
+// request weight. Failures are returned as *common.FilterError.
+//
+// PERCENT_PRICE and the MIN_NOTIONAL of market orders given in base quantity
+// depend on the average price and are left to the server.
+type OrderValidator struct {
+	// Round snap prices and quantities off their tick or step size instead of
+	// rejecting the order. Quantities are rounded down, prices are rounded
+	// down for buys and up for sells.
+	Round bool
+	// OpenAlgoOrders return the number of open algo orders of symbol,
+	// MAX_NUM_ALGO_ORDERS is not checked when nil
+	OpenAlgoOrders func(symbol string) int
+
+	symbols map[string]*Symbol
+}
+
+// NewOrderValidator init an order validator with the symbols of a cached
+// exchange info
+func NewOrderValidator(info *ExchangeInfo) *OrderValidator {
+	symbols := make(map[string]*Symbol, len(info.Symbols))
+	for i := range info.Symbols {
+		symbols[info.Symbols[i].Symbol] = &info.Symbols[i]
+	}
+	return &OrderValidator{symbols: symbols}
+}
+
+// orderParams point to the parameters of an order service, nil when unset,
+// so rounded values are written back to the service
+type orderParams struct {
+	symbol          string
+	side            SideType
+	orderType       OrderType
+	quantity        *string
+	quoteOrderQty   *string
+	price           *string
+	stopPrice       *string
+	icebergQuantity *string
+}
+
+// ValidateOrder validate a spot order, rounding its price and quantities
+// in place when Round is set
+func (v *OrderValidator) ValidateOrder(s *CreateOrderService) error {
+	return v.validate(&orderParams{
+		symbol:          s.symbol,
+		side:            s.side,
+		orderType:       s.orderType,
+		quantity:        s.quantity,
+		quoteOrderQty:   s.quoteOrderQty,
+		price:           s.price,
+		stopPrice:       s.stopPrice,
+		icebergQuantity: s.icebergQuantity,
+	})
+}
+
+// ValidateMarginOrder validate a margin order, rounding its price and
+// quantities in place when Round is set
+func (v *OrderValidator) ValidateMarginOrder(s *CreateMarginOrderService) error {
+	return v.validate(&orderParams{
+		symbol:          s.symbol,
+		side:            s.side,
+		orderType:       s.orderType,
+		quantity:        s.quantity,
+		quoteOrderQty:   s.quoteOrderQty,
+		price:           s.price,
+		stopPrice:       s.stopPrice,
+		icebergQuantity: s.icebergQuantity,
+	})
+}
+
+func (v *OrderValidator) validate(o *orderParams) error {
+	symbol, ok := v.symbols[o.symbol]
+	if !ok {
+		return fmt.Errorf("%w: %s", common.ErrSymbolNotFound, o.symbol)
+	}
+	priceMode := common.RoundDown
+	if o.side == SideTypeSell {
+		priceMode = common.RoundUp
+	}
+	if f := symbol.PriceFilter(); f != nil {
+		filter := common.StepFilter{Filter: string(SymbolFilterTypePriceFilter), Min: f.MinPrice, Max: f.MaxPrice, Step: f.TickSize}
+		if err := v.apply(filter, o.symbol, "price", o.price, priceMode); err != nil {
+			return err
+		}
+		if err := v.apply(filter, o.symbol, "stopPrice", o.stopPrice, priceMode); err != nil {
+			return err
+		}
+	}
+	if f := symbol.LotSizeFilter(); f != nil {
+		filter := common.StepFilter{Filter: string(SymbolFilterTypeLotSize), Min: f.MinQuantity, Max: f.MaxQuantity, Step: f.StepSize}
+		if err := v.apply(filter, o.symbol, "quantity", o.quantity, common.RoundDown); err != nil {
+			return err
+		}
+		if err := v.apply(filter, o.symbol, "icebergQty", o.icebergQuantity, common.RoundDown); err != nil {
+			return err
+		}
+	}
+	if f := symbol.MarketLotSizeFilter(); f != nil && o.orderType == OrderTypeMarket {
+		filter := common.StepFilter{Filter: string(SymbolFilterTypeMarketLotSize), Min: f.MinQuantity, Max: f.MaxQuantity, Step: f.StepSize}
+		if err := v.apply(filter, o.symbol, "quantity", o.quantity, common.RoundDown); err != nil {
+			return err
+		}
+	}
+	if f := symbol.MinNotionalFilter(); f != nil {
+		filter := string(SymbolFilterTypeMinNotional)
+		switch {
+		case o.price != nil && o.quantity != nil:
+			if err := common.CheckMinNotional(o.symbol, filter, *o.price, *o.quantity, f.MinNotional); err != nil {
+				return err
+			}
+		case o.orderType == OrderTypeMarket && o.quoteOrderQty != nil && f.ApplyToMarket:
+			// quoteOrderQty is the notional itself
+			minNotional := common.StepFilter{Filter: filter, Min: f.MinNotional}
+			if err := v.apply(minNotional, o.symbol, "quoteOrderQty", o.quoteOrderQty, common.RoundDown); err != nil {
+				return err
+			}
+		}
+	}
+	if f := symbol.IcebergPartsFilter(); f != nil && o.icebergQuantity != nil && o.quantity != nil {
+		err := common.CheckIcebergParts(o.symbol, string(SymbolFilterTypeIcebergParts), *o.quantity, *o.icebergQuantity, f.Limit)
+		if err != nil {
+			return err
+		}
+	}
+	if f := symbol.MaxNumAlgoOrdersFilter(); f != nil && v.OpenAlgoOrders != nil && isAlgoOrder(o.orderType) {
+		err := common.CheckMaxNumAlgoOrders(o.symbol, string(SymbolFilterTypeMaxNumAlgoOrders), v.OpenAlgoOrders(o.symbol), f.MaxNumAlgoOrders)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// apply check a parameter against filter, writing the rounded value back
+func (v *OrderValidator) apply(filter common.StepFilter, symbol, param string, value *string, mode common.RoundingMode) error {
+	if value == nil {
+		return nil
+	}
+	res, err := filter.Apply(symbol, param, *value, v.Round, mode)
+	if err != nil {
+		return err
+	}
+	*value = res
+	return nil
+}
+
+// isAlgoOrder tell whether orders of type t count toward MAX_NUM_ALGO_ORDERS
+func isAlgoOrder(t OrderType) bool {
+	switch t {
+	case OrderTypeStopLoss, OrderTypeStopLossLimit, OrderTypeTakeProfit, OrderTypeTakeProfitLimit:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,115 @@
+package binance
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/vv1zard/go-binance/v2/common"
+)
+
+type orderValidatorTestSuite struct {
+	baseTestSuite
+	validator *OrderValidator
+}
+
+func TestOrderValidator(t *testing.T) {
+	suite.Run(t, new(orderValidatorTestSuite))
+}
+
+func (s *orderValidatorTestSuite) SetupTest() {
+	s.baseTestSuite.SetupTest()
+	s.validator = NewOrderValidator(&ExchangeInfo{
+		Symbols: []Symbol{
+			{
+				Symbol: "BTCUSDT",
+				Filters: []map[string]interface{}{
+					{"filterType": "PRICE_FILTER", "minPrice": "0.01000000", "maxPrice": "1000000.00000000", "tickSize": "0.01000000"},
+					{"filterType": "LOT_SIZE", "minQty": "0.00001000", "maxQty": "9000.00000000", "stepSize": "0.00001000"},
+					{"filterType": "MARKET_LOT_SIZE", "minQty": "0.00000000", "maxQty": "100.00000000", "stepSize": "0.00000000"},
+					{"filterType": "MIN_NOTIONAL", "minNotional": "10.00000000", "applyToMarket": true, "avgPriceMins": float64(5)},
+					{"filterType": "ICEBERG_PARTS", "limit": float64(10)},
+					{"filterType": "MAX_NUM_ALGO_ORDERS", "maxNumAlgoOrders": float64(5)},
+				},
+			},
+		},
+	})
+}
+
+func (s *orderValidatorTestSuite) assertFilterError(err error, filter SymbolFilterType, param string) {
+	r := s.r()
+	var filterErr *common.FilterError
+	r.True(errors.As(err, &filterErr), "unexpected error %v", err)
+	r.Equal(string(filter), filterErr.Filter)
+	r.Equal(param, filterErr.Param)
+	r.True(common.IsFilterFailure(err))
+}
+
+func (s *orderValidatorTestSuite) newOrder() *CreateOrderService {
+	return s.client.NewCreateOrderService().Symbol("BTCUSDT").Side(SideTypeBuy).
+		Type(OrderTypeLimit).TimeInForce(TimeInForceTypeGTC)
+}
+
+func (s *orderValidatorTestSuite) TestValid() {
+	s.r().NoError(s.validator.ValidateOrder(s.newOrder().Price("43187.01").Quantity("0.00100")))
+	s.r().NoError(s.validator.ValidateOrder(s.newOrder().Type(OrderTypeMarket).QuoteOrderQty("10")))
+}
+
+func (s *orderValidatorTestSuite) TestReject() {
+	err := s.validator.ValidateOrder(s.newOrder().Price("43187.015").Quantity("0.001"))
+	s.assertFilterError(err, SymbolFilterTypePriceFilter, "price")
+
+	err = s.validator.ValidateOrder(s.newOrder().Price("43187.01").Quantity("0.0010001"))
+	s.assertFilterError(err, SymbolFilterTypeLotSize, "quantity")
+
+	err = s.validator.ValidateOrder(s.newOrder().Price("1000").Quantity("0.00999"))
+	s.assertFilterError(err, SymbolFilterTypeMinNotional, "notional")
+
+	err = s.validator.ValidateOrder(s.newOrder().Type(OrderTypeMarket).Quantity("100.1"))
+	s.assertFilterError(err, SymbolFilterTypeMarketLotSize, "quantity")
+
+	err = s.validator.ValidateOrder(s.newOrder().Type(OrderTypeMarket).QuoteOrderQty("9.99"))
+	s.assertFilterError(err, SymbolFilterTypeMinNotional, "quoteOrderQty")
+
+	err = s.validator.ValidateOrder(s.newOrder().Price("50000").Quantity("1").IcebergQuantity("0.09"))
+	s.assertFilterError(err, SymbolFilterTypeIcebergParts, "icebergQty")
+
+	err = s.validator.ValidateOrder(s.newOrder().Symbol("ETHUSDT"))
+	s.r().True(errors.Is(err, common.ErrSymbolNotFound))
+}
+
+func (s *orderValidatorTestSuite) TestRound() {
+	s.validator.Round = true
+	buy := s.newOrder().Price("43187.019").Quantity("0.0012345")
+	s.r().NoError(s.validator.ValidateOrder(buy))
+	s.r().Equal("43187.01", *buy.price)
+	s.r().Equal("0.00123", *buy.quantity)
+
+	sell := s.newOrder().Side(SideTypeSell).Price("43187.011").Quantity("0.0012345")
+	s.r().NoError(s.validator.ValidateOrder(sell))
+	s.r().Equal("43187.02", *sell.price)
+
+	// Rounding can not fix a quantity below the min
+	err := s.validator.ValidateOrder(s.newOrder().Price("43187.01").Quantity("0.000001"))
+	s.assertFilterError(err, SymbolFilterTypeLotSize, "quantity")
+}
+
+func (s *orderValidatorTestSuite) TestMaxNumAlgoOrders() {
+	open := 5
+	s.validator.OpenAlgoOrders = func(symbol string) int {
+		return open
+	}
+	stop := s.newOrder().Type(OrderTypeStopLossLimit).Price("40000").StopPrice("40100").Quantity("0.001")
+	err := s.validator.ValidateOrder(stop)
+	s.assertFilterError(err, SymbolFilterTypeMaxNumAlgoOrders, "openAlgoOrders")
+	s.r().NoError(s.validator.ValidateOrder(s.newOrder().Price("40000").Quantity("0.001")))
+	open = 4
+	s.r().NoError(s.validator.ValidateOrder(stop))
+}
+
+func (s *orderValidatorTestSuite) TestMarginOrder() {
+	order := s.client.NewCreateMarginOrderService().Symbol("BTCUSDT").Side(SideTypeBuy).
+		Type(OrderTypeLimit).Price("43187.015").Quantity("0.001")
+	err := s.validator.ValidateMarginOrder(order)
+	s.assertFilterError(err, SymbolFilterTypePriceFilter, "price")
+}
@@ -0,0 +1,58 @@
+package portfolio
+
+import (
+	"strings"
+	"time"
+
+	"github.com/vv1zard/go-binance/v2/common"
+)
+
+// defaultRateLimits are the limits of the portfolio margin API, which has no
+// exchange info endpoint to seed them from
+var defaultRateLimits = []common.RateLimit{
+	{Type: common.RateLimitRequestWeight, Interval: time.Minute, Limit: 6000},
+	{Type: common.RateLimitOrders, Interval: time.Minute, Limit: 1200},
+}
+
+// requestWeights is the weight of each /papi endpoint, endpoints missing
+// from the table weigh 1. Weights depending on the parameters are computed
+// by requestWeight.
+var requestWeights = map[string]int64{
+	"GET /papi/v1/account":              20,
+	"GET /papi/v1/balance":              20,
+	"GET /papi/v1/um/account":           5,
+	"GET /papi/v1/cm/account":           5,
+	"GET /papi/v1/um/allOrders":         5,
+	"GET /papi/v1/cm/allOrders":         20,
+	"GET /papi/v1/um/positionSide/dual": 30,
+	"GET /papi/v1/cm/positionSide/dual": 30,
+}
+
+// orderCounts is the number of orders placed by each endpoint
+var orderCounts = map[string]int64{
+	"POST /papi/v1/um/order":     1,
+	"POST /papi/v1/cm/order":     1,
+	"POST /papi/v1/margin/order": 1,
+}
+
+// requestWeight return the weight and order count of r, tracked is false for
+// endpoints outside of the /papi rate limits
+func requestWeight(r *request) (weight, orders int64, tracked bool) {
+	if !strings.HasPrefix(r.endpoint, "/papi/") {
+		return 0, 0, false
+	}
+	key := r.method + " " + r.endpoint
+	orders = orderCounts[key]
+	switch key {
+	case "GET /papi/v1/um/openOrders", "GET /papi/v1/cm/openOrders":
+		if r.query.Get("symbol") != "" {
+			return 1, orders, true
+		}
+		return 40, orders, true
+	}
+	weight, ok := requestWeights[key]
+	if !ok {
+		weight = 1
+	}
+	return weight, orders, true
+}
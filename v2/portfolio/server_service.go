@@ -0,0 +1,57 @@
+package portfolio
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/vv1zard/go-binance/v2/common"
+)
+
+// PingService ping server
+type PingService struct {
+	c *Client
+}
+
+// Do send request
+func (s *PingService) Do(ctx context.Context, opts ...RequestOption) (err error) {
+	r := &request{
+		method:   http.MethodGet,
+		endpoint: "/papi/v1/ping",
+	}
+	_, _, err = s.c.callAPI(ctx, r, opts...)
+	return err
+}
+
+// ServerTimeService get server time. The portfolio margin API has no time
+// endpoint, the time is read from the Date header of a ping with a second
+// precision and centered on the middle of that second.
+type ServerTimeService struct {
+	c *Client
+}
+
+// Do send request
+func (s *ServerTimeService) Do(ctx context.Context, opts ...RequestOption) (serverTime int64, err error) {
+	r := &request{
+		method:   http.MethodGet,
+		endpoint: "/papi/v1/ping",
+	}
+	_, header, err := s.c.callAPI(ctx, r, opts...)
+	if err != nil {
+		return 0, err
+	}
+	date, err := http.ParseTime(header.Get("Date"))
+	if err != nil {
+		return 0, fmt.Errorf("invalid Date header: %w", err)
+	}
+	return date.Add(500*time.Millisecond).UnixNano() / int64(time.Millisecond), nil
+}
+
+// NewClockSync init a clock sync measuring the offset with ServerTimeService,
+// assign it to Client.ClockSync and start it to keep signed requests in the recvWindow
+func (c *Client) NewClockSync() *common.ClockSync {
+	return common.NewClockSync(func(ctx context.Context) (int64, error) {
+		return c.NewServerTimeService().Do(ctx)
+	})
+}
@@ -3,7 +3,10 @@ package portfolio
 import (
 	"encoding/json"
 	"fmt"
+	"log/slog"
 	"time"
+
+	"github.com/vv1zard/go-binance/v2/common"
 )
 
 // Endpoints
@@ -19,6 +22,13 @@ var (
 	WebsocketTimeout = time.Second * 60
 	// WebsocketKeepalive enables sending ping/pong messages to check the connection stability
 	WebsocketKeepalive = true
+	// WebsocketReconnect makes every stream reconnect automatically when set, nil disables it
+	WebsocketReconnect *WsReconnectConfig
+	// WebsocketLogHandler receives the connection events of every stream, nil disables them
+	WebsocketLogHandler slog.Handler
+	// WebsocketMetrics receives the message count, event lag and reconnections of
+	// every stream, nil disables them
+	WebsocketMetrics common.Metrics
 	// UseTestnet switch all the WS streams from production to the testnet
 	UseTestnet      = false
 	UseTestnetOrder = false
@@ -325,8 +335,14 @@ type WsUserDataHandler func(event *WsUserDataEvent)
 
 // WsUserDataServe serve user data handler with listen key
 func WsUserDataServe(listenKey string, handler WsUserDataHandler, errHandler ErrHandler) (doneC, stopC chan struct{}, err error) {
-	endpoint := fmt.Sprintf("%s/%s", getWsEndpointOrder(), listenKey)
-	cfg := newWsConfig(endpoint)
+	return wsUserDataServe(newWsConfig(getUserDataEndpoint(listenKey)), handler, errHandler)
+}
+
+func getUserDataEndpoint(listenKey string) string {
+	return fmt.Sprintf("%s/%s", getWsEndpointOrder(), listenKey)
+}
+
+func wsUserDataServe(cfg *WsConfig, handler WsUserDataHandler, errHandler ErrHandler) (doneC, stopC chan struct{}, err error) {
 	wsHandler := func(message []byte) {
 		event := new(WsUserDataEvent)
 		err := json.Unmarshal(message, event)
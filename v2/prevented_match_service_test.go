@@ -0,0 +1,61 @@
+package binance
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type preventedMatchServiceTestSuite struct {
+	baseTestSuite
+}
+
+func TestPreventedMatchService(t *testing.T) {
+	suite.Run(t, new(preventedMatchServiceTestSuite))
+}
+
+func (s *preventedMatchServiceTestSuite) TestListPreventedMatches() {
+	data := []byte(`[
+		{
+			"symbol": "BTCUSDT",
+			"preventedMatchId": 1,
+			"takerOrderId": 5,
+			"makerSymbol": "BTCUSDT",
+			"makerOrderId": 3,
+			"tradeGroupId": 1,
+			"selfTradePreventionMode": "EXPIRE_MAKER",
+			"price": "1.100000",
+			"makerPreventedQuantity": "1.300000",
+			"transactTime": 1669101687094
+		}
+	]`)
+	s.mockDo(data, nil)
+	defer s.assertDo()
+	s.assertReq(func(r *request) {
+		e := newSignedRequest().setParams(params{
+			"symbol":               "BTCUSDT",
+			"orderId":              5,
+			"fromPreventedMatchId": 1,
+			"limit":                10,
+		})
+		s.assertRequestEqual(e, r)
+	})
+
+	res, err := s.client.NewListPreventedMatchesService().Symbol("BTCUSDT").OrderID(5).
+		FromPreventedMatchID(1).Limit(10).Do(newContext())
+	r := s.r()
+	r.NoError(err)
+	r.Len(res, 1)
+	r.Equal(&PreventedMatch{
+		Symbol:                  "BTCUSDT",
+		PreventedMatchID:        1,
+		TakerOrderID:            5,
+		MakerSymbol:             "BTCUSDT",
+		MakerOrderID:            3,
+		TradeGroupID:            1,
+		SelfTradePreventionMode: SelfTradePreventionModeExpireMaker,
+		Price:                   "1.100000",
+		MakerPreventedQuantity:  "1.300000",
+		TransactTime:            1669101687094,
+	}, res[0])
+}
@@ -28,6 +28,9 @@ type request struct {
 	header     http.Header
 	body       io.Reader
 	fullURL    string
+	// keepErrorBody return the body along the APIError, for the endpoints
+	// reporting partial failures
+	keepErrorBody bool
 }
 
 // addParam add param with key/value to query string
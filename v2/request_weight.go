@@ -0,0 +1,118 @@
+package binance
+
+import (
+	"strconv"
+	"strings"
+)
+
+// requestWeights is the weight of each /api endpoint, endpoints missing
+// from the table weigh 1. Weights depending on the parameters are computed
+// by requestWeight.
+var requestWeights = map[string]int64{
+	"GET /api/v3/exchangeInfo":       20,
+	"GET /api/v1/trades":             25,
+	"GET /api/v3/trades":             25,
+	"GET /api/v3/historicalTrades":   25,
+	"GET /api/v3/aggTrades":          2,
+	"GET /api/v3/klines":             2,
+	"GET /api/v3/uiKlines":           2,
+	"GET /api/v3/avgPrice":           2,
+	"GET /api/v3/order":              4,
+	"GET /api/v3/allOrders":          20,
+	"GET /api/v3/orderList":          4,
+	"GET /api/v3/allOrderList":       20,
+	"GET /api/v3/openOrderList":      6,
+	"GET /api/v3/account":            20,
+	"GET /api/v3/myTrades":           20,
+	"GET /api/v3/rateLimit/order":    40,
+	"GET /api/v3/myAllocations":      20,
+	"GET /api/v3/account/commission": 20,
+	"POST /api/v3/userDataStream":    2,
+	"PUT /api/v3/userDataStream":     2,
+	"DELETE /api/v3/userDataStream":  2,
+}
+
+// orderCounts is the number of orders placed by each endpoint
+var orderCounts = map[string]int64{
+	"POST /api/v3/order":               1,
+	"POST /api/v3/order/oco":           2,
+	"POST /api/v3/order/cancelReplace": 1,
+	"POST /api/v3/orderList/oco":       2,
+	"POST /api/v3/orderList/oto":       2,
+	"POST /api/v3/orderList/otoco":     3,
+	"POST /api/v3/sor/order":           1,
+}
+
+// requestWeight return the weight and order count of r, tracked is false for
+// endpoints outside of the /api rate limits such as /sapi
+func requestWeight(r *request) (weight, orders int64, tracked bool) {
+	endpoint := strings.TrimSpace(r.endpoint)
+	if !strings.HasPrefix(endpoint, "/api/") {
+		return 0, 0, false
+	}
+	key := r.method + " " + endpoint
+	orders = orderCounts[key]
+	switch key {
+	case "GET /api/v3/depth":
+		return depthWeight(r.query.Get("limit")), orders, true
+	case "GET /api/v3/ticker/24hr":
+		return tickerWeight(r, 2, 80), orders, true
+	case "GET /api/v3/ticker/price", "GET /api/v3/ticker/bookTicker":
+		return tickerWeight(r, 2, 4), orders, true
+	case "GET /api/v3/ticker":
+		weight := 4 * int64(len(strings.Split(r.query.Get("symbols"), ",")))
+		if weight > 200 {
+			weight = 200
+		}
+		return weight, orders, true
+	case "GET /api/v3/openOrders":
+		return tickerWeight(r, 6, 80), orders, true
+	case "GET /api/v3/myPreventedMatches":
+		// 2 by preventedMatchId, 20 by orderId
+		if r.query.Get("preventedMatchId") != "" {
+			return 2, orders, true
+		}
+		return 20, orders, true
+	case "POST /api/v3/order/test", "POST /api/v3/sor/order/test":
+		if r.form.Get("computeCommissionRates") == "true" || r.query.Get("computeCommissionRates") == "true" {
+			return 20, orders, true
+		}
+		return 1, orders, true
+	}
+	weight, ok := requestWeights[key]
+	if !ok {
+		weight = 1
+	}
+	return weight, orders, true
+}
+
+func depthWeight(limit string) int64 {
+	n, err := strconv.Atoi(limit)
+	if err != nil {
+		n = 100
+	}
+	switch {
+	case n <= 100:
+		return 5
+	case n <= 500:
+		return 25
+	case n <= 1000:
+		return 50
+	}
+	return 250
+}
+
+// tickerWeight return single per symbol requested, capped at all which is
+// also the weight when no symbol is given
+func tickerWeight(r *request, single, all int64) int64 {
+	if r.query.Get("symbol") != "" {
+		return single
+	}
+	if symbols := r.query.Get("symbols"); symbols != "" {
+		weight := single * int64(len(strings.Split(symbols, ",")))
+		if weight < all {
+			return weight
+		}
+	}
+	return all
+}
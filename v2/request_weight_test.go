@@ -0,0 +1,87 @@
+package binance
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/vv1zard/go-binance/v2/common"
+)
+
+type requestWeightTestSuite struct {
+	baseTestSuite
+}
+
+func TestRequestWeight(t *testing.T) {
+	suite.Run(t, new(requestWeightTestSuite))
+}
+
+func (s *requestWeightTestSuite) TestRequestWeight() {
+	tests := []struct {
+		name     string
+		method   string
+		endpoint string
+		params   params
+		weight   int64
+		orders   int64
+		tracked  bool
+	}{
+		{"default", http.MethodGet, "/api/v3/ping", nil, 1, 0, true},
+		{"table", http.MethodGet, "/api/v3/account", nil, 20, 0, true},
+		{"depth", http.MethodGet, "/api/v3/depth", params{"limit": 500}, 25, 0, true},
+		{"all tickers", http.MethodGet, "/api/v3/ticker/24hr", nil, 80, 0, true},
+		{"one ticker", http.MethodGet, "/api/v3/ticker/24hr", params{"symbol": "BTCUSDT"}, 2, 0, true},
+		{"order", http.MethodPost, "/api/v3/order", nil, 1, 1, true},
+		{"oco", http.MethodPost, "/api/v3/order/oco", nil, 1, 2, true},
+		{"sor test", http.MethodPost, "/api/v3/sor/order/test", params{"computeCommissionRates": true}, 20, 0, true},
+		{"order test", http.MethodPost, "/api/v3/order/test", nil, 1, 0, true},
+		{"account commission", http.MethodGet, "/api/v3/account/commission", params{"symbol": "BTCUSDT"}, 20, 0, true},
+		{"prevented match", http.MethodGet, "/api/v3/myPreventedMatches", params{"preventedMatchId": 1}, 2, 0, true},
+		{"prevented matches", http.MethodGet, "/api/v3/myPreventedMatches", params{"orderId": 5}, 20, 0, true},
+		{"sapi", http.MethodGet, "/sapi/v1/accountSnapshot", nil, 0, 0, false},
+	}
+	for _, tt := range tests {
+		r := &request{method: tt.method, endpoint: tt.endpoint}
+		r.setParams(tt.params)
+		r.validate()
+		weight, orders, tracked := requestWeight(r)
+		s.r().Equal(tt.weight, weight, tt.name)
+		s.r().Equal(tt.orders, orders, tt.name)
+		s.r().Equal(tt.tracked, tracked, tt.name)
+	}
+}
+
+func (s *requestWeightTestSuite) TestRetryAfter() {
+	s.client.Client.do = s.client.do
+	header := http.Header{}
+	header.Set("Retry-After", "60")
+	header.Set("X-Mbx-Used-Weight-1m", "1300")
+	s.client.On("do", anyHTTPRequest()).Return(&http.Response{
+		Body:       ioutil.NopCloser(bytes.NewBufferString(`{"code":-1003,"msg":"Too many requests"}`)),
+		StatusCode: http.StatusTooManyRequests,
+		Header:     header,
+	}, nil).Once()
+	s.client.RateLimiter.FailFast = true
+
+	err := s.client.NewPingService().Do(newContext())
+	apiErr, ok := common.AsAPIError(err)
+	s.r().True(ok)
+	s.r().Equal(http.StatusTooManyRequests, apiErr.StatusCode)
+	s.r().Equal(http.MethodGet, apiErr.Method)
+	s.r().Equal("/api/v3/ping", apiErr.Endpoint)
+	s.r().Equal(int64(1), apiErr.Weight)
+	s.r().True(errors.Is(err, common.ErrTooManyRequests))
+	s.r().True(common.IsRateLimit(err))
+	err = s.client.NewPingService().Do(newContext())
+	s.r().True(errors.Is(err, common.ErrRateLimited))
+	s.client.AssertNumberOfCalls(s.T(), "do", 1)
+
+	// Endpoints outside of /api are not throttled
+	s.mockDo([]byte(`{}`), nil)
+	_, err = s.client.NewGetAccountSnapshotService().Type("SPOT").Do(newContext())
+	s.r().NoError(err)
+	s.client.AssertNumberOfCalls(s.T(), "do", 2)
+}
@@ -1,10 +1,14 @@
 package binance
 
 import (
+	"bytes"
 	"fmt"
+	"io/ioutil"
 	"net/http"
+	"strconv"
 	"testing"
 
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
 	"github.com/vv1zard/go-binance/v2/common"
 )
@@ -105,3 +109,35 @@ func (s *serverServiceTestSuite) TestSetServerTime() {
 	s.r().NotZero(s.client.TimeOffset)
 	s.r().EqualValues(timeOffset, s.client.TimeOffset)
 }
+
+func (s *serverServiceTestSuite) TestClockSyncRetry() {
+	s.client.Client.do = s.client.do
+	timestamps := []int64{}
+	respond := func(data string, statusCode int) {
+		s.client.On("do", anyHTTPRequest()).Run(func(args mock.Arguments) {
+			req := args.Get(0).(*http.Request)
+			timestamp, _ := strconv.ParseInt(req.URL.Query().Get(timestampKey), 10, 64)
+			timestamps = append(timestamps, timestamp)
+		}).Return(&http.Response{
+			Body:       ioutil.NopCloser(bytes.NewBufferString(data)),
+			StatusCode: statusCode,
+		}, nil).Once()
+	}
+	respond(`{"code":-1021,"msg":"Timestamp for this request is outside of the recvWindow."}`, http.StatusBadRequest)
+	respond(`{"serverTime": 1499827319559}`, http.StatusOK)
+	respond(`{}`, http.StatusOK)
+	s.client.ClockSync = s.client.NewClockSync()
+
+	_, err := s.client.NewGetAccountService().Do(newContext())
+	s.r().NoError(err)
+	s.client.AssertNumberOfCalls(s.T(), "do", 3)
+	s.r().True(s.client.ClockSync.Synced())
+	s.r().InDelta(1499827319559, timestamps[2], 1000)
+}
+
+func (s *serverServiceTestSuite) TestClockSyncDisabled() {
+	s.mockDo([]byte(`{"code":-1021,"msg":"Timestamp for this request is outside of the recvWindow."}`), nil, http.StatusBadRequest)
+	_, err := s.client.NewGetAccountService().Do(newContext())
+	s.r().True(common.IsInvalidTimestamp(err))
+	s.client.AssertNumberOfCalls(s.T(), "do", 1)
+}
@@ -0,0 +1,168 @@
+package binance
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type sorServiceTestSuite struct {
+	baseTestSuite
+}
+
+func TestSORService(t *testing.T) {
+	suite.Run(t, new(sorServiceTestSuite))
+}
+
+func (s *sorServiceTestSuite) TestCreateSOROrder() {
+	data := []byte(`{
+		"symbol": "BTCUSDT",
+		"orderId": 2,
+		"orderListId": -1,
+		"clientOrderId": "sBI1KM6nNtOfj5tccZSKly",
+		"transactTime": 1689149087774,
+		"price": "31000.00000000",
+		"origQty": "0.50000000",
+		"executedQty": "0.50000000",
+		"cummulativeQuoteQty": "14000.00000000",
+		"status": "FILLED",
+		"timeInForce": "GTC",
+		"type": "LIMIT",
+		"side": "BUY",
+		"workingTime": 1689149087774,
+		"fills": [
+			{
+				"matchType": "ONE_PARTY_TRADE_REPORT",
+				"price": "28000.00000000",
+				"qty": "0.50000000",
+				"commission": "0.00000000",
+				"commissionAsset": "BTC",
+				"tradeId": -1,
+				"allocId": 0
+			}
+		],
+		"workingFloor": "SOR",
+		"selfTradePreventionMode": "NONE",
+		"usedSor": true
+	}`)
+	s.mockDo(data, nil)
+	defer s.assertDo()
+
+	s.assertReq(func(r *request) {
+		e := newSignedRequest().setFormParams(params{
+			"symbol":      "BTCUSDT",
+			"side":        SideTypeBuy,
+			"type":        OrderTypeLimit,
+			"quantity":    "0.5",
+			"price":       "31000",
+			"timeInForce": TimeInForceTypeGTC,
+			"strategyId":  int64(1),
+		})
+		s.assertRequestEqual(e, r)
+	})
+
+	res, err := s.client.NewCreateSOROrderService().Symbol("BTCUSDT").Side(SideTypeBuy).
+		Type(OrderTypeLimit).Quantity("0.5").Price("31000").TimeInForce(TimeInForceTypeGTC).
+		StrategyID(1).Do(newContext())
+	r := s.r()
+	r.NoError(err)
+	r.True(res.UsedSor)
+	r.Equal("SOR", res.WorkingFloor)
+	r.Equal(int64(-1), res.OrderListID)
+	r.Equal(int64(2), res.OrderID)
+	r.Equal(OrderStatusTypeFilled, res.Status)
+	r.Len(res.Fills, 1)
+	r.Equal("ONE_PARTY_TRADE_REPORT", res.Fills[0].MatchType)
+	r.Equal("28000.00000000", res.Fills[0].Price)
+}
+
+func (s *sorServiceTestSuite) TestCreateSOROrderTest() {
+	data := []byte(`{
+		"standardCommissionForOrder": {"maker": "0.00000112", "taker": "0.00000114"},
+		"taxCommissionForOrder": {"maker": "0.00000112", "taker": "0.00000114"},
+		"discount": {
+			"enabledForAccount": true,
+			"enabledForSymbol": true,
+			"discountAsset": "BNB",
+			"discount": "0.25000000"
+		}
+	}`)
+	s.mockDo(data, nil)
+	defer s.assertDo()
+
+	s.assertReq(func(r *request) {
+		e := newSignedRequest().setFormParams(params{
+			"symbol":                 "BTCUSDT",
+			"side":                   SideTypeSell,
+			"type":                   OrderTypeMarket,
+			"quantity":               "0.1",
+			"computeCommissionRates": true,
+		})
+		s.assertRequestEqual(e, r)
+	})
+
+	res, err := s.client.NewCreateSOROrderService().Symbol("BTCUSDT").Side(SideTypeSell).
+		Type(OrderTypeMarket).Quantity("0.1").Test(newContext())
+	r := s.r()
+	r.NoError(err)
+	r.Equal(&CommissionRates{Maker: "0.00000112", Taker: "0.00000114"}, res.StandardCommissionForOrder)
+	r.Equal("0.00000114", res.TaxCommissionForOrder.Taker)
+	r.Equal(&CommissionDiscount{
+		EnabledForAccount: true,
+		EnabledForSymbol:  true,
+		DiscountAsset:     "BNB",
+		Discount:          "0.25000000",
+	}, res.Discount)
+}
+
+func (s *sorServiceTestSuite) TestListAllocations() {
+	data := []byte(`[
+		{
+			"symbol": "BTCUSDT",
+			"tradeId": -1,
+			"orderId": 12345,
+			"orderListId": -1,
+			"price": "1.00000000",
+			"qty": "0.10000000",
+			"quoteQty": "0.10000000",
+			"commission": "0.00000000",
+			"commissionAsset": "BTC",
+			"time": 1687506878118,
+			"isBuyer": false,
+			"isMaker": false,
+			"isAllocator": false,
+			"allocationId": 0,
+			"allocationType": "SOR"
+		}
+	]`)
+	s.mockDo(data, nil)
+	defer s.assertDo()
+
+	s.assertReq(func(r *request) {
+		e := newSignedRequest().setParams(params{
+			"symbol":           "BTCUSDT",
+			"orderId":          int64(12345),
+			"fromAllocationId": int64(0),
+			"limit":            100,
+		})
+		s.assertRequestEqual(e, r)
+	})
+
+	res, err := s.client.NewListAllocationsService().Symbol("BTCUSDT").OrderID(12345).
+		FromAllocationID(0).Limit(100).Do(newContext())
+	r := s.r()
+	r.NoError(err)
+	r.Equal([]*Allocation{{
+		Symbol:          "BTCUSDT",
+		TradeID:         -1,
+		OrderID:         12345,
+		OrderListID:     -1,
+		Price:           "1.00000000",
+		Quantity:        "0.10000000",
+		QuoteQuantity:   "0.10000000",
+		Commission:      "0.00000000",
+		CommissionAsset: "BTC",
+		Time:            1687506878118,
+		AllocationType:  "SOR",
+	}}, res)
+}
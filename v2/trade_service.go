@@ -3,6 +3,9 @@ package binance
 import (
 	"context"
 	"net/http"
+	"time"
+
+	"github.com/vv1zard/go-binance/v2/common"
 )
 
 // ListTradesService list trades
@@ -87,6 +90,37 @@ func (s *ListTradesService) Do(ctx context.Context, opts ...RequestOption) (res
 	return res, nil
 }
 
+// Iterate walk the trades of the symbol from StartTime, or from FromID, up to
+// EndTime with as many requests as needed, Limit sets the page size
+func (s *ListTradesService) Iterate(opts ...RequestOption) *common.Iterator[*TradeV3] {
+	p := common.Pager[*TradeV3]{
+		Fetch: func(ctx context.Context, q common.PageQuery) ([]*TradeV3, error) {
+			page := &ListTradesService{c: s.c, symbol: s.symbol, orderId: s.orderId, limit: &q.Limit}
+			if q.FromID != nil {
+				page.fromID = q.FromID
+			} else {
+				page.startTime, page.endTime = &q.StartTime, &q.EndTime
+			}
+			return page.Do(ctx, opts...)
+		},
+		Limit:     1000,
+		MaxWindow: 24 * time.Hour,
+		Time:      func(trade *TradeV3) int64 { return trade.Time },
+		ID:        func(trade *TradeV3) int64 { return trade.ID },
+		FromID:    s.fromID,
+	}
+	if s.limit != nil {
+		p.Limit = *s.limit
+	}
+	if s.startTime != nil {
+		p.StartTime = *s.startTime
+	}
+	if s.endTime != nil {
+		p.EndTime = *s.endTime
+	}
+	return p.Iterator()
+}
+
 // HistoricalTradesService trades
 type HistoricalTradesService struct {
 	c      *Client
@@ -170,6 +204,26 @@ type TradeV3 struct {
 	IsIsolated      bool   `json:"isIsolated"`
 }
 
+// PriceDecimal parse Price as an exact decimal
+func (t *TradeV3) PriceDecimal() (common.Decimal, error) {
+	return common.ParseDecimal(t.Price)
+}
+
+// QuantityDecimal parse Quantity as an exact decimal
+func (t *TradeV3) QuantityDecimal() (common.Decimal, error) {
+	return common.ParseDecimal(t.Quantity)
+}
+
+// QuoteQuantityDecimal parse QuoteQuantity as an exact decimal
+func (t *TradeV3) QuoteQuantityDecimal() (common.Decimal, error) {
+	return common.ParseDecimal(t.QuoteQuantity)
+}
+
+// CommissionDecimal parse Commission as an exact decimal
+func (t *TradeV3) CommissionDecimal() (common.Decimal, error) {
+	return common.ParseDecimal(t.Commission)
+}
+
 // AggTradesService list aggregate trades
 type AggTradesService struct {
 	c         *Client
@@ -241,6 +295,38 @@ func (s *AggTradesService) Do(ctx context.Context, opts ...RequestOption) (res [
 	return res, nil
 }
 
+// Iterate walk the aggregate trades of the symbol from StartTime, or from
+// FromID, up to EndTime with as many requests as needed, Limit sets the page
+// size
+func (s *AggTradesService) Iterate(opts ...RequestOption) *common.Iterator[*AggTrade] {
+	p := common.Pager[*AggTrade]{
+		Fetch: func(ctx context.Context, q common.PageQuery) ([]*AggTrade, error) {
+			page := &AggTradesService{c: s.c, symbol: s.symbol, limit: &q.Limit}
+			if q.FromID != nil {
+				page.fromID = q.FromID
+			} else {
+				page.startTime, page.endTime = &q.StartTime, &q.EndTime
+			}
+			return page.Do(ctx, opts...)
+		},
+		Limit:     1000,
+		MaxWindow: time.Hour,
+		Time:      func(trade *AggTrade) int64 { return trade.Timestamp },
+		ID:        func(trade *AggTrade) int64 { return trade.AggTradeID },
+		FromID:    s.fromID,
+	}
+	if s.limit != nil {
+		p.Limit = *s.limit
+	}
+	if s.startTime != nil {
+		p.StartTime = *s.startTime
+	}
+	if s.endTime != nil {
+		p.EndTime = *s.endTime
+	}
+	return p.Iterator()
+}
+
 // AggTrade define aggregate trade info
 type AggTrade struct {
 	AggTradeID       int64  `json:"a"`
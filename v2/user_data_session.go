@@ -0,0 +1,72 @@
+package binance
+
+import (
+	"context"
+
+	"github.com/vv1zard/go-binance/v2/common"
+)
+
+// NewUserDataSession init a managed session of the spot user data stream,
+// errHandler also receives the errors the session recovers from. Call Start
+// on the returned session to connect.
+func (c *Client) NewUserDataSession(handler WsUserDataHandler, errHandler ErrHandler) *common.UserDataSession {
+	return newUserDataSession(common.ListenKeyFuncs{
+		Start: func(ctx context.Context) (string, error) {
+			return c.NewStartUserStreamService().Do(ctx)
+		},
+		Keepalive: func(ctx context.Context, listenKey string) error {
+			return c.NewKeepaliveUserStreamService().ListenKey(listenKey).Do(ctx)
+		},
+		Close: func(ctx context.Context, listenKey string) error {
+			return c.NewCloseUserStreamService().ListenKey(listenKey).Do(ctx)
+		},
+	}, handler, errHandler)
+}
+
+// NewMarginUserDataSession init a managed session of the cross margin user
+// data stream, see NewUserDataSession
+func (c *Client) NewMarginUserDataSession(handler WsUserDataHandler, errHandler ErrHandler) *common.UserDataSession {
+	return newUserDataSession(common.ListenKeyFuncs{
+		Start: func(ctx context.Context) (string, error) {
+			return c.NewStartMarginUserStreamService().Do(ctx)
+		},
+		Keepalive: func(ctx context.Context, listenKey string) error {
+			return c.NewKeepaliveMarginUserStreamService().ListenKey(listenKey).Do(ctx)
+		},
+		Close: func(ctx context.Context, listenKey string) error {
+			return c.NewCloseMarginUserStreamService().ListenKey(listenKey).Do(ctx)
+		},
+	}, handler, errHandler)
+}
+
+// NewIsolatedMarginUserDataSession init a managed session of the isolated
+// margin user data stream of symbol, see NewUserDataSession
+func (c *Client) NewIsolatedMarginUserDataSession(symbol string, handler WsUserDataHandler, errHandler ErrHandler) *common.UserDataSession {
+	return newUserDataSession(common.ListenKeyFuncs{
+		Start: func(ctx context.Context) (string, error) {
+			return c.NewStartIsolatedMarginUserStreamService().Symbol(symbol).Do(ctx)
+		},
+		Keepalive: func(ctx context.Context, listenKey string) error {
+			return c.NewKeepaliveIsolatedMarginUserStreamService().Symbol(symbol).ListenKey(listenKey).Do(ctx)
+		},
+		Close: func(ctx context.Context, listenKey string) error {
+			return c.NewCloseIsolatedMarginUserStreamService().Symbol(symbol).ListenKey(listenKey).Do(ctx)
+		},
+	}, handler, errHandler)
+}
+
+// newUserDataSession serve the stream without reconnection, the session
+// reconnects itself with a fresh listen key
+func newUserDataSession(keys common.ListenKeyFuncs, handler WsUserDataHandler, errHandler ErrHandler) *common.UserDataSession {
+	session := common.NewUserDataSession(keys, func(listenKey string, expired func()) (doneC, stopC chan struct{}, err error) {
+		cfg := &WsConfig{Endpoint: getUserDataEndpoint(listenKey), LogHandler: WebsocketLogHandler, Metrics: WebsocketMetrics}
+		return wsUserDataServe(cfg, func(event *WsUserDataEvent) {
+			if event.Event == UserDataEventTypeListenKeyExpired {
+				expired()
+			}
+			handler(event)
+		}, errHandler)
+	})
+	session.OnError = errHandler
+	return session
+}
@@ -0,0 +1,445 @@
+package binance
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	stdjson "encoding/json"
+
+	"github.com/gorilla/websocket"
+	"github.com/vv1zard/go-binance/v2/common"
+)
+
+// Endpoints
+const (
+	baseWsAPIMainURL    = "wss://ws-api.binance.com:443/ws-api/v3"
+	baseWsAPITestnetURL = "wss://testnet.binance.vision/ws-api/v3"
+)
+
+// ErrWsAPIClientClosed is returned when using a WsAPIClient after Close
+var ErrWsAPIClientClosed = errors.New("websocket api client closed")
+
+// getWsAPIEndpoint return the websocket API endpoint according the UseTestnet flag
+func getWsAPIEndpoint() string {
+	if UseTestnet {
+		return baseWsAPITestnetURL
+	}
+	return baseWsAPIMainURL
+}
+
+// wsAPIIntParams are the parameters sent as JSON numbers, the others are sent as strings
+var wsAPIIntParams = map[string]bool{
+	"orderId":       true,
+	"recvWindow":    true,
+	"timestamp":     true,
+	"trailingDelta": true,
+	"strategyId":    true,
+	"strategyType":  true,
+	"cancelOrderId": true,
+}
+
+// WsAPIClient send requests over a persistent connection to the websocket API
+// instead of paying an HTTP round trip per request. Requests are correlated
+// with their response by id, so they can be sent concurrently. The
+// connection is dialed on the first request and redialed after a failure,
+// logging on again if SessionLogon succeeded before.
+type WsAPIClient struct {
+	Endpoint string
+	// Timeout bounds a request whose context has no deadline
+	Timeout time.Duration
+
+	c      *Client
+	nextID int64
+
+	mu       sync.Mutex
+	conn     *wsAPIConn
+	dialing  chan struct{}
+	loggedOn bool
+	closed   bool
+}
+
+// NewWsAPIClient init a websocket API client signing requests with the
+// credentials of c, the connection is opened on the first request
+func (c *Client) NewWsAPIClient() *WsAPIClient {
+	return &WsAPIClient{
+		Endpoint: getWsAPIEndpoint(),
+		Timeout:  10 * time.Second,
+		c:        c,
+	}
+}
+
+type wsAPIRequest struct {
+	ID     string                 `json:"id"`
+	Method string                 `json:"method"`
+	Params map[string]interface{} `json:"params,omitempty"`
+}
+
+type wsAPIResponse struct {
+	ID     string             `json:"id"`
+	Status int                `json:"status"`
+	Result stdjson.RawMessage `json:"result"`
+	Error  *common.APIError   `json:"error"`
+}
+
+// WsAPISessionStatus define the session status returned by session.logon
+type WsAPISessionStatus struct {
+	APIKey           string `json:"apiKey"`
+	AuthorizedSince  int64  `json:"authorizedSince"`
+	ConnectedSince   int64  `json:"connectedSince"`
+	ReturnRateLimits bool   `json:"returnRateLimits"`
+	ServerTime       int64  `json:"serverTime"`
+}
+
+// Call send a request with params as is and return the raw result
+func (c *WsAPIClient) Call(ctx context.Context, method string, params map[string]interface{}) (stdjson.RawMessage, error) {
+	if _, ok := ctx.Deadline(); !ok && c.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.Timeout)
+		defer cancel()
+	}
+	conn, err := c.connection(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return conn.call(ctx, c.newID(), method, params)
+}
+
+func (c *WsAPIClient) newID() string {
+	return strconv.FormatInt(atomic.AddInt64(&c.nextID, 1), 10)
+}
+
+// connection return the current connection, dialing a new one if there is
+// none or if it failed. The dial and the logon run outside of c.mu, the
+// requests arriving meanwhile wait for them.
+func (c *WsAPIClient) connection(ctx context.Context) (*wsAPIConn, error) {
+	c.mu.Lock()
+	for c.dialing != nil && !c.closed {
+		dialing := c.dialing
+		c.mu.Unlock()
+		select {
+		case <-dialing:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		c.mu.Lock()
+	}
+	if c.closed {
+		c.mu.Unlock()
+		return nil, ErrWsAPIClientClosed
+	}
+	if c.conn != nil && !c.conn.isDone() {
+		conn := c.conn
+		c.mu.Unlock()
+		return conn, nil
+	}
+	dialing := make(chan struct{})
+	c.dialing = dialing
+	loggedOn := c.loggedOn
+	c.mu.Unlock()
+
+	conn, err := c.dial(ctx, loggedOn)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.dialing = nil
+	close(dialing)
+	if err != nil {
+		return nil, err
+	}
+	if c.closed {
+		conn.close()
+		return nil, ErrWsAPIClientClosed
+	}
+	c.conn = conn
+	return conn, nil
+}
+
+// dial open a connection, logging it on when the session was logged on
+func (c *WsAPIClient) dial(ctx context.Context, loggedOn bool) (*wsAPIConn, error) {
+	ws, err := wsDial(c.Endpoint)
+	if err != nil {
+		return nil, err
+	}
+	conn := newWsAPIConn(ws)
+	if loggedOn {
+		_, err = c.logon(ctx, conn)
+		if err != nil {
+			conn.close()
+			return nil, err
+		}
+	}
+	return conn, nil
+}
+
+// SessionLogon authenticate the connection with the API key, the following
+// requests are then sent without apiKey and signature. Binance only accepts
+// Ed25519 keys for logon, see common.Ed25519Signer.
+func (c *WsAPIClient) SessionLogon(ctx context.Context) (*WsAPISessionStatus, error) {
+	conn, err := c.connection(ctx)
+	if err != nil {
+		return nil, err
+	}
+	res, err := c.logon(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	c.loggedOn = true
+	c.mu.Unlock()
+	return res, nil
+}
+
+func (c *WsAPIClient) logon(ctx context.Context, conn *wsAPIConn) (*WsAPISessionStatus, error) {
+	params, err := c.signParams(&request{secType: secTypeSigned}, false)
+	if err != nil {
+		return nil, err
+	}
+	data, err := conn.call(ctx, c.newID(), "session.logon", params)
+	if err != nil {
+		return nil, err
+	}
+	res := new(WsAPISessionStatus)
+	err = json.Unmarshal(data, res)
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// PlaceOrder place the order built by s with order.place
+func (c *WsAPIClient) PlaceOrder(ctx context.Context, s *CreateOrderService, opts ...RequestOption) (res *CreateOrderResponse, err error) {
+	res = new(CreateOrderResponse)
+	err = c.callSigned(ctx, "order.place", s.buildRequest("/api/v3/order"), opts, res)
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// CancelOrder cancel the order described by s with order.cancel
+func (c *WsAPIClient) CancelOrder(ctx context.Context, s *CancelOrderService, opts ...RequestOption) (res *CancelOrderResponse, err error) {
+	res = new(CancelOrderResponse)
+	err = c.callSigned(ctx, "order.cancel", s.buildRequest(), opts, res)
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// GetOrder query the order described by s with order.status
+func (c *WsAPIClient) GetOrder(ctx context.Context, s *GetOrderService, opts ...RequestOption) (res *Order, err error) {
+	res = new(Order)
+	err = c.callSigned(ctx, "order.status", s.buildRequest(), opts, res)
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// AccountStatus query the account information with account.status
+func (c *WsAPIClient) AccountStatus(ctx context.Context, opts ...RequestOption) (res *Account, err error) {
+	res = new(Account)
+	s := &GetAccountService{c: c.c}
+	err = c.callSigned(ctx, "account.status", s.buildRequest(), opts, res)
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func (c *WsAPIClient) callSigned(ctx context.Context, method string, r *request, opts []RequestOption, res interface{}) error {
+	for _, opt := range opts {
+		opt(r)
+	}
+	c.mu.Lock()
+	loggedOn := c.loggedOn
+	c.mu.Unlock()
+	params, err := c.signParams(r, loggedOn)
+	if err != nil {
+		return err
+	}
+	data, err := c.Call(ctx, method, params)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, res)
+}
+
+// signParams merge the query and form of r into websocket API params, adding
+// the timestamp and, unless the session is logged on, the apiKey and signature
+func (c *WsAPIClient) signParams(r *request, loggedOn bool) (map[string]interface{}, error) {
+	err := r.validate()
+	if err != nil {
+		return nil, err
+	}
+	values := make(map[string]string)
+	for _, v := range []map[string][]string{r.query, r.form} {
+		for key, value := range v {
+			if len(value) > 0 {
+				values[key] = value[0]
+			}
+		}
+	}
+	if r.recvWindow > 0 {
+		values[recvWindowKey] = strconv.FormatInt(r.recvWindow, 10)
+	}
+	if r.secType == secTypeSigned {
+		values[timestampKey] = strconv.FormatInt(currentTimestamp()-c.c.timeOffset(), 10)
+	}
+	if (r.secType == secTypeSigned || r.secType == secTypeAPIKey) && !loggedOn {
+		values["apiKey"] = c.c.APIKey
+	}
+	if r.secType == secTypeSigned && !loggedOn {
+		keys := make([]string, 0, len(values))
+		for key := range values {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		pairs := make([]string, len(keys))
+		for i, key := range keys {
+			pairs[i] = key + "=" + values[key]
+		}
+		signature, err := c.c.signer().Sign([]byte(strings.Join(pairs, "&")))
+		if err != nil {
+			return nil, err
+		}
+		values[signatureKey] = signature
+	}
+	params := make(map[string]interface{}, len(values))
+	for key, value := range values {
+		params[key] = value
+		if wsAPIIntParams[key] {
+			if n, err := strconv.ParseInt(value, 10, 64); err == nil {
+				params[key] = n
+			}
+		}
+	}
+	return params, nil
+}
+
+// Close close the connection, the client can not be used afterwards
+func (c *WsAPIClient) Close() {
+	c.mu.Lock()
+	conn := c.conn
+	c.conn = nil
+	c.closed = true
+	c.mu.Unlock()
+	if conn != nil {
+		conn.close()
+	}
+}
+
+// wsAPIConn is a websocket API connection with its pending requests
+type wsAPIConn struct {
+	conn    *websocket.Conn
+	writeMu sync.Mutex
+
+	mu      sync.Mutex
+	pending map[string]chan *wsAPIResponse
+	err     error
+	doneC   chan struct{}
+}
+
+func newWsAPIConn(ws *websocket.Conn) *wsAPIConn {
+	conn := &wsAPIConn{
+		conn:    ws,
+		pending: make(map[string]chan *wsAPIResponse),
+		doneC:   make(chan struct{}),
+	}
+	// The server drops the connections idle for too long
+	if WebsocketKeepalive {
+		keepAlive(ws, WebsocketTimeout)
+	}
+	go conn.serve()
+	return conn
+}
+
+// serve read responses and hand them to their pending request until the connection fails
+func (c *wsAPIConn) serve() {
+	var err error
+	for {
+		var message []byte
+		_, message, err = c.conn.ReadMessage()
+		if err != nil {
+			break
+		}
+		res := new(wsAPIResponse)
+		if json.Unmarshal(message, res) != nil {
+			continue
+		}
+		c.mu.Lock()
+		resC, ok := c.pending[res.ID]
+		delete(c.pending, res.ID)
+		c.mu.Unlock()
+		if ok {
+			resC <- res
+		}
+	}
+	c.mu.Lock()
+	c.err = err
+	c.mu.Unlock()
+	close(c.doneC)
+}
+
+func (c *wsAPIConn) isDone() bool {
+	select {
+	case <-c.doneC:
+		return true
+	default:
+		return false
+	}
+}
+
+func (c *wsAPIConn) call(ctx context.Context, id, method string, params map[string]interface{}) (stdjson.RawMessage, error) {
+	resC := make(chan *wsAPIResponse, 1)
+	c.mu.Lock()
+	c.pending[id] = resC
+	c.mu.Unlock()
+	defer func() {
+		c.mu.Lock()
+		delete(c.pending, id)
+		c.mu.Unlock()
+	}()
+
+	c.writeMu.Lock()
+	if deadline, ok := ctx.Deadline(); ok {
+		c.conn.SetWriteDeadline(deadline)
+	}
+	err := c.conn.WriteJSON(&wsAPIRequest{ID: id, Method: method, Params: params})
+	c.conn.SetWriteDeadline(time.Time{})
+	c.writeMu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	select {
+	case res := <-resC:
+		if res.Error != nil {
+			res.Error.StatusCode = res.Status
+			res.Error.Endpoint = method
+			return nil, res.Error
+		}
+		if res.Status >= 400 {
+			return nil, fmt.Errorf("websocket api %s failed with status %d", method, res.Status)
+		}
+		return res.Result, nil
+	case <-c.doneC:
+		c.mu.Lock()
+		err := c.err
+		c.mu.Unlock()
+		return nil, fmt.Errorf("websocket api connection closed: %w", err)
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (c *wsAPIConn) close() {
+	c.conn.Close()
+}
@@ -0,0 +1,259 @@
+package binance
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/suite"
+	"github.com/vv1zard/go-binance/v2/common"
+)
+
+type wsAPIClientTestSuite struct {
+	suite.Suite
+	server   *httptest.Server
+	mu       sync.Mutex
+	requests []*wsAPIRequest
+	conns    []*websocket.Conn
+	results  map[string]string
+	pings    int
+	client   *Client
+	ws       *WsAPIClient
+}
+
+func TestWsAPIClient(t *testing.T) {
+	suite.Run(t, new(wsAPIClientTestSuite))
+}
+
+// SetupTest starts a server answering each method with the result set in
+// s.results, or with an error for unknown methods. A "slow" method is never
+// answered.
+func (s *wsAPIClientTestSuite) SetupTest() {
+	s.requests = nil
+	s.conns = nil
+	s.pings = 0
+	s.results = map[string]string{}
+	upgrader := websocket.Upgrader{}
+	s.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer c.Close()
+		s.mu.Lock()
+		s.conns = append(s.conns, c)
+		s.mu.Unlock()
+		c.SetPingHandler(func(data string) error {
+			s.mu.Lock()
+			s.pings++
+			s.mu.Unlock()
+			return c.WriteControl(websocket.PongMessage, []byte(data), time.Now().Add(time.Second))
+		})
+		for {
+			req := new(wsAPIRequest)
+			err := c.ReadJSON(req)
+			if err != nil {
+				return
+			}
+			s.mu.Lock()
+			s.requests = append(s.requests, req)
+			result, ok := s.results[req.Method]
+			s.mu.Unlock()
+			switch {
+			case req.Method == "slow":
+			case ok:
+				c.WriteMessage(websocket.TextMessage, []byte(fmt.Sprintf(`{"id":%q,"status":200,"result":%s}`, req.ID, result)))
+			default:
+				c.WriteMessage(websocket.TextMessage, []byte(fmt.Sprintf(`{"id":%q,"status":400,"error":{"code":-1102,"msg":"Mandatory parameter was not sent."}}`, req.ID)))
+			}
+		}
+	}))
+	s.client = NewClient("dummyAPIKey", "dummySecretKey")
+	s.ws = s.client.NewWsAPIClient()
+	s.ws.Endpoint = "ws" + strings.TrimPrefix(s.server.URL, "http")
+}
+
+func (s *wsAPIClientTestSuite) TearDownTest() {
+	s.ws.Close()
+	s.server.Close()
+}
+
+func (s *wsAPIClientTestSuite) lastRequest() *wsAPIRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requests[len(s.requests)-1]
+}
+
+func (s *wsAPIClientTestSuite) TestPlaceOrder() {
+	s.results["order.place"] = `{
+		"symbol": "BTCUSDT",
+		"orderId": 12569099453,
+		"clientOrderId": "4d96324ff9d44481926157ec08158a40",
+		"transactTime": 1660801715639,
+		"price": "23416.10000000",
+		"origQty": "0.00847000",
+		"status": "NEW",
+		"timeInForce": "GTC",
+		"type": "LIMIT",
+		"side": "SELL"
+	}`
+	order := s.client.NewCreateOrderService().Symbol("BTCUSDT").Side(SideTypeSell).
+		Type(OrderTypeLimit).TimeInForce(TimeInForceTypeGTC).Quantity("0.00847000").Price("23416.10000000")
+	res, err := s.ws.PlaceOrder(context.Background(), order, WithRecvWindow(5000))
+	s.Require().NoError(err)
+	s.Equal(int64(12569099453), res.OrderID)
+	s.Equal(OrderStatusTypeNew, res.Status)
+
+	req := s.lastRequest()
+	s.Equal("order.place", req.Method)
+	s.Equal("dummyAPIKey", req.Params["apiKey"])
+	s.Equal("BTCUSDT", req.Params["symbol"])
+	s.Equal(float64(5000), req.Params["recvWindow"])
+	s.IsType(float64(0), req.Params["timestamp"])
+
+	// The signature covers every other parameter sorted by name
+	keys := []string{}
+	for key := range req.Params {
+		if key != signatureKey {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	pairs := []string{}
+	for _, key := range keys {
+		value := req.Params[key]
+		if n, ok := value.(float64); ok {
+			value = strconv.FormatFloat(n, 'f', -1, 64)
+		}
+		pairs = append(pairs, fmt.Sprintf("%s=%v", key, value))
+	}
+	signature, err := common.NewHMACSigner("dummySecretKey").Sign([]byte(strings.Join(pairs, "&")))
+	s.Require().NoError(err)
+	s.Equal(signature, req.Params[signatureKey])
+}
+
+func (s *wsAPIClientTestSuite) TestQueries() {
+	s.results["order.status"] = `{"symbol":"BTCUSDT","orderId":12569099453,"status":"FILLED"}`
+	s.results["order.cancel"] = `{"symbol":"BTCUSDT","origClientOrderId":"4d96324f","orderId":12569099453,"status":"CANCELED"}`
+	s.results["account.status"] = `{"makerCommission":15,"canTrade":true,"balances":[{"asset":"BTC","free":"1.0","locked":"0.0"}]}`
+	ctx := context.Background()
+
+	order, err := s.ws.GetOrder(ctx, s.client.NewGetOrderService().Symbol("BTCUSDT").OrderID(12569099453))
+	s.Require().NoError(err)
+	s.Equal(OrderStatusTypeFilled, order.Status)
+	s.Equal(float64(12569099453), s.lastRequest().Params["orderId"])
+
+	canceled, err := s.ws.CancelOrder(ctx, s.client.NewCancelOrderService().Symbol("BTCUSDT").OrigClientOrderID("4d96324f"))
+	s.Require().NoError(err)
+	s.Equal(OrderStatusTypeCanceled, canceled.Status)
+	s.Equal("4d96324f", s.lastRequest().Params["origClientOrderId"])
+
+	account, err := s.ws.AccountStatus(ctx)
+	s.Require().NoError(err)
+	s.True(account.CanTrade)
+	s.Equal("BTC", account.Balances[0].Asset)
+
+	s.mu.Lock()
+	s.Len(s.conns, 1)
+	s.mu.Unlock()
+}
+
+func (s *wsAPIClientTestSuite) TestError() {
+	_, err := s.ws.GetOrder(context.Background(), s.client.NewGetOrderService().Symbol("BTCUSDT"))
+	s.Require().Error(err)
+	s.True(common.IsAPIError(err))
+	s.Equal(common.CodeMandatoryParamEmpty, err.(*common.APIError).Code)
+}
+
+func (s *wsAPIClientTestSuite) TestTimeout() {
+	s.ws.Timeout = 50 * time.Millisecond
+	_, err := s.ws.Call(context.Background(), "slow", nil)
+	s.Equal(context.DeadlineExceeded, err)
+}
+
+func (s *wsAPIClientTestSuite) TestSessionLogon() {
+	pub, key, err := ed25519.GenerateKey(rand.Reader)
+	s.Require().NoError(err)
+	s.client.Signer = &common.Ed25519Signer{PrivateKey: key}
+	s.results["session.logon"] = `{"apiKey":"dummyAPIKey","authorizedSince":1649729878532,"connectedSince":1649729873021,"serverTime":1649729878630}`
+	s.results["account.status"] = `{"canTrade":true}`
+
+	status, err := s.ws.SessionLogon(context.Background())
+	s.Require().NoError(err)
+	s.Equal(int64(1649729878532), status.AuthorizedSince)
+	req := s.lastRequest()
+	s.Len(req.Params, 3)
+	signature, err := base64.StdEncoding.DecodeString(req.Params[signatureKey].(string))
+	s.Require().NoError(err)
+	payload := fmt.Sprintf("apiKey=dummyAPIKey&timestamp=%.0f", req.Params[timestampKey])
+	s.True(ed25519.Verify(pub, []byte(payload), signature))
+
+	_, err = s.ws.AccountStatus(context.Background())
+	s.Require().NoError(err)
+	req = s.lastRequest()
+	s.Nil(req.Params["apiKey"])
+	s.Nil(req.Params[signatureKey])
+	s.NotNil(req.Params[timestampKey])
+
+	// A new connection logs on again before the request
+	s.mu.Lock()
+	s.conns[0].Close()
+	s.mu.Unlock()
+	s.Eventually(func() bool {
+		return s.ws.conn.isDone()
+	}, time.Second, 10*time.Millisecond)
+	_, err = s.ws.AccountStatus(context.Background())
+	s.Require().NoError(err)
+	s.mu.Lock()
+	methods := []string{}
+	for _, req := range s.requests {
+		methods = append(methods, req.Method)
+	}
+	s.mu.Unlock()
+	s.Equal([]string{"session.logon", "account.status", "session.logon", "account.status"}, methods)
+}
+
+func (s *wsAPIClientTestSuite) TestConcurrentDial() {
+	s.results["ping"] = `{}`
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := s.ws.Call(context.Background(), "ping", nil)
+			s.NoError(err)
+		}()
+	}
+	wg.Wait()
+	// The requests arriving during the dial share its connection
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.Len(s.conns, 1)
+	s.Len(s.requests, 5)
+}
+
+func (s *wsAPIClientTestSuite) TestKeepalive() {
+	timeout := WebsocketTimeout
+	WebsocketTimeout = 20 * time.Millisecond
+	defer func() { WebsocketTimeout = timeout }()
+	s.results["ping"] = `{}`
+	_, err := s.ws.Call(context.Background(), "ping", nil)
+	s.Require().NoError(err)
+	// The idle connection is pinged
+	s.Eventually(func() bool {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		return s.pings > 2
+	}, time.Second, time.Millisecond)
+}
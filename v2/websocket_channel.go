@@ -0,0 +1,154 @@
+package binance
+
+import (
+	"context"
+
+	"github.com/vv1zard/go-binance/v2/common"
+)
+
+// WsPartialDepthStream is WsPartialDepthServe delivering events on a channel until ctx is done
+func WsPartialDepthStream(ctx context.Context, symbol string, levels string, opts ...common.StreamOption) (*common.Stream[*WsPartialDepthEvent], error) {
+	return common.NewStream[*WsPartialDepthEvent](ctx, func(handler func(*WsPartialDepthEvent), errHandler func(error)) (doneC, stopC chan struct{}, err error) {
+		return WsPartialDepthServe(symbol, levels, handler, errHandler)
+	}, opts...)
+}
+
+// WsPartialDepthStream100Ms is WsPartialDepthServe100Ms delivering events on a channel until ctx is done
+func WsPartialDepthStream100Ms(ctx context.Context, symbol string, levels string, opts ...common.StreamOption) (*common.Stream[*WsPartialDepthEvent], error) {
+	return common.NewStream[*WsPartialDepthEvent](ctx, func(handler func(*WsPartialDepthEvent), errHandler func(error)) (doneC, stopC chan struct{}, err error) {
+		return WsPartialDepthServe100Ms(symbol, levels, handler, errHandler)
+	}, opts...)
+}
+
+// WsCombinedPartialDepthStream is WsCombinedPartialDepthServe delivering events on a channel until ctx is done
+func WsCombinedPartialDepthStream(ctx context.Context, symbolLevels map[string]string, opts ...common.StreamOption) (*common.Stream[*WsPartialDepthEvent], error) {
+	return common.NewStream[*WsPartialDepthEvent](ctx, func(handler func(*WsPartialDepthEvent), errHandler func(error)) (doneC, stopC chan struct{}, err error) {
+		return WsCombinedPartialDepthServe(symbolLevels, handler, errHandler)
+	}, opts...)
+}
+
+// WsDepthStream is WsDepthServe delivering events on a channel until ctx is done
+func WsDepthStream(ctx context.Context, symbol string, opts ...common.StreamOption) (*common.Stream[*WsDepthEvent], error) {
+	return common.NewStream[*WsDepthEvent](ctx, func(handler func(*WsDepthEvent), errHandler func(error)) (doneC, stopC chan struct{}, err error) {
+		return WsDepthServe(symbol, handler, errHandler)
+	}, opts...)
+}
+
+// WsDepthStream100Ms is WsDepthServe100Ms delivering events on a channel until ctx is done
+func WsDepthStream100Ms(ctx context.Context, symbol string, opts ...common.StreamOption) (*common.Stream[*WsDepthEvent], error) {
+	return common.NewStream[*WsDepthEvent](ctx, func(handler func(*WsDepthEvent), errHandler func(error)) (doneC, stopC chan struct{}, err error) {
+		return WsDepthServe100Ms(symbol, handler, errHandler)
+	}, opts...)
+}
+
+// WsCombinedDepthStream is WsCombinedDepthServe delivering events on a channel until ctx is done
+func WsCombinedDepthStream(ctx context.Context, symbols []string, opts ...common.StreamOption) (*common.Stream[*WsDepthEvent], error) {
+	return common.NewStream[*WsDepthEvent](ctx, func(handler func(*WsDepthEvent), errHandler func(error)) (doneC, stopC chan struct{}, err error) {
+		return WsCombinedDepthServe(symbols, handler, errHandler)
+	}, opts...)
+}
+
+// WsCombinedDepthStream100Ms is WsCombinedDepthServe100Ms delivering events on a channel until ctx is done
+func WsCombinedDepthStream100Ms(ctx context.Context, symbols []string, opts ...common.StreamOption) (*common.Stream[*WsDepthEvent], error) {
+	return common.NewStream[*WsDepthEvent](ctx, func(handler func(*WsDepthEvent), errHandler func(error)) (doneC, stopC chan struct{}, err error) {
+		return WsCombinedDepthServe100Ms(symbols, handler, errHandler)
+	}, opts...)
+}
+
+// WsCombinedKlineStream is WsCombinedKlineServe delivering events on a channel until ctx is done
+func WsCombinedKlineStream(ctx context.Context, symbolIntervalPair map[string]string, opts ...common.StreamOption) (*common.Stream[*WsKlineEvent], error) {
+	return common.NewStream[*WsKlineEvent](ctx, func(handler func(*WsKlineEvent), errHandler func(error)) (doneC, stopC chan struct{}, err error) {
+		return WsCombinedKlineServe(symbolIntervalPair, handler, errHandler)
+	}, opts...)
+}
+
+// WsKlineStream is WsKlineServe delivering events on a channel until ctx is done
+func WsKlineStream(ctx context.Context, symbol string, interval string, opts ...common.StreamOption) (*common.Stream[*WsKlineEvent], error) {
+	return common.NewStream[*WsKlineEvent](ctx, func(handler func(*WsKlineEvent), errHandler func(error)) (doneC, stopC chan struct{}, err error) {
+		return WsKlineServe(symbol, interval, handler, errHandler)
+	}, opts...)
+}
+
+// WsAggTradeStream is WsAggTradeServe delivering events on a channel until ctx is done
+func WsAggTradeStream(ctx context.Context, symbol string, opts ...common.StreamOption) (*common.Stream[*WsAggTradeEvent], error) {
+	return common.NewStream[*WsAggTradeEvent](ctx, func(handler func(*WsAggTradeEvent), errHandler func(error)) (doneC, stopC chan struct{}, err error) {
+		return WsAggTradeServe(symbol, handler, errHandler)
+	}, opts...)
+}
+
+// WsCombinedAggTradeStream is WsCombinedAggTradeServe delivering events on a channel until ctx is done
+func WsCombinedAggTradeStream(ctx context.Context, symbols []string, opts ...common.StreamOption) (*common.Stream[*WsAggTradeEvent], error) {
+	return common.NewStream[*WsAggTradeEvent](ctx, func(handler func(*WsAggTradeEvent), errHandler func(error)) (doneC, stopC chan struct{}, err error) {
+		return WsCombinedAggTradeServe(symbols, handler, errHandler)
+	}, opts...)
+}
+
+// WsTradeStream is WsTradeServe delivering events on a channel until ctx is done
+func WsTradeStream(ctx context.Context, symbol string, opts ...common.StreamOption) (*common.Stream[*WsTradeEvent], error) {
+	return common.NewStream[*WsTradeEvent](ctx, func(handler func(*WsTradeEvent), errHandler func(error)) (doneC, stopC chan struct{}, err error) {
+		return WsTradeServe(symbol, handler, errHandler)
+	}, opts...)
+}
+
+// WsCombinedTradeStream is WsCombinedTradeServe delivering events on a channel until ctx is done
+func WsCombinedTradeStream(ctx context.Context, symbols []string, opts ...common.StreamOption) (*common.Stream[*WsCombinedTradeEvent], error) {
+	return common.NewStream[*WsCombinedTradeEvent](ctx, func(handler func(*WsCombinedTradeEvent), errHandler func(error)) (doneC, stopC chan struct{}, err error) {
+		return WsCombinedTradeServe(symbols, handler, errHandler)
+	}, opts...)
+}
+
+// WsUserDataStream is WsUserDataServe delivering events on a channel until ctx is done
+func WsUserDataStream(ctx context.Context, listenKey string, opts ...common.StreamOption) (*common.Stream[*WsUserDataEvent], error) {
+	return common.NewStream[*WsUserDataEvent](ctx, func(handler func(*WsUserDataEvent), errHandler func(error)) (doneC, stopC chan struct{}, err error) {
+		return WsUserDataServe(listenKey, handler, errHandler)
+	}, opts...)
+}
+
+// WsCombinedMarketStatStream is WsCombinedMarketStatServe delivering events on a channel until ctx is done
+func WsCombinedMarketStatStream(ctx context.Context, symbols []string, opts ...common.StreamOption) (*common.Stream[*WsMarketStatEvent], error) {
+	return common.NewStream[*WsMarketStatEvent](ctx, func(handler func(*WsMarketStatEvent), errHandler func(error)) (doneC, stopC chan struct{}, err error) {
+		return WsCombinedMarketStatServe(symbols, handler, errHandler)
+	}, opts...)
+}
+
+// WsMarketStatStream is WsMarketStatServe delivering events on a channel until ctx is done
+func WsMarketStatStream(ctx context.Context, symbol string, opts ...common.StreamOption) (*common.Stream[*WsMarketStatEvent], error) {
+	return common.NewStream[*WsMarketStatEvent](ctx, func(handler func(*WsMarketStatEvent), errHandler func(error)) (doneC, stopC chan struct{}, err error) {
+		return WsMarketStatServe(symbol, handler, errHandler)
+	}, opts...)
+}
+
+// WsAllMarketsStatStream is WsAllMarketsStatServe delivering events on a channel until ctx is done
+func WsAllMarketsStatStream(ctx context.Context, opts ...common.StreamOption) (*common.Stream[WsAllMarketsStatEvent], error) {
+	return common.NewStream[WsAllMarketsStatEvent](ctx, func(handler func(WsAllMarketsStatEvent), errHandler func(error)) (doneC, stopC chan struct{}, err error) {
+		return WsAllMarketsStatServe(handler, errHandler)
+	}, opts...)
+}
+
+// WsAllMiniMarketsStatStream is WsAllMiniMarketsStatServe delivering events on a channel until ctx is done
+func WsAllMiniMarketsStatStream(ctx context.Context, opts ...common.StreamOption) (*common.Stream[WsAllMiniMarketsStatEvent], error) {
+	return common.NewStream[WsAllMiniMarketsStatEvent](ctx, func(handler func(WsAllMiniMarketsStatEvent), errHandler func(error)) (doneC, stopC chan struct{}, err error) {
+		return WsAllMiniMarketsStatServe(handler, errHandler)
+	}, opts...)
+}
+
+// WsBookTickerStream is WsBookTickerServe delivering events on a channel until ctx is done
+func WsBookTickerStream(ctx context.Context, symbol string, opts ...common.StreamOption) (*common.Stream[*WsBookTickerEvent], error) {
+	return common.NewStream[*WsBookTickerEvent](ctx, func(handler func(*WsBookTickerEvent), errHandler func(error)) (doneC, stopC chan struct{}, err error) {
+		return WsBookTickerServe(symbol, handler, errHandler)
+	}, opts...)
+}
+
+// WsCombinedBookTickerStream is WsCombinedBookTickerServe delivering events on a channel until ctx is done
+func WsCombinedBookTickerStream(ctx context.Context, symbols []string, opts ...common.StreamOption) (*common.Stream[*WsBookTickerEvent], error) {
+	return common.NewStream[*WsBookTickerEvent](ctx, func(handler func(*WsBookTickerEvent), errHandler func(error)) (doneC, stopC chan struct{}, err error) {
+		return WsCombinedBookTickerServe(symbols, handler, errHandler)
+	}, opts...)
+}
+
+// WsAllBookTickerStream is WsAllBookTickerServe delivering events on a channel until ctx is done
+func WsAllBookTickerStream(ctx context.Context, opts ...common.StreamOption) (*common.Stream[*WsBookTickerEvent], error) {
+	return common.NewStream[*WsBookTickerEvent](ctx, func(handler func(*WsBookTickerEvent), errHandler func(error)) (doneC, stopC chan struct{}, err error) {
+		return WsAllBookTickerServe(handler, errHandler)
+	}, opts...)
+}
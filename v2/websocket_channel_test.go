@@ -0,0 +1,34 @@
+package binance
+
+import (
+	"context"
+	"errors"
+
+	"github.com/vv1zard/go-binance/v2/common"
+)
+
+func (s *websocketServiceTestSuite) TestKlineStream() {
+	data := []byte(`{
+		"e": "kline", "E": 1499404907056, "s": "ETHBTC",
+		"k": {"t": 1499404860000, "T": 1499404919999, "s": "ETHBTC", "i": "1m", "c": "0.10278645"}
+	}`)
+	s.mockWsServe(data, errors.New("fake error"))
+	defer s.assertWsServe()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var errs []error
+	stream, err := WsKlineStream(ctx, "ETHBTC", "1m", common.WithBuffer(1), common.WithErrHandler(func(err error) {
+		errs = append(errs, err)
+	}))
+	s.r().NoError(err)
+	event := <-stream.Events()
+	s.r().Equal("ETHBTC", event.Symbol)
+	s.r().Equal("0.10278645", event.Kline.Close)
+	s.r().EqualError(errs[0], "fake error")
+
+	cancel()
+	<-stream.Done()
+	_, ok := <-stream.Events()
+	s.r().False(ok)
+	s.r().Equal(context.Canceled, stream.Err())
+}
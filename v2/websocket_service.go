@@ -2,14 +2,23 @@ package binance
 
 import (
 	"fmt"
+	"log/slog"
 	"strings"
 	"time"
 
 	stdjson "encoding/json"
 
+	"github.com/bitly/go-simplejson"
 	easyjson "github.com/mailru/easyjson"
+	"github.com/vv1zard/go-binance/v2/common"
 )
 
+// The decoders of the events marked easyjson:json are generated, without the
+// encoding/json import which would clash with the json of the package
+//go:generate go run github.com/mailru/easyjson/easyjson websocket_service.go
+//go:generate sed -i.bak -e "\\|json \"encoding/json\"|d" -e "\\|_ \\*json.RawMessage|d" websocket_service_easyjson.go
+//go:generate rm websocket_service_easyjson.go.bak
+
 // Endpoints
 const (
 	baseWsMainURL          = "wss://stream.binance.com:9443/ws"
@@ -23,6 +32,13 @@ var (
 	WebsocketTimeout = time.Second * 60
 	// WebsocketKeepalive enables sending ping/pong messages to check the connection stability
 	WebsocketKeepalive = true
+	// WebsocketReconnect makes every stream reconnect automatically when set, nil disables it
+	WebsocketReconnect *WsReconnectConfig
+	// WebsocketLogHandler receives the connection events of every stream, nil disables them
+	WebsocketLogHandler slog.Handler
+	// WebsocketMetrics receives the message count, event lag and reconnections of
+	// every stream, nil disables them
+	WebsocketMetrics common.Metrics
 )
 
 // getWsEndpoint return the base endpoint of the WS according the UseTestnet flag
@@ -42,6 +58,8 @@ func getCombinedEndpoint() string {
 }
 
 // WsPartialDepthEvent define websocket partial depth book event
+//
+//easyjson:json
 type WsPartialDepthEvent struct {
 	Symbol       string
 	LastUpdateID int64 `json:"lastUpdateId"`
@@ -167,36 +185,14 @@ func wsDepthServe(endpoint string, handler WsDepthHandler, errHandler ErrHandler
 			errHandler(err)
 			return
 		}
-		event := new(WsDepthEvent)
-		event.Event = j.Get("e").MustString()
-		event.Time = j.Get("E").MustInt64()
-		event.Symbol = j.Get("s").MustString()
-		event.LastUpdateID = j.Get("u").MustInt64()
-		event.FirstUpdateID = j.Get("U").MustInt64()
-		bidsLen := len(j.Get("b").MustArray())
-		event.Bids = make([]Bid, bidsLen)
-		for i := 0; i < bidsLen; i++ {
-			item := j.Get("b").GetIndex(i)
-			event.Bids[i] = Bid{
-				Price:    item.GetIndex(0).MustString(),
-				Quantity: item.GetIndex(1).MustString(),
-			}
-		}
-		asksLen := len(j.Get("a").MustArray())
-		event.Asks = make([]Ask, asksLen)
-		for i := 0; i < asksLen; i++ {
-			item := j.Get("a").GetIndex(i)
-			event.Asks[i] = Ask{
-				Price:    item.GetIndex(0).MustString(),
-				Quantity: item.GetIndex(1).MustString(),
-			}
-		}
-		handler(event)
+		handler(parseWsDepthEvent(j))
 	}
 	return wsServe(cfg, wsHandler, errHandler)
 }
 
 // WsDepthEvent define websocket depth event
+//
+//easyjson:json
 type WsDepthEvent struct {
 	Event         string `json:"e"`
 	Time          int64  `json:"E"`
@@ -207,6 +203,35 @@ type WsDepthEvent struct {
 	Asks          []Ask  `json:"a"`
 }
 
+// parseWsDepthEvent build a WsDepthEvent from the payload of a depth stream
+func parseWsDepthEvent(j *simplejson.Json) *WsDepthEvent {
+	event := new(WsDepthEvent)
+	event.Event = j.Get("e").MustString()
+	event.Time = j.Get("E").MustInt64()
+	event.Symbol = j.Get("s").MustString()
+	event.LastUpdateID = j.Get("u").MustInt64()
+	event.FirstUpdateID = j.Get("U").MustInt64()
+	bidsLen := len(j.Get("b").MustArray())
+	event.Bids = make([]Bid, bidsLen)
+	for i := 0; i < bidsLen; i++ {
+		item := j.Get("b").GetIndex(i)
+		event.Bids[i] = Bid{
+			Price:    item.GetIndex(0).MustString(),
+			Quantity: item.GetIndex(1).MustString(),
+		}
+	}
+	asksLen := len(j.Get("a").MustArray())
+	event.Asks = make([]Ask, asksLen)
+	for i := 0; i < asksLen; i++ {
+		item := j.Get("a").GetIndex(i)
+		event.Asks[i] = Ask{
+			Price:    item.GetIndex(0).MustString(),
+			Quantity: item.GetIndex(1).MustString(),
+		}
+	}
+	return event
+}
+
 // WsCombinedDepthServe is similar to WsDepthServe, but it for multiple symbols
 func WsCombinedDepthServe(symbols []string, handler WsDepthHandler, errHandler ErrHandler) (doneC, stopC chan struct{}, err error) {
 	endpoint := getCombinedEndpoint()
@@ -323,6 +348,8 @@ func WsKlineServe(symbol string, interval string, handler WsKlineHandler, errHan
 }
 
 // WsKlineEvent define websocket kline event
+//
+//easyjson:json
 type WsKlineEvent struct {
 	Event  string  `json:"e"`
 	Time   int64   `json:"E"`
@@ -331,6 +358,8 @@ type WsKlineEvent struct {
 }
 
 // WsKline define websocket kline
+//
+//easyjson:json
 type WsKline struct {
 	StartTime            int64  `json:"t"`
 	EndTime              int64  `json:"T"`
@@ -350,6 +379,36 @@ type WsKline struct {
 	ActiveBuyQuoteVolume string `json:"Q"`
 }
 
+// OpenDecimal parse Open as an exact decimal
+func (w *WsKline) OpenDecimal() (common.Decimal, error) {
+	return common.ParseDecimal(w.Open)
+}
+
+// HighDecimal parse High as an exact decimal
+func (w *WsKline) HighDecimal() (common.Decimal, error) {
+	return common.ParseDecimal(w.High)
+}
+
+// LowDecimal parse Low as an exact decimal
+func (w *WsKline) LowDecimal() (common.Decimal, error) {
+	return common.ParseDecimal(w.Low)
+}
+
+// CloseDecimal parse Close as an exact decimal
+func (w *WsKline) CloseDecimal() (common.Decimal, error) {
+	return common.ParseDecimal(w.Close)
+}
+
+// VolumeDecimal parse Volume as an exact decimal
+func (w *WsKline) VolumeDecimal() (common.Decimal, error) {
+	return common.ParseDecimal(w.Volume)
+}
+
+// QuoteVolumeDecimal parse QuoteVolume as an exact decimal
+func (w *WsKline) QuoteVolumeDecimal() (common.Decimal, error) {
+	return common.ParseDecimal(w.QuoteVolume)
+}
+
 // WsAggTradeHandler handle websocket aggregate trade event
 type WsAggTradeHandler func(event *WsAggTradeEvent)
 
@@ -408,6 +467,8 @@ func WsCombinedAggTradeServe(symbols []string, handler WsAggTradeHandler, errHan
 }
 
 // WsAggTradeEvent define websocket aggregate trade event
+//
+//easyjson:json
 type WsAggTradeEvent struct {
 	Event                 string `json:"e"`
 	Time                  int64  `json:"E"`
@@ -466,6 +527,7 @@ func WsCombinedTradeServe(symbols []string, handler WsCombinedTradeHandler, errH
 
 // WsTradeEvent define websocket trade event
 
+//easyjson:json
 type WsTradeEvent struct {
 	Event         string `json:"e"`
 	Time          int64  `json:"E"`
@@ -480,14 +542,15 @@ type WsTradeEvent struct {
 	Placeholder   bool   `json:"M"` // add this field to avoid case insensitive unmarshaling
 }
 
+//easyjson:json
 type WsCombinedTradeEvent struct {
 	Stream string       `json:"stream"`
 	Data   WsTradeEvent `json:"data"`
 }
 
 // WsUserDataEvent define user data event
-
-//model:json
+//
+//easyjson:json
 type WsUserDataEvent struct {
 	Event             UserDataEventType `json:"e"`
 	Time              int64             `json:"E"`
@@ -500,17 +563,21 @@ type WsUserDataEvent struct {
 }
 
 // WsAccountUpdate define account update
+//
+//easyjson:json
 type WsAccountUpdate struct {
 	Asset  string `json:"a"`
 	Free   string `json:"f"`
 	Locked string `json:"l"`
 }
 
+//easyjson:json
 type WsBalanceUpdate struct {
 	Asset  string `json:"a"`
 	Change string `json:"d"`
 }
 
+//easyjson:json
 type WsOrderUpdate struct {
 	Symbol            string          `json:"s"`
 	ClientOrderId     string          `json:"c"`
@@ -540,8 +607,58 @@ type WsOrderUpdate struct {
 	FilledQuoteVolume string          `json:"Z"` // the quote volume that already filled
 	LatestQuoteVolume string          `json:"Y"` // the quote volume for the latest trade
 	QuoteVolume       string          `json:"Q"`
+
+	SelfTradePreventionMode SelfTradePreventionModeType `json:"V"`
+	PreventedMatchId        int64                       `json:"v"` // only present when the order expired due to STP
+	PreventedQuantity       string                      `json:"A"`
+	LastPreventedQuantity   string                      `json:"B"`
+	TradeGroupId            int64                       `json:"u"`
+	CounterOrderId          int64                       `json:"U"`
+	StrategyId              int64                       `json:"j"`
+	StrategyType            int64                       `json:"J"`
+}
+
+// VolumeDecimal parse Volume as an exact decimal
+func (w *WsOrderUpdate) VolumeDecimal() (common.Decimal, error) {
+	return common.ParseDecimal(w.Volume)
+}
+
+// PriceDecimal parse Price as an exact decimal
+func (w *WsOrderUpdate) PriceDecimal() (common.Decimal, error) {
+	return common.ParseDecimal(w.Price)
+}
+
+// StopPriceDecimal parse StopPrice as an exact decimal
+func (w *WsOrderUpdate) StopPriceDecimal() (common.Decimal, error) {
+	return common.ParseDecimal(w.StopPrice)
+}
+
+// LatestVolumeDecimal parse LatestVolume as an exact decimal
+func (w *WsOrderUpdate) LatestVolumeDecimal() (common.Decimal, error) {
+	return common.ParseDecimal(w.LatestVolume)
 }
 
+// FilledVolumeDecimal parse FilledVolume as an exact decimal
+func (w *WsOrderUpdate) FilledVolumeDecimal() (common.Decimal, error) {
+	return common.ParseDecimal(w.FilledVolume)
+}
+
+// LatestPriceDecimal parse LatestPrice as an exact decimal
+func (w *WsOrderUpdate) LatestPriceDecimal() (common.Decimal, error) {
+	return common.ParseDecimal(w.LatestPrice)
+}
+
+// FeeCostDecimal parse FeeCost as an exact decimal
+func (w *WsOrderUpdate) FeeCostDecimal() (common.Decimal, error) {
+	return common.ParseDecimal(w.FeeCost)
+}
+
+// FilledQuoteVolumeDecimal parse FilledQuoteVolume as an exact decimal
+func (w *WsOrderUpdate) FilledQuoteVolumeDecimal() (common.Decimal, error) {
+	return common.ParseDecimal(w.FilledQuoteVolume)
+}
+
+//easyjson:json
 type WsOCOUpdate struct {
 	Symbol          string       `json:"s"`
 	OrderListId     int64        `json:"g"`
@@ -553,19 +670,49 @@ type WsOCOUpdate struct {
 	Orders          []WsOCOOrder `json:"O"`
 }
 
+//easyjson:json
 type WsOCOOrder struct {
 	Symbol        string `json:"s"`
 	OrderId       int64  `json:"i"`
 	ClientOrderId string `json:"c"`
 }
 
+// OrderList return the order list of a listStatus event, nil for the other
+// events
+func (e *WsUserDataEvent) OrderList() *OrderList {
+	if e.Event != UserDataEventTypeListStatus {
+		return nil
+	}
+	u := &e.OCOUpdate
+	list := &OrderList{
+		OrderListID:       u.OrderListId,
+		ContingencyType:   ContingencyType(u.ContingencyType),
+		ListStatusType:    ListStatusType(u.ListStatusType),
+		ListOrderStatus:   ListOrderStatusType(u.ListOrderStatus),
+		ListClientOrderID: u.ClientOrderId,
+		TransactionTime:   e.TransactionTime,
+		Symbol:            u.Symbol,
+		Orders:            make([]*OCOOrder, len(u.Orders)),
+	}
+	for i, o := range u.Orders {
+		list.Orders[i] = &OCOOrder{Symbol: o.Symbol, OrderID: o.OrderId, ClientOrderID: o.ClientOrderId}
+	}
+	return list
+}
+
 // WsUserDataHandler handle WsUserDataEvent
 type WsUserDataHandler func(event *WsUserDataEvent)
 
 // WsUserDataServe serve user data handler with listen key
 func WsUserDataServe(listenKey string, handler WsUserDataHandler, errHandler ErrHandler) (doneC, stopC chan struct{}, err error) {
-	endpoint := fmt.Sprintf("%s/%s", getWsEndpoint(), listenKey)
-	cfg := newWsConfig(endpoint)
+	return wsUserDataServe(newWsConfig(getUserDataEndpoint(listenKey)), handler, errHandler)
+}
+
+func getUserDataEndpoint(listenKey string) string {
+	return fmt.Sprintf("%s/%s", getWsEndpoint(), listenKey)
+}
+
+func wsUserDataServe(cfg *WsConfig, handler WsUserDataHandler, errHandler ErrHandler) (doneC, stopC chan struct{}, err error) {
 	wsHandler := func(message []byte) {
 		j, err := newJSON(message)
 		if err != nil {
@@ -574,15 +721,23 @@ func WsUserDataServe(listenKey string, handler WsUserDataHandler, errHandler Err
 		}
 
 		event := new(WsUserDataEvent)
-
-		// err = json.Unmarshal(message, event)
-		err = easyjson.Unmarshal(message, event)
-		if err != nil {
-			errHandler(err)
-			return
+		eventType := UserDataEventType(j.Get("e").MustString())
+
+		if eventType == UserDataEventTypeExecutionReport {
+			// "B" is the last prevented quantity of an execution report, not
+			// the balances of an account update
+			event.Event = eventType
+			event.Time = j.Get("E").MustInt64()
+		} else {
+			// err = json.Unmarshal(message, event)
+			err = easyjson.Unmarshal(message, event)
+			if err != nil {
+				errHandler(err)
+				return
+			}
 		}
 
-		switch UserDataEventType(j.Get("e").MustString()) {
+		switch eventType {
 		case UserDataEventTypeOutboundAccountPosition:
 
 		case UserDataEventTypeBalanceUpdate:
@@ -698,6 +853,8 @@ func WsAllMarketsStatServe(handler WsAllMarketsStatHandler, errHandler ErrHandle
 type WsAllMarketsStatEvent []*WsMarketStatEvent
 
 // WsMarketStatEvent define websocket market statistics event
+//
+//easyjson:json
 type WsMarketStatEvent struct {
 	Event              string `json:"e"`
 	Time               int64  `json:"E"`
@@ -748,6 +905,8 @@ func WsAllMiniMarketsStatServe(handler WsAllMiniMarketsStatServeHandler, errHand
 type WsAllMiniMarketsStatEvent []*WsMiniMarketsStatEvent
 
 // WsMiniMarketsStatEvent define websocket market mini-ticker statistics event
+//
+//easyjson:json
 type WsMiniMarketsStatEvent struct {
 	Event       string `json:"e"`
 	Time        int64  `json:"E"`
@@ -761,6 +920,8 @@ type WsMiniMarketsStatEvent struct {
 }
 
 // WsBookTickerEvent define websocket best book ticker event.
+//
+//easyjson:json
 type WsBookTickerEvent struct {
 	UpdateID     int64  `json:"u"`
 	Symbol       string `json:"s"`
@@ -770,6 +931,7 @@ type WsBookTickerEvent struct {
 	BestAskQty   string `json:"A"`
 }
 
+//easyjson:json
 type WsCombinedBookTickerEvent struct {
 	Data   *WsBookTickerEvent `json:"data"`
 	Stream string             `json:"stream"`
@@ -574,6 +574,22 @@ func easyjsonEd041cfDecodeGithubComVv1zardGoBinanceV23(in *jlexer.Lexer, out *Ws
 			out.LatestQuoteVolume = string(in.String())
 		case "Q":
 			out.QuoteVolume = string(in.String())
+		case "V":
+			out.SelfTradePreventionMode = SelfTradePreventionModeType(in.String())
+		case "v":
+			out.PreventedMatchId = int64(in.Int64())
+		case "A":
+			out.PreventedQuantity = string(in.String())
+		case "B":
+			out.LastPreventedQuantity = string(in.String())
+		case "u":
+			out.TradeGroupId = int64(in.Int64())
+		case "U":
+			out.CounterOrderId = int64(in.Int64())
+		case "j":
+			out.StrategyId = int64(in.Int64())
+		case "J":
+			out.StrategyType = int64(in.Int64())
 		default:
 			in.SkipRecursive()
 		}
@@ -728,6 +744,46 @@ func easyjsonEd041cfEncodeGithubComVv1zardGoBinanceV23(out *jwriter.Writer, in W
 		out.RawString(prefix)
 		out.String(string(in.QuoteVolume))
 	}
+	{
+		const prefix string = ",\"V\":"
+		out.RawString(prefix)
+		out.String(string(in.SelfTradePreventionMode))
+	}
+	{
+		const prefix string = ",\"v\":"
+		out.RawString(prefix)
+		out.Int64(int64(in.PreventedMatchId))
+	}
+	{
+		const prefix string = ",\"A\":"
+		out.RawString(prefix)
+		out.String(string(in.PreventedQuantity))
+	}
+	{
+		const prefix string = ",\"B\":"
+		out.RawString(prefix)
+		out.String(string(in.LastPreventedQuantity))
+	}
+	{
+		const prefix string = ",\"u\":"
+		out.RawString(prefix)
+		out.Int64(int64(in.TradeGroupId))
+	}
+	{
+		const prefix string = ",\"U\":"
+		out.RawString(prefix)
+		out.Int64(int64(in.CounterOrderId))
+	}
+	{
+		const prefix string = ",\"j\":"
+		out.RawString(prefix)
+		out.Int64(int64(in.StrategyId))
+	}
+	{
+		const prefix string = ",\"J\":"
+		out.RawString(prefix)
+		out.Int64(int64(in.StrategyType))
+	}
 	out.RawByte('}')
 }
 
@@ -774,6 +774,14 @@ func (s *websocketServiceTestSuite) assertOrderUpdate(e, a *WsOrderUpdate) {
 	r.Equal(e.LatestVolume, a.LatestVolume, "OrigCustomOrderId")
 	r.Equal(e.OrigCustomOrderId, a.OrigCustomOrderId, "OrigCustomOrderId")
 	r.Equal(e.RejectReason, a.RejectReason, "RejectReason")
+	r.Equal(e.SelfTradePreventionMode, a.SelfTradePreventionMode, "SelfTradePreventionMode")
+	r.Equal(e.PreventedMatchId, a.PreventedMatchId, "PreventedMatchId")
+	r.Equal(e.PreventedQuantity, a.PreventedQuantity, "PreventedQuantity")
+	r.Equal(e.LastPreventedQuantity, a.LastPreventedQuantity, "LastPreventedQuantity")
+	r.Equal(e.TradeGroupId, a.TradeGroupId, "TradeGroupId")
+	r.Equal(e.CounterOrderId, a.CounterOrderId, "CounterOrderId")
+	r.Equal(e.StrategyId, a.StrategyId, "StrategyId")
+	r.Equal(e.StrategyType, a.StrategyType, "StrategyType")
 }
 
 func (s *websocketServiceTestSuite) assertBalanceUpdate(e, a *WsBalanceUpdate) {
@@ -795,6 +803,7 @@ func (s *websocketServiceTestSuite) assertUserDataEvent(e, a *WsUserDataEvent) {
 	}
 	s.assertOrderUpdate(&e.OrderUpdate, &a.OrderUpdate)
 	s.assertBalanceUpdate(&e.BalanceUpdate, &a.BalanceUpdate)
+	r.Equal(e.OCOUpdate, a.OCOUpdate, "OCOUpdate")
 }
 
 func (s *websocketServiceTestSuite) testWsUserDataServe(data []byte, expectedEvent *WsUserDataEvent) {
@@ -914,6 +923,138 @@ func (s *websocketServiceTestSuite) TestWsUserDataServeOrderUpdate() {
 	s.testWsUserDataServe(data, expectedEvent)
 }
 
+func (s *websocketServiceTestSuite) TestWsUserDataServeOrderUpdatePreventedMatch() {
+	data := []byte(`{
+	   "e":"executionReport",
+	   "E":1669101687095,
+	   "s":"BTCUSDT",
+	   "c":"web_5e4ba3b5",
+	   "S":"SELL",
+	   "o":"LIMIT",
+	   "f":"GTC",
+	   "q":"1.30000000",
+	   "p":"1.10000000",
+	   "P":"0.00000000",
+	   "F":"0.00000000",
+	   "g":-1,
+	   "C":"",
+	   "x":"TRADE_PREVENTION",
+	   "X":"EXPIRED",
+	   "r":"NONE",
+	   "i":5,
+	   "l":"0.00000000",
+	   "z":"0.00000000",
+	   "L":"0.00000000",
+	   "n":"0",
+	   "N":null,
+	   "T":1669101687094,
+	   "t":-1,
+	   "v":1,
+	   "I":29,
+	   "w":false,
+	   "m":false,
+	   "M":false,
+	   "O":1669101687094,
+	   "Z":"0.00000000",
+	   "Y":"0.00000000",
+	   "Q":"0.00000000",
+	   "j":37,
+	   "J":1000000,
+	   "V":"EXPIRE_MAKER",
+	   "A":"1.30000000",
+	   "B":"1.30000000",
+	   "u":1,
+	   "U":3
+	}`)
+	expectedEvent := &WsUserDataEvent{
+		Event:           "executionReport",
+		Time:            1669101687095,
+		TransactionTime: 1669101687094,
+		OrderUpdate: WsOrderUpdate{
+			Symbol:                  "BTCUSDT",
+			ClientOrderId:           "web_5e4ba3b5",
+			Side:                    "SELL",
+			Type:                    "LIMIT",
+			TimeInForce:             "GTC",
+			Volume:                  "1.30000000",
+			Price:                   "1.10000000",
+			StopPrice:               "0.00000000",
+			IceBergVolume:           "0.00000000",
+			OrderListId:             -1,
+			ExecutionType:           "TRADE_PREVENTION",
+			Status:                  "EXPIRED",
+			RejectReason:            "NONE",
+			Id:                      5,
+			LatestVolume:            "0.00000000",
+			FilledVolume:            "0.00000000",
+			LatestPrice:             "0.00000000",
+			FeeCost:                 "0",
+			TransactionTime:         1669101687094,
+			TradeId:                 -1,
+			CreateTime:              1669101687094,
+			FilledQuoteVolume:       "0.00000000",
+			LatestQuoteVolume:       "0.00000000",
+			QuoteVolume:             "0.00000000",
+			SelfTradePreventionMode: SelfTradePreventionModeExpireMaker,
+			PreventedMatchId:        1,
+			PreventedQuantity:       "1.30000000",
+			LastPreventedQuantity:   "1.30000000",
+			TradeGroupId:            1,
+			CounterOrderId:          3,
+			StrategyId:              37,
+			StrategyType:            1000000,
+		},
+	}
+	s.testWsUserDataServe(data, expectedEvent)
+}
+
+func (s *websocketServiceTestSuite) TestWsUserDataServeListStatus() {
+	data := []byte(`{
+		"e": "listStatus",
+		"E": 1564035303637,
+		"s": "ETHBTC",
+		"g": 2,
+		"c": "OCO",
+		"l": "EXEC_STARTED",
+		"L": "EXECUTING",
+		"r": "NONE",
+		"C": "F4QN4G8DlFATFlIUQ0cjdD",
+		"T": 1564035303625,
+		"O": [
+			{"s": "ETHBTC", "i": 17, "c": "AJYsMjErWJesZvqlJCTUgL"},
+			{"s": "ETHBTC", "i": 18, "c": "bfYPSQdLoqAJeNrOr9adzq"}
+		]
+	}`)
+	expectedEvent := &WsUserDataEvent{
+		Event:           UserDataEventTypeListStatus,
+		Time:            1564035303637,
+		TransactionTime: 1564035303625,
+		OCOUpdate: WsOCOUpdate{
+			Symbol:          "ETHBTC",
+			OrderListId:     2,
+			ContingencyType: "OCO",
+			ListStatusType:  "EXEC_STARTED",
+			ListOrderStatus: "EXECUTING",
+			RejectReason:    "NONE",
+			ClientOrderId:   "F4QN4G8DlFATFlIUQ0cjdD",
+			Orders: []WsOCOOrder{
+				{Symbol: "ETHBTC", OrderId: 17, ClientOrderId: "AJYsMjErWJesZvqlJCTUgL"},
+				{Symbol: "ETHBTC", OrderId: 18, ClientOrderId: "bfYPSQdLoqAJeNrOr9adzq"},
+			},
+		},
+	}
+	s.testWsUserDataServe(data, expectedEvent)
+
+	list := expectedEvent.OrderList()
+	r := s.r()
+	r.Equal(ContingencyTypeOCO, list.ContingencyType)
+	r.Equal(ListStatusTypeExecStarted, list.ListStatusType)
+	r.Equal(ListOrderStatusTypeExecuting, list.ListOrderStatus)
+	r.Equal(int64(1564035303625), list.TransactionTime)
+	r.Equal(int64(18), list.Orders[1].OrderID)
+	r.Nil((&WsUserDataEvent{Event: UserDataEventTypeExecutionReport}).OrderList())
+}
+
 func (s *websocketServiceTestSuite) TestWsMarketStatServe() {
 	data := []byte(`{
   		"e": "24hrTicker",
@@ -0,0 +1,148 @@
+package binance
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/suite"
+)
+
+type wsStreamClientTestSuite struct {
+	suite.Suite
+	server *httptest.Server
+	mu     sync.Mutex
+	conns  []*websocket.Conn
+	client *WsStreamClient
+	errs   chan error
+}
+
+func TestWsStreamClient(t *testing.T) {
+	suite.Run(t, new(wsStreamClientTestSuite))
+}
+
+// SetupTest starts a server that pushes one event for every stream
+// subscribed, the sharding and pacing are tested with common.StreamClient.
+func (s *wsStreamClientTestSuite) SetupTest() {
+	s.conns = nil
+	upgrader := websocket.Upgrader{}
+	s.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer c.Close()
+		s.mu.Lock()
+		s.conns = append(s.conns, c)
+		s.mu.Unlock()
+		for {
+			var req struct {
+				Method string   `json:"method"`
+				Params []string `json:"params"`
+				ID     int64    `json:"id"`
+			}
+			err := c.ReadJSON(&req)
+			if err != nil {
+				return
+			}
+			c.WriteMessage(websocket.TextMessage, []byte(fmt.Sprintf(`{"result":null,"id":%d}`, req.ID)))
+			if req.Method == "SUBSCRIBE" {
+				for _, stream := range req.Params {
+					c.WriteMessage(websocket.TextMessage, []byte(streamEvent(stream)))
+				}
+			}
+		}
+	}))
+	s.errs = make(chan error, 10)
+	s.client = NewWsStreamClient(func(err error) {
+		s.errs <- err
+	})
+	s.client.Endpoint = "ws" + strings.TrimPrefix(s.server.URL, "http") + "/stream"
+}
+
+func (s *wsStreamClientTestSuite) TearDownTest() {
+	s.client.Close()
+	s.server.Close()
+}
+
+func streamEvent(stream string) string {
+	symbol := strings.ToUpper(strings.Split(stream, "@")[0])
+	switch {
+	case strings.Contains(stream, "@kline_"):
+		return fmt.Sprintf(`{"stream":%q,"data":{"e":"kline","E":1,"s":%q,"k":{"t":1,"i":"1m","o":"1.0","x":true}}}`, stream, symbol)
+	case strings.HasSuffix(stream, "@depth"):
+		return fmt.Sprintf(`{"stream":%q,"data":{"e":"depthUpdate","E":1,"s":%q,"U":157,"u":160,"b":[["0.0024","10"]],"a":[["0.0026","100"]]}}`, stream, symbol)
+	case strings.HasSuffix(stream, "@bookTicker"):
+		return fmt.Sprintf(`{"stream":%q,"data":{"u":400900217,"s":%q,"b":"25.35","B":"31.21","a":"25.36","A":"40.66"}}`, stream, symbol)
+	}
+	return fmt.Sprintf(`{"stream":%q,"data":{}}`, stream)
+}
+
+func (s *wsStreamClientTestSuite) TestSubscribeTypedStreams() {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	depthC := make(chan *WsDepthEvent, 1)
+	klineC := make(chan *WsKlineEvent, 1)
+	bookTickerC := make(chan *WsBookTickerEvent, 1)
+
+	err := s.client.SubscribeDepth(ctx, []string{"BTCUSDT"}, func(event *WsDepthEvent) {
+		depthC <- event
+	})
+	s.Require().NoError(err)
+	err = s.client.SubscribeKline(ctx, map[string]string{"ETHUSDT": "1m"}, func(event *WsKlineEvent) {
+		klineC <- event
+	})
+	s.Require().NoError(err)
+	err = s.client.SubscribeBookTicker(ctx, []string{"BNBUSDT"}, func(event *WsBookTickerEvent) {
+		bookTickerC <- event
+	})
+	s.Require().NoError(err)
+
+	depth := <-depthC
+	s.Equal("BTCUSDT", depth.Symbol)
+	s.Equal(int64(160), depth.LastUpdateID)
+	s.Equal(int64(157), depth.FirstUpdateID)
+	s.Equal([]Bid{{Price: "0.0024", Quantity: "10"}}, depth.Bids)
+	s.Equal([]Ask{{Price: "0.0026", Quantity: "100"}}, depth.Asks)
+	kline := <-klineC
+	s.Equal("ETHUSDT", kline.Symbol)
+	s.True(kline.Kline.IsFinal)
+	bookTicker := <-bookTickerC
+	s.Equal("BNBUSDT", bookTicker.Symbol)
+	s.Equal("25.35", bookTicker.BestBidPrice)
+
+	s.mu.Lock()
+	s.Len(s.conns, 1)
+	s.mu.Unlock()
+}
+
+func (s *wsStreamClientTestSuite) TestResubscribeAfterReconnect() {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	s.client.Reconnect.MinBackoff = time.Millisecond
+	s.client.Reconnect.MaxBackoff = time.Millisecond
+	events := make(chan []byte, 10)
+	err := s.client.Subscribe(ctx, []string{"btcusdt@aggTrade"}, func(data []byte) {
+		events <- data
+	})
+	s.Require().NoError(err)
+	<-events
+
+	s.mu.Lock()
+	s.conns[0].Close()
+	s.mu.Unlock()
+	select {
+	case <-events:
+	case <-time.After(5 * time.Second):
+		s.FailNow("timed out waiting for resubscription")
+	}
+	s.mu.Lock()
+	s.Len(s.conns, 2)
+	s.mu.Unlock()
+}
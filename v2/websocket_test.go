@@ -0,0 +1,192 @@
+package binance
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/suite"
+)
+
+type websocketTestSuite struct {
+	suite.Suite
+	server *httptest.Server
+}
+
+func TestWebsocket(t *testing.T) {
+	suite.Run(t, new(websocketTestSuite))
+}
+
+// SetupTest starts a server that sends one message per connection and then
+// drops the connection.
+func (s *websocketTestSuite) SetupTest() {
+	upgrader := websocket.Upgrader{}
+	s.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer c.Close()
+		c.WriteMessage(websocket.TextMessage, []byte(r.URL.Path))
+	}))
+}
+
+func (s *websocketTestSuite) TearDownTest() {
+	s.server.Close()
+}
+
+func (s *websocketTestSuite) endpoint() string {
+	return "ws" + strings.TrimPrefix(s.server.URL, "http") + "/btcusdt@depth"
+}
+
+func (s *websocketTestSuite) TestServeWithoutReconnect() {
+	messages := make(chan string, 10)
+	doneC, _, err := wsServe(&WsConfig{Endpoint: s.endpoint()}, func(message []byte) {
+		messages <- string(message)
+	}, func(err error) {})
+	s.Require().NoError(err)
+	<-doneC
+	s.Require().Len(messages, 1)
+	s.Equal("/btcusdt@depth", <-messages)
+}
+
+func (s *websocketTestSuite) TestServeWithReconnect() {
+	rc := NewWsReconnectConfig()
+	rc.MinBackoff = time.Millisecond
+	rc.MaxBackoff = 5 * time.Millisecond
+	var connected, disconnected, reconnected int
+	rc.OnConnected = func(endpoint string) {
+		connected++
+	}
+	rc.OnDisconnected = func(endpoint string, err error) {
+		disconnected++
+	}
+	rc.OnReconnected = func(endpoint string, attempts int) {
+		reconnected++
+	}
+	messages := make(chan string, 10)
+	doneC, stopC, err := wsServe(&WsConfig{Endpoint: s.endpoint(), Reconnect: rc}, func(message []byte) {
+		messages <- string(message)
+	}, func(err error) {})
+	s.Require().NoError(err)
+	for i := 0; i < 3; i++ {
+		select {
+		case m := <-messages:
+			s.Equal("/btcusdt@depth", m)
+		case <-time.After(time.Second):
+			s.FailNow("timed out waiting for message")
+		}
+	}
+	close(stopC)
+	<-doneC
+	s.Equal(1, connected)
+	s.GreaterOrEqual(disconnected, 2)
+	s.GreaterOrEqual(reconnected, 2)
+}
+
+func (s *websocketTestSuite) TestServeWithReconnectExhausted() {
+	rc := NewWsReconnectConfig()
+	rc.MinBackoff = time.Millisecond
+	rc.MaxBackoff = time.Millisecond
+	rc.MaxRetries = 2
+	var lastErr error
+	doneC, _, err := wsServe(&WsConfig{Endpoint: s.endpoint(), Reconnect: rc}, func(message []byte) {
+		s.server.CloseClientConnections()
+		s.server.Listener.Close()
+	}, func(err error) {
+		lastErr = err
+	})
+	s.Require().NoError(err)
+	select {
+	case <-doneC:
+	case <-time.After(time.Second):
+		s.FailNow("timed out waiting for stream to stop")
+	}
+	s.True(errors.Is(lastErr, ErrWsReconnectExhausted))
+}
+
+func (s *websocketTestSuite) TestServeWithMaxConnectionAge() {
+	hold := make(chan struct{})
+	defer close(hold)
+	s.server.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c, err := (&websocket.Upgrader{}).Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer c.Close()
+		c.WriteMessage(websocket.TextMessage, []byte("hello"))
+		<-hold
+	})
+	rc := NewWsReconnectConfig()
+	rc.MaxConnectionAge = 20 * time.Millisecond
+	disconnectC := make(chan error, 10)
+	rc.OnDisconnected = func(endpoint string, err error) {
+		disconnectC <- err
+	}
+	doneC, stopC, err := wsServe(&WsConfig{Endpoint: s.endpoint(), Reconnect: rc}, func(message []byte) {}, func(err error) {})
+	s.Require().NoError(err)
+	select {
+	case err := <-disconnectC:
+		s.True(errors.Is(err, ErrWsConnectionExpired))
+	case <-time.After(time.Second):
+		s.FailNow("timed out waiting for connection to expire")
+	}
+	close(stopC)
+	<-doneC
+}
+
+func (s *websocketTestSuite) TestServeLogs() {
+	rc := NewWsReconnectConfig()
+	rc.MinBackoff = time.Millisecond
+	rc.MaxBackoff = time.Millisecond
+	rc.MaxRetries = 1
+	buf := new(bytes.Buffer)
+	cfg := &WsConfig{Endpoint: s.endpoint(), Reconnect: rc, LogHandler: slog.NewTextHandler(buf, nil)}
+	doneC, _, err := wsServe(cfg, func(message []byte) {
+		s.server.CloseClientConnections()
+		s.server.Listener.Close()
+	}, func(err error) {})
+	s.Require().NoError(err)
+	select {
+	case <-doneC:
+	case <-time.After(time.Second):
+		s.FailNow("timed out waiting for stream to stop")
+	}
+	out := buf.String()
+	s.Contains(out, `level=INFO msg="websocket connected" stream=`+s.endpoint())
+	s.Contains(out, `level=WARN msg="websocket disconnected" stream=`+s.endpoint())
+	s.Contains(out, `level=WARN msg="websocket reconnection failed" stream=`+s.endpoint()+" attempts=1")
+	s.Contains(out, `level=ERROR msg="websocket reconnection exhausted" stream=`+s.endpoint()+" attempts=1")
+}
+
+func (s *websocketTestSuite) TestServeMetrics() {
+	rc := NewWsReconnectConfig()
+	rc.MinBackoff = time.Millisecond
+	rc.MaxBackoff = time.Millisecond
+	m := new(countMetrics)
+	messages := make(chan string, 10)
+	doneC, stopC, err := wsServe(&WsConfig{Endpoint: s.endpoint(), Reconnect: rc, Metrics: m}, func(message []byte) {
+		messages <- string(message)
+	}, func(err error) {})
+	s.Require().NoError(err)
+	for i := 0; i < 2; i++ {
+		select {
+		case <-messages:
+		case <-time.After(time.Second):
+			s.FailNow("timed out waiting for message")
+		}
+	}
+	close(stopC)
+	<-doneC
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s.GreaterOrEqual(m.messages["btcusdt@depth"], 2)
+	s.GreaterOrEqual(m.reconnects, 1)
+	s.Zero(m.lags)
+}
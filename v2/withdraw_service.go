@@ -3,6 +3,9 @@ package binance
 import (
 	"context"
 	"net/http"
+	"time"
+
+	"github.com/vv1zard/go-binance/v2/common"
 )
 
 // CreateWithdrawService submits a withdraw request.
@@ -210,6 +213,40 @@ func (s *ListWithdrawsService) Do(ctx context.Context) (res []*Withdraw, err err
 	return res, nil
 }
 
+// Iterate walk the withdrawals from StartTime up to EndTime in windows of 90
+// days with as many requests as needed, Limit sets the page size. The
+// withdrawals are returned oldest first.
+func (s *ListWithdrawsService) Iterate() *common.Iterator[*Withdraw] {
+	p := common.Pager[*Withdraw]{
+		Fetch: func(ctx context.Context, q common.PageQuery) ([]*Withdraw, error) {
+			page := &ListWithdrawsService{c: s.c, coin: s.coin, withdrawOrderId: s.withdrawOrderId, status: s.status,
+				startTime: &q.StartTime, endTime: &q.EndTime, offset: &q.Offset, limit: &q.Limit}
+			return page.Do(ctx)
+		},
+		Limit:     1000,
+		MaxWindow: 90 * 24 * time.Hour,
+		Time: func(withdraw *Withdraw) int64 {
+			t, _ := time.Parse(withdrawTimeLayout, withdraw.ApplyTime)
+			return t.UnixMilli()
+		},
+		Key:    func(withdraw *Withdraw) interface{} { return withdraw.ID },
+		Offset: true,
+	}
+	if s.limit != nil {
+		p.Limit = *s.limit
+	}
+	if s.startTime != nil {
+		p.StartTime = *s.startTime
+	}
+	if s.endTime != nil {
+		p.EndTime = *s.endTime
+	}
+	return p.Iterator()
+}
+
+// withdrawTimeLayout is the layout of Withdraw.ApplyTime, in UTC
+const withdrawTimeLayout = "2006-01-02 15:04:05"
+
 // Withdraw represents a single withdraw entry.
 type Withdraw struct {
 	Address         string `json:"address"`